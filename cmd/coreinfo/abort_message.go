@@ -0,0 +1,39 @@
+package coreinfo
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Markers delimiting the abort message printed by the abort message probe.
+const (
+	abortMessageBegin = "cbtoolbox-abort-message-begin"
+	abortMessageEnd   = "cbtoolbox-abort-message-end"
+)
+
+var abortMessageRegex = regexp.MustCompile(`(?s)` + abortMessageBegin + `\n(.*?)\n` + abortMessageEnd)
+
+// abortMessageArgs returns gdb arguments that print the message glibc
+// records before aborting, e.g. for a failed assert(), when the process
+// was terminated by SIGABRT. Returns nil for other signals.
+//
+// __abort_msg points to a struct abort_msg_s, an unsigned int size followed
+// by the message. glibc's symbol carries no type information without its
+// debug symbols, so the message is read at offset 4 through casts. When no
+// message was recorded the pointer is NULL, gdb reports an error and
+// nothing is printed between the markers.
+func abortMessageArgs(signal string) []string {
+	if signal != "SIGABRT" {
+		return nil
+	}
+	return []string{"-ex", `printf "` + abortMessageBegin + `\n%s\n` + abortMessageEnd + `\n", *(char **)&__abort_msg + 4`}
+}
+
+// extractAbortMessage returns the message printed by the abort message
+// probe, or "" if none was recorded or the probe did not run.
+func extractAbortMessage(gdbOutput string) string {
+	if match := abortMessageRegex.FindStringSubmatch(gdbOutput); len(match) > 1 {
+		return strings.TrimSpace(match[1])
+	}
+	return ""
+}
@@ -0,0 +1,48 @@
+package coreinfo
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestAbortMessageArgs validates that the abort message probe only runs for SIGABRT.
+func TestAbortMessageArgs(t *testing.T) {
+	if args := abortMessageArgs("SIGSEGV"); args != nil {
+		t.Errorf("expected no probe for SIGSEGV, got %v", args)
+	}
+	args := abortMessageArgs("SIGABRT")
+	if len(args) != 2 || args[0] != "-ex" || !strings.Contains(args[1], "__abort_msg") {
+		t.Errorf("unexpected probe for SIGABRT: %v", args)
+	}
+}
+
+// TestExtractAbortMessage validates extraction of the abort message from gdb output.
+func TestExtractAbortMessage(t *testing.T) {
+	tests := []struct {
+		name      string
+		gdbOutput string
+		expected  string
+	}{
+		{
+			name:      "assertion",
+			gdbOutput: "Program terminated with signal SIGABRT, Aborted.\n" + abortMessageBegin + "\npostgres: execMain.c:412: ExecutePlan: Assertion `estate != NULL' failed.\n\n" + abortMessageEnd + "\n",
+			expected:  "postgres: execMain.c:412: ExecutePlan: Assertion `estate != NULL' failed.",
+		},
+		{
+			name:      "no message recorded",
+			gdbOutput: "Program terminated with signal SIGABRT, Aborted.\nCannot access memory at address 0x4\n",
+		},
+		{
+			name:      "empty message",
+			gdbOutput: abortMessageBegin + "\n\n" + abortMessageEnd + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractAbortMessage(tt.gdbOutput); got != tt.expected {
+				t.Errorf("extractAbortMessage() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
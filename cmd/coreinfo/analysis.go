@@ -0,0 +1,342 @@
+package coreinfo
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// CoreAnalysis is the structured result of analyzing a single core file.
+// It is parsed from the gdb transcript and the core's FileInfo, and is the
+// input to every output renderer.
+//
+// DetectedVersion is the Cloudberry version recorded in the core's memory
+// and BinaryVersion the version of the binary gdb was given; they differ
+// when a core is analyzed against the wrong build. SymbolSource records
+// where gdb got symbols from: inline, companion, debuginfod or none.
+//
+// CommandLine is the command line of the crashed process as gdb reports
+// it, and ProcessArgv its arguments split as a shell would, so consumers
+// can pick out individual arguments such as the port or data directory.
+//
+// AbortMessage is the message glibc recorded before a SIGABRT, such as a
+// failed assertion; it is empty for other signals or when none was recorded.
+// LikelyCause is the first crashed-thread frame outside the C runtime and
+// Cloudberry's error reporting path, where a first responder should look
+// first; it is nil when there is no such frame.
+// KnownIssues lists the issues tracking crashes the core matches in the
+// known issues table.
+//
+// OpenFiles lists the file descriptors open at crash time. It is only
+// populated with --open-files and when the core's fd tables are readable.
+// MemoryMap lists the mappings of the crashed process with --memory-map,
+// and FaultRegion describes the one containing the faulting address.
+// Hostname is the host that generated the core, as recorded in its file name
+// by the kernel core_pattern %h specifier; it is empty when unknown.
+// BinaryOverride describes how the binary was substituted for an executable
+// recorded in the core that no longer exists; it is empty otherwise.
+// CrashedThreadFrames is the depth of the crashed thread's backtrace; it
+// exceeds len(CrashedThread) when the backtrace was truncated by --max-frames.
+// ThreadSummary counts the threads by state; it is nil without backtraces.
+// GDBSessionArgs are the gdb arguments that reopen the core with the
+// binary, symbols and settings of the analysis, for --interactive.
+// ExtraCommands maps each --gdb-eval command to the output gdb printed for it.
+// Warnings lists non-fatal issues that may limit the analysis.
+type CoreAnalysis struct {
+	CoreFile            string            `json:"core_file" yaml:"core_file"`
+	Hostname            string            `json:"hostname,omitempty" yaml:"hostname,omitempty"`
+	Binary              string            `json:"binary" yaml:"binary"`
+	Platform            string            `json:"platform" yaml:"platform"`
+	UserInfo            string            `json:"user_info" yaml:"user_info"`
+	ExecPath            string            `json:"exec_path" yaml:"exec_path"`
+	Signal              string            `json:"signal" yaml:"signal"`
+	FaultAddress        string            `json:"fault_address" yaml:"fault_address"`
+	FaultRegion         string            `json:"fault_region,omitempty" yaml:"fault_region,omitempty"`
+	AbortMessage        string            `json:"abort_message,omitempty" yaml:"abort_message,omitempty"`
+	LikelyCause         *StackFrame       `json:"likely_cause,omitempty" yaml:"likely_cause,omitempty"`
+	KnownIssues         []string          `json:"known_issues,omitempty" yaml:"known_issues,omitempty"`
+	ThreadID            string            `json:"thread_id" yaml:"thread_id"`
+	ProcessArgs         string            `json:"process_args" yaml:"process_args"`
+	CommandLine         string            `json:"command_line" yaml:"command_line"`
+	ProcessArgv         []string          `json:"process_argv,omitempty" yaml:"process_argv,omitempty"`
+	DetectedVersion     string            `json:"detected_version,omitempty" yaml:"detected_version,omitempty"`
+	BinaryVersion       string            `json:"binary_version,omitempty" yaml:"binary_version,omitempty"`
+	SymbolSource        string            `json:"symbol_source,omitempty" yaml:"symbol_source,omitempty"`
+	BinaryOverride      string            `json:"binary_override,omitempty" yaml:"binary_override,omitempty"`
+	CrashedThread       []StackFrame      `json:"crashed_thread,omitempty" yaml:"crashed_thread,omitempty"`
+	CrashedThreadFrames int               `json:"crashed_thread_frames,omitempty" yaml:"crashed_thread_frames,omitempty"`
+	ThreadSummary       *ThreadSummary    `json:"thread_summary,omitempty" yaml:"thread_summary,omitempty"`
+	OpenFiles           []string          `json:"open_files,omitempty" yaml:"open_files,omitempty"`
+	MemoryMap           []MemoryRegion    `json:"memory_map,omitempty" yaml:"memory_map,omitempty"`
+	ExtraCommands       map[string]string `json:"extra_commands,omitempty" yaml:"extra_commands,omitempty"`
+	Warnings            []string          `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+	GDBOutput           string            `json:"-" yaml:"-"`
+	GDBSessionArgs      []string          `json:"-" yaml:"-"`
+}
+
+// StackFrame is a single frame of a gdb backtrace.
+//
+// Args maps the frame's arguments to their values and Locals its local
+// variables printed by 'bt full'. Locals holding pretty-printed structs
+// spanning several lines are left out. Values the compiler did not keep
+// are "<optimized out>".
+type StackFrame struct {
+	Index    int               `json:"index" yaml:"index"`
+	Address  string            `json:"address,omitempty" yaml:"address,omitempty"`
+	Function string            `json:"function" yaml:"function"`
+	File     string            `json:"file,omitempty" yaml:"file,omitempty"`
+	Line     int               `json:"line,omitempty" yaml:"line,omitempty"`
+	Library  string            `json:"library,omitempty" yaml:"library,omitempty"`
+	Args     map[string]string `json:"args,omitempty" yaml:"args,omitempty"`
+	Locals   map[string]string `json:"locals,omitempty" yaml:"locals,omitempty"`
+	Raw      string            `json:"-" yaml:"-"`
+}
+
+// defaultMaxFrames is the default depth limit of parsed backtraces. Deep
+// recursion crashes can produce thousands of frames, which bloat reports
+// and slow parsing without adding insight.
+const defaultMaxFrames = 256
+
+// maxFrames is the --max-frames limit on parsed backtrace depth; 0 means unlimited.
+var maxFrames = defaultMaxFrames
+
+var (
+	binaryRegex       = regexp.MustCompile("Core was generated by `(.+): .+\\'")
+	signalRegex       = regexp.MustCompile(`Program terminated with signal (\w+), (.+)`)
+	faultAddrRegex    = regexp.MustCompile(`si_addr = ([^,]+)`)
+	threadIDRegex     = regexp.MustCompile(`Current thread is (\d+)`)
+	argsRegex         = regexp.MustCompile("Core was generated by `.*: ([^']+)\\'")
+	threadHeaderRegex = regexp.MustCompile(`^Thread (\d+) \(.*\):\s*$`)
+	frameIndexRegex   = regexp.MustCompile(`^\s*#(\d+)\s`)
+	frameRegex        = regexp.MustCompile(`^#(\d+)\s+(?:(0x[0-9a-fA-F]+) in )?(\S+)\s*\((.*?)\)(?:\s+at\s+(\S+):(\d+))?(?:\s+from\s+(\S+))?\s*$`)
+)
+
+// parseCoreAnalysis builds a CoreAnalysis from gdb output and the FileInfo
+// gathered during core file validation. It fails only when the originating
+// binary cannot be identified; other missing details are reported as "N/A".
+func parseCoreAnalysis(gdbOutput string, fileInfo *FileInfo, coreFile string) (*CoreAnalysis, error) {
+	analysis := &CoreAnalysis{
+		CoreFile:  coreFile,
+		GDBOutput: gdbOutput,
+	}
+
+	// Match and extract relevant information
+	if match := binaryRegex.FindStringSubmatch(gdbOutput); len(match) > 1 {
+		analysis.Binary = match[1]
+	} else {
+		return nil, fmt.Errorf("failed to extract binary information")
+	}
+
+	if match := signalRegex.FindStringSubmatch(gdbOutput); len(match) > 2 {
+		analysis.Signal = fmt.Sprintf("%s (%s)", match[1], match[2])
+	} else {
+		analysis.Signal = "Unknown signal"
+	}
+
+	if match := faultAddrRegex.FindStringSubmatch(gdbOutput); len(match) > 1 {
+		analysis.FaultAddress = match[1]
+	} else {
+		analysis.FaultAddress = "N/A"
+	}
+
+	if match := threadIDRegex.FindStringSubmatch(gdbOutput); len(match) > 1 {
+		analysis.ThreadID = match[1]
+	} else {
+		analysis.ThreadID = "N/A"
+	}
+
+	if match := argsRegex.FindStringSubmatch(gdbOutput); len(match) > 1 {
+		analysis.ProcessArgs = match[1]
+	} else {
+		analysis.ProcessArgs = "N/A"
+	}
+
+	if commandLine := extractCommandLine(gdbOutput); commandLine != "" {
+		analysis.CommandLine = commandLine
+		analysis.ProcessArgv = splitCommandLine(commandLine)
+	} else {
+		analysis.CommandLine = "N/A"
+	}
+
+	analysis.Platform = "unknown"
+	analysis.UserInfo = "unknown"
+	analysis.ExecPath = "unknown"
+	if fileInfo != nil {
+		analysis.Platform = fileInfo.Platform
+		analysis.UserInfo = fmt.Sprintf("uid=%s(%s), gid=%s(%s)",
+			fileInfo.RealUID, fileInfo.EffUID,
+			fileInfo.RealGID, fileInfo.EffGID)
+		analysis.ExecPath = fileInfo.ExecPath
+	}
+
+	threads, depths := parseBacktraces(gdbOutput)
+	crashedID := crashedThreadID(threads, analysis.ThreadID)
+	analysis.CrashedThread, analysis.CrashedThreadFrames = threads[crashedID], depths[crashedID]
+	analysis.ThreadSummary = summarizeThreads(threads, crashedID)
+	analysis.LikelyCause = likelyCause(analysis.CrashedThread)
+	analysis.DetectedVersion = extractDetectedVersion(gdbOutput)
+	analysis.AbortMessage = extractAbortMessage(gdbOutput)
+	analysis.OpenFiles = extractOpenFiles(gdbOutput)
+	analysis.MemoryMap = extractMemoryMap(gdbOutput)
+	analysis.FaultRegion = faultRegion(analysis.FaultAddress, analysis.MemoryMap)
+
+	return analysis, nil
+}
+
+// analysisWarnings returns the non-fatal issues found while analyzing a core
+// with binary: a version mismatch between core and binary, missing symbols,
+// a missing crashed-thread backtrace and --gdb-eval commands without output.
+func analysisWarnings(analysis *CoreAnalysis, binary string, evalCommands []string) []string {
+	var warnings []string
+	if warning := versionMismatchWarning(analysis, binary); warning != "" {
+		warnings = append(warnings, warning)
+	}
+	if analysis.SymbolSource == symbolSourceNone {
+		warnings = append(warnings, fmt.Sprintf("no debug symbols found for %s; backtraces may lack function names and source lines (see --debug-file)", binary))
+	}
+	if len(analysis.CrashedThread) == 0 {
+		warnings = append(warnings, "no backtrace found for the crashed thread")
+	} else if analysis.BinaryOverride != "" && !symbolsResolved(analysis.CrashedThread) {
+		warnings = append(warnings, fmt.Sprintf("symbols did not resolve after the binary override (%s); the binary may not match the core (see --binary)", analysis.BinaryOverride))
+	}
+	for _, command := range evalCommands {
+		if _, ok := analysis.ExtraCommands[command]; !ok {
+			warnings = append(warnings, fmt.Sprintf("no output captured for --gdb-eval %q", command))
+		}
+	}
+	return warnings
+}
+
+// symbolsResolved reports whether gdb resolved the function name of any frame.
+func symbolsResolved(frames []StackFrame) bool {
+	for _, frame := range frames {
+		if frame.Function != "??" {
+			return true
+		}
+	}
+	return false
+}
+
+// parseBacktraces splits the output of 'thread apply all bt' into frames per
+// gdb thread number. Frames that appear before any "Thread N" header (as with
+// a plain 'bt') are recorded under thread "".
+// Each backtrace is truncated to maxFrames frames unless maxFrames is 0; the
+// second map holds the full depth of each backtrace.
+func parseBacktraces(gdbOutput string) (map[string][]StackFrame, map[string]int) {
+	threads := make(map[string][]StackFrame)
+	depths := make(map[string]int)
+	current := ""
+	lastIndex := -1
+	skip := false
+	// Locals of the last parsed frame follow it as indented lines, and
+	// continuation lines of a multi-line local are skipped.
+	var frame *StackFrame
+	nesting := 0
+
+	scanner := bufio.NewScanner(strings.NewReader(gdbOutput))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if nesting > 0 {
+			nesting += braceDepth(line)
+			continue
+		}
+		if match := localRegex.FindStringSubmatch(line); match != nil && frame != nil {
+			if nesting = braceDepth(match[2]); nesting == 0 {
+				if frame.Locals == nil {
+					frame.Locals = make(map[string]string)
+				}
+				frame.Locals[match[1]] = match[2]
+			}
+			continue
+		}
+		frame = nil
+		if match := threadHeaderRegex.FindStringSubmatch(line); match != nil {
+			current = match[1]
+			lastIndex = -1
+			// Only the first backtrace of each thread is kept
+			skip = len(threads[current]) > 0
+			continue
+		}
+		if skip {
+			continue
+		}
+		// Frames beyond the limit are only counted, not parsed
+		if maxFrames > 0 && len(threads[current]) >= maxFrames {
+			if match := frameIndexRegex.FindStringSubmatch(line); match != nil {
+				if index, err := strconv.Atoi(match[1]); err == nil && index > lastIndex {
+					lastIndex = index
+					depths[current]++
+				}
+			}
+			continue
+		}
+		parsed, ok := parseStackFrame(line)
+		if !ok {
+			continue
+		}
+		// A backtrace restarts at #0, so a second 'bt' without a thread
+		// header must not be appended to the first.
+		if parsed.Index <= lastIndex {
+			continue
+		}
+		lastIndex = parsed.Index
+		threads[current] = append(threads[current], parsed)
+		depths[current]++
+		frame = &threads[current][len(threads[current])-1]
+	}
+	return threads, depths
+}
+
+// crashedThreadID returns the key of the crashed thread's backtrace among
+// threads, which is the thread gdb reports as current. When that thread
+// cannot be found, the unlabeled or lowest numbered backtrace is used.
+// Returns "" if there are no backtraces.
+func crashedThreadID(threads map[string][]StackFrame, threadID string) string {
+	if _, ok := threads[threadID]; ok {
+		return threadID
+	}
+	if _, ok := threads[""]; ok {
+		return ""
+	}
+
+	// Fall back to the lowest numbered thread
+	lowest := -1
+	for id := range threads {
+		if n, err := strconv.Atoi(id); err == nil && (lowest == -1 || n < lowest) {
+			lowest = n
+		}
+	}
+	if lowest == -1 {
+		return ""
+	}
+	return strconv.Itoa(lowest)
+}
+
+// parseStackFrame parses a single gdb backtrace line such as
+//
+//	#1  0x00000000004005a4 in ExecProcNode (node=0x0) at execProcnode.c:412
+//
+// Returns false if the line is not a backtrace frame.
+func parseStackFrame(line string) (StackFrame, bool) {
+	match := frameRegex.FindStringSubmatch(strings.TrimSpace(line))
+	if match == nil {
+		return StackFrame{}, false
+	}
+	index, _ := strconv.Atoi(match[1])
+	frame := StackFrame{
+		Index:    index,
+		Address:  match[2],
+		Function: match[3],
+		File:     match[5],
+		Library:  match[7],
+		Args:     parseFrameArgs(match[4]),
+		Raw:      strings.TrimSpace(line),
+	}
+	if match[6] != "" {
+		frame.Line, _ = strconv.Atoi(match[6])
+	}
+	return frame, true
+}
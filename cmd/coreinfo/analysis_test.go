@@ -0,0 +1,315 @@
+package coreinfo
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// sampleGDBOutput is a trimmed gdb transcript of a postgres backend that
+// crashed with SIGSEGV in thread 1 while thread 2 was idle.
+const sampleGDBOutput = `[New LWP 4242]
+[New LWP 4243]
+Core was generated by ` + "`" + `postgres: 7000, gpadmin postgres [local] con12 cmd3 SELECT'.
+Program terminated with signal SIGSEGV, Segmentation fault.
+#0  0x00000000004005a4 in ExecProcNode (node=0x0) at execProcnode.c:412
+412		result = node->ExecProcNode(node);
+[Current thread is 1 (Thread 0x7f2a1b2c3d40 (LWP 4242))]
+
+
+======================================================================
+=== Thread Backtraces
+======================================================================
+
+Thread 2 (Thread 0x7f2a1a000700 (LWP 4243)):
+#0  0x00007f2a19e3a9cd in epoll_wait () from /lib64/libc.so.6
+No symbol table info available.
+#1  0x0000000000a1b2c3 in WaitEventSetWait (set=0x1, timeout=-1) at latch.c:1200
+        rc = <optimized out>
+
+Thread 1 (Thread 0x7f2a1b2c3d40 (LWP 4242)):
+#0  0x00000000004005a4 in ExecProcNode (node=0x0) at execProcnode.c:412
+        result = <optimized out>
+#1  ExecutePlan (estate=0x2b3c4d0, planstate=0x0) at execMain.c:1632
+        slot = 0x0
+#2  0x00000000004a1b2c in standard_ExecutorRun (queryDesc=0x2b3c000, direction=ForwardScanDirection, count=0) at execMain.c:350
+#3  0x00000000004b2c3d in PostgresMain (argc=1, argv=0x7ffd) at postgres.c:4512
+#4  0x00000000004c3d4e in main (argc=5, argv=0x7ffd1234) at main.c:240
+
+$1 = {si_signo = 11, si_errno = 0, si_code = 1, _sifields = {_sigfault = {si_addr = 0x0, _addr_lsb = 0}}}
+`
+
+// TestParseCoreAnalysis validates extraction of summary fields and the crashed thread.
+func TestParseCoreAnalysis(t *testing.T) {
+	info := &FileInfo{Platform: "x86_64", RealUID: "1000", EffUID: "1000", RealGID: "1000", EffGID: "1000", ExecPath: "/usr/local/cloudberry-db/bin/postgres"}
+
+	analysis, err := parseCoreAnalysis(sampleGDBOutput, info, "/var/crash/core.4242")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if analysis.Binary != "postgres" {
+		t.Errorf("Expected binary 'postgres', got %q", analysis.Binary)
+	}
+	if analysis.Signal != "SIGSEGV (Segmentation fault.)" {
+		t.Errorf("Unexpected signal: %q", analysis.Signal)
+	}
+	if analysis.FaultAddress != "0x0" {
+		t.Errorf("Expected fault address 0x0, got %q", analysis.FaultAddress)
+	}
+	if analysis.ThreadID != "1" {
+		t.Errorf("Expected thread ID 1, got %q", analysis.ThreadID)
+	}
+	if analysis.ExecPath != info.ExecPath {
+		t.Errorf("Expected exec path %q, got %q", info.ExecPath, analysis.ExecPath)
+	}
+
+	if len(analysis.CrashedThread) != 5 {
+		t.Fatalf("Expected 5 frames in crashed thread, got %d: %+v", len(analysis.CrashedThread), analysis.CrashedThread)
+	}
+	top := analysis.CrashedThread[0]
+	if top.Function != "ExecProcNode" || top.File != "execProcnode.c" || top.Line != 412 || top.Address != "0x00000000004005a4" {
+		t.Errorf("Unexpected top frame: %+v", top)
+	}
+	if inlined := analysis.CrashedThread[1]; inlined.Function != "ExecutePlan" || inlined.Address != "" {
+		t.Errorf("Unexpected frame without address: %+v", inlined)
+	}
+}
+
+// TestParseCoreAnalysisMissingBinary validates that an unrecognized transcript is rejected.
+func TestParseCoreAnalysisMissingBinary(t *testing.T) {
+	if _, err := parseCoreAnalysis("No core file now.", nil, "core"); err == nil {
+		t.Error("Expected error when binary information is missing")
+	}
+}
+
+// TestParseStackFrame validates parsing of individual backtrace lines.
+func TestParseStackFrame(t *testing.T) {
+	frame, ok := parseStackFrame("#0  0x00007f2a19e3a9cd in epoll_wait () from /lib64/libc.so.6")
+	if !ok || frame.Function != "epoll_wait" || frame.Library != "/lib64/libc.so.6" {
+		t.Errorf("Unexpected library frame: %+v", frame)
+	}
+
+	frame, ok = parseStackFrame("#2  0x0000000000000000 in ?? ()")
+	if !ok || frame.Function != "??" {
+		t.Errorf("Unexpected unresolved frame: %+v", frame)
+	}
+
+	if _, ok := parseStackFrame("        result = <optimized out>"); ok {
+		t.Error("Expected local variable line not to parse as a frame")
+	}
+}
+
+// TestRenderMarkdown validates the Markdown report layout.
+func TestRenderMarkdown(t *testing.T) {
+	analysis, err := parseCoreAnalysis(sampleGDBOutput, nil, "/var/crash/core.4242")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	report := renderMarkdown(analysis, false)
+	for _, want := range []string{
+		"| Field | Value |",
+		"| Signal | SIGSEGV (Segmentation fault.) |",
+		"<details>",
+		"<summary>Crashed thread 1 backtrace (5 frames)</summary>",
+		"#0  0x00000000004005a4 in ExecProcNode (node=0x0) at execProcnode.c:412",
+		"</details>",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("Expected markdown to contain %q, got:\n%s", want, report)
+		}
+	}
+	if strings.Contains(report, "### GDB Output") {
+		t.Error("Expected raw gdb output to be omitted without includeRaw")
+	}
+
+	report = renderMarkdown(analysis, true)
+	if !strings.Contains(report, "### GDB Output\n\n```text\n[New LWP 4242]") {
+		t.Errorf("Expected fenced raw gdb output, got:\n%s", report)
+	}
+}
+
+// TestRenderMarkdownEscaping validates escaping of table cells and code fences.
+func TestRenderMarkdownEscaping(t *testing.T) {
+	analysis := &CoreAnalysis{CoreFile: "core", ProcessArgs: "a | b", GDBOutput: "```inner```"}
+
+	report := renderMarkdown(analysis, true)
+	if !strings.Contains(report, `| Process Args | a \| b |`) {
+		t.Errorf("Expected pipe to be escaped, got:\n%s", report)
+	}
+	if !strings.Contains(report, "````text\n```inner```\n````") {
+		t.Errorf("Expected a longer fence around backticks, got:\n%s", report)
+	}
+}
+
+// TestValidateFormat tests format validation for supported and unsupported formats.
+func TestValidateFormat(t *testing.T) {
+	for _, format := range []string{"text", "markdown", "json"} {
+		if err := validateFormat(format); err != nil {
+			t.Errorf("Unexpected error for valid format %q: %v", format, err)
+		}
+	}
+	if err := validateFormat("html"); err == nil {
+		t.Error("Expected error for invalid format")
+	}
+}
+
+// TestParseFormats validates splitting and validation of comma-separated formats.
+func TestParseFormats(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected []string
+		wantErr  bool
+	}{
+		{"text", []string{"text"}, false},
+		{"text,json", []string{"text", "json"}, false},
+		{" markdown , json ", []string{"markdown", "json"}, false},
+		{"json,text,json", []string{"json", "text"}, false},
+		{"text,html", nil, true},
+		{"text,", nil, true},
+	}
+
+	for _, tc := range testCases {
+		formats, err := parseFormats(tc.value)
+		if tc.wantErr {
+			if !errors.Is(err, ErrInvalidFormat) {
+				t.Errorf("parseFormats(%q): expected ErrInvalidFormat, got %v", tc.value, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseFormats(%q): unexpected error: %v", tc.value, err)
+			continue
+		}
+		if !reflect.DeepEqual(formats, tc.expected) {
+			t.Errorf("parseFormats(%q) = %v, expected %v", tc.value, formats, tc.expected)
+		}
+	}
+}
+
+// TestDetectedVersion validates extraction of the version probe and the mismatch rule.
+func TestDetectedVersion(t *testing.T) {
+	output := sampleGDBOutput + versionMarker + ` $1 = 0x2f1c0a0 "1.6.0 build 1"` + "\n"
+
+	analysis, err := parseCoreAnalysis(output, nil, "core.4242")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if analysis.DetectedVersion != "1.6.0 build 1" {
+		t.Errorf("Expected detected version '1.6.0 build 1', got %q", analysis.DetectedVersion)
+	}
+
+	if version := extractDetectedVersion(versionMarker + `$1 = 0x2f1c0a0 "1.6.0 build 1"`); version != "1.6.0 build 1" {
+		t.Errorf("Expected the version without a space after the marker, got %q", version)
+	}
+
+	missing := sampleGDBOutput + versionMarker + ` No symbol "gp_server_version_string" in current context.` + "\n"
+	if version := extractDetectedVersion(missing); version != "" {
+		t.Errorf("Expected no detected version without symbols, got %q", version)
+	}
+
+	testCases := []struct {
+		detected, binary string
+		match            bool
+	}{
+		{"1.6.0 build 1", "postgres (Cloudberry Database) 1.6.0 build 1", true},
+		{"1.6.0 build 1", "postgres (Cloudberry Database) 2.0.0 build 3", false},
+		{"", "postgres (Cloudberry Database) 2.0.0 build 3", true},
+		{"1.6.0 build 1", "", true},
+	}
+	for _, tc := range testCases {
+		if got := versionsMatch(tc.detected, tc.binary); got != tc.match {
+			t.Errorf("versionsMatch(%q, %q) = %v, want %v", tc.detected, tc.binary, got, tc.match)
+		}
+	}
+}
+
+// TestAnalysisWarnings validates that non-fatal issues are recorded as
+// warnings and rendered in both output formats.
+func TestAnalysisWarnings(t *testing.T) {
+	analysis, err := parseCoreAnalysis(sampleGDBOutput, nil, "core.4242")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	analysis.SymbolSource = symbolSourceInline
+	if warnings := analysisWarnings(analysis, "/usr/local/cloudberry/bin/postgres", nil); len(warnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", warnings)
+	}
+
+	analysis.DetectedVersion = "1.6.0 build 1"
+	analysis.BinaryVersion = "postgres (Cloudberry Database) 2.0.0 build 3"
+	analysis.SymbolSource = symbolSourceNone
+	analysis.CrashedThread = nil
+	analysis.Warnings = analysisWarnings(analysis, "/usr/local/cloudberry/bin/postgres", []string{"p MyProcPid"})
+
+	expected := []string{"generated by version", "no debug symbols", "no backtrace", `--gdb-eval "p MyProcPid"`}
+	if len(analysis.Warnings) != len(expected) {
+		t.Fatalf("Expected %d warnings, got %v", len(expected), analysis.Warnings)
+	}
+	for i, want := range expected {
+		if !strings.Contains(analysis.Warnings[i], want) {
+			t.Errorf("Expected warning %d to contain %q, got %q", i, want, analysis.Warnings[i])
+		}
+	}
+
+	if text := renderText(analysis); !strings.Contains(text, "- Warnings:\n  - core core.4242 was generated by version") {
+		t.Errorf("Expected warnings in text summary, got:\n%s", text)
+	}
+	if md := renderMarkdown(analysis, false); !strings.Contains(md, "### Warnings\n\n- core core.4242") {
+		t.Errorf("Expected warnings in markdown, got:\n%s", md)
+	}
+}
+
+// TestMaxFrames validates truncation of deep backtraces and preservation of their depth.
+func TestMaxFrames(t *testing.T) {
+	defer func() { maxFrames = defaultMaxFrames }()
+
+	var b strings.Builder
+	b.WriteString("Core was generated by `postgres: 7000, gpadmin postgres [local] con12 cmd3 SELECT'.\n")
+	b.WriteString("Program terminated with signal SIGSEGV, Segmentation fault.\n")
+	b.WriteString("[Current thread is 1 (Thread 0x7f2a1b2c3d40 (LWP 4242))]\n")
+	b.WriteString("Thread 1 (Thread 0x7f2a1b2c3d40 (LWP 4242)):\n")
+	for i := 0; i < 300; i++ {
+		fmt.Fprintf(&b, "#%d  0x00000000004005a4 in recurse%d (depth=%d) at recurse.c:10\n", i, i, i)
+	}
+	output := b.String()
+
+	tests := []struct {
+		limit, expected int
+	}{
+		{defaultMaxFrames, defaultMaxFrames},
+		{0, 300},
+		{2, 2},
+	}
+	for _, tt := range tests {
+		maxFrames = tt.limit
+		analysis, err := parseCoreAnalysis(output, nil, "core.4242")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(analysis.CrashedThread) != tt.expected || analysis.CrashedThreadFrames != 300 {
+			t.Errorf("max frames %d: expected %d of 300 frames, got %d of %d",
+				tt.limit, tt.expected, len(analysis.CrashedThread), analysis.CrashedThreadFrames)
+		}
+	}
+
+	// The signature and reports only use the retained frames
+	maxFrames = 2
+	analysis, err := parseCoreAnalysis(output, nil, "core.4242")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if signature, err := crashSignature(analysis); err != nil || signature != "SIGSEGV:recurse0>recurse1" {
+		t.Errorf("Unexpected signature %q: %v", signature, err)
+	}
+	report := renderMarkdown(analysis, false)
+	if !strings.Contains(report, "backtrace (2 of 300 frames)") || !strings.Contains(report, "... (truncated)") {
+		t.Errorf("Expected truncation to be shown in markdown, got:\n%s", report)
+	}
+	if summary := textSummary(analysis); !strings.Contains(summary, "Crashed Thread Frames: 300 ... (truncated, 2 shown)") {
+		t.Errorf("Expected truncation to be shown in the text summary, got:\n%s", summary)
+	}
+}
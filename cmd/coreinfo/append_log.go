@@ -0,0 +1,101 @@
+package coreinfo
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// defaultAppendLogMaxMB is the size in MiB at which --append-log rotates.
+const defaultAppendLogMaxMB = 100
+
+var (
+	// appendLogPath is the file each analysis is appended to as one NDJSON line
+	appendLogPath string
+
+	// appendLogMaxMB is the size in MiB beyond which the log is rotated; 0 disables rotation
+	appendLogMaxMB = defaultAppendLogMaxMB
+)
+
+// analysisLog appends analyses to an NDJSON file, rotating it to
+// "<path>.1" when it would grow beyond maxBytes. It is safe for
+// concurrent use.
+type analysisLog struct {
+	mu       sync.Mutex
+	path     string
+	maxBytes int64
+	file     *os.File
+	size     int64
+}
+
+// openAnalysisLog opens the log at path for appending, creating it if it
+// does not exist. A maxBytes of 0 disables rotation.
+func openAnalysisLog(path string, maxBytes int64) (*analysisLog, error) {
+	l := &analysisLog{path: path, maxBytes: maxBytes}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// open (re)opens the log file and records its current size.
+func (l *analysisLog) open() error {
+	file, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("append log: failed to open %s: %w", l.path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("append log: failed to stat %s: %w", l.path, err)
+	}
+	l.file, l.size = file, info.Size()
+	return nil
+}
+
+// Append writes analysis as a single JSON line and syncs it to disk, so
+// each line is complete even if the process is killed afterwards.
+func (l *analysisLog) Append(analysis *CoreAnalysis) error {
+	line, err := json.Marshal(analysis)
+	if err != nil {
+		return fmt.Errorf("append log: failed to marshal analysis of %s: %w", analysis.CoreFile, err)
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.maxBytes > 0 && l.size > 0 && l.size+int64(len(line)) > l.maxBytes {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := l.file.Write(line)
+	l.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("append log: failed to write %s: %w", l.path, err)
+	}
+	if err := l.file.Sync(); err != nil {
+		return fmt.Errorf("append log: failed to sync %s: %w", l.path, err)
+	}
+	return nil
+}
+
+// rotate renames the log to "<path>.1", replacing an earlier rotation,
+// and starts a new log. The caller must hold l.mu.
+func (l *analysisLog) rotate() error {
+	if err := l.file.Close(); err != nil {
+		return fmt.Errorf("append log: failed to close %s: %w", l.path, err)
+	}
+	if err := os.Rename(l.path, l.path+".1"); err != nil {
+		return fmt.Errorf("append log: failed to rotate %s: %w", l.path, err)
+	}
+	return l.open()
+}
+
+// Close closes the log file.
+func (l *analysisLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
@@ -0,0 +1,94 @@
+package coreinfo
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// readNDJSON returns the analyses in an NDJSON file, failing on any line
+// that is not a complete JSON object.
+func readNDJSON(t *testing.T, path string) []CoreAnalysis {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", path, err)
+	}
+	defer file.Close()
+
+	var analyses []CoreAnalysis
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var analysis CoreAnalysis
+		if err := json.Unmarshal(scanner.Bytes(), &analysis); err != nil {
+			t.Fatalf("invalid NDJSON line %q: %v", scanner.Text(), err)
+		}
+		analyses = append(analyses, analysis)
+	}
+	return analyses
+}
+
+// TestAnalysisLogConcurrentAppends validates that concurrent appends each
+// produce one complete line, and that an existing log is appended to.
+func TestAnalysisLogConcurrentAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "crashes.ndjson")
+	if err := os.WriteFile(path, []byte(`{"core_file":"existing"}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	log, err := openAnalysisLog(path, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := log.Append(&CoreAnalysis{CoreFile: fmt.Sprintf("core.%d", i), ProcessArgv: []string{"postgres"}}); err != nil {
+				t.Errorf("Append() error = %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if err := log.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	analyses := readNDJSON(t, path)
+	if len(analyses) != 21 || analyses[0].CoreFile != "existing" {
+		t.Errorf("expected the existing line and 20 appended lines, got %d", len(analyses))
+	}
+}
+
+// TestAnalysisLogRotation validates that the log is rotated to <path>.1
+// before it would exceed its maximum size.
+func TestAnalysisLogRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "crashes.ndjson")
+	line, _ := json.Marshal(&CoreAnalysis{CoreFile: "core.0"})
+
+	// Room for two lines per file
+	log, err := openAnalysisLog(path, int64(2*(len(line)+1)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer log.Close()
+	for i := 0; i < 5; i++ {
+		if err := log.Append(&CoreAnalysis{CoreFile: fmt.Sprintf("core.%d", i)}); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+
+	current := readNDJSON(t, path)
+	rotated := readNDJSON(t, path+".1")
+	if len(current) != 1 || current[0].CoreFile != "core.4" {
+		t.Errorf("expected core.4 in the current log, got %+v", current)
+	}
+	if len(rotated) != 2 || rotated[0].CoreFile != "core.2" || rotated[1].CoreFile != "core.3" {
+		t.Errorf("expected core.2 and core.3 in the rotated log, got %+v", rotated)
+	}
+}
@@ -0,0 +1,127 @@
+package coreinfo
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"debug/elf"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// fromArchive is the --from-archive flag: a tar archive, optionally gzip
+// compressed, holding the cores to analyze and optionally the binary.
+var fromArchive string
+
+// archiveBinary is the postgres binary taken from the --from-archive
+// bundle, or "" when none is used. It comes from an untrusted archive, so
+// gdb reads it but it is never executed.
+var archiveBinary string
+
+// archiveBinaryName is the file name of the binary detected in an archive.
+const archiveBinaryName = "postgres"
+
+// gzipMagic starts every gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// extractArchive extracts the regular files of a tar or tar.gz archive
+// below dest and returns the directories that received files, for core
+// detection, and the postgres binary found in the archive ("" if none).
+// Entry paths are kept inside dest; links and other special entries are
+// skipped, as are entries whose path was already extracted, which are
+// reported to w. When the archive holds several postgres binaries, the
+// first is returned and the others are reported to w.
+func extractArchive(w io.Writer, archive, dest string) ([]string, string, error) {
+	f, err := os.Open(archive)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open archive: %w", permissionError(archive, err))
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	var stream io.Reader = r
+	if magic, err := r.Peek(len(gzipMagic)); err == nil && bytes.Equal(magic, gzipMagic) {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read archive %s: %v", archive, err)
+		}
+		defer gz.Close()
+		stream = gz
+	}
+
+	dirs := make(map[string]bool)
+	var binary string
+	tr := tar.NewReader(stream)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read archive %s: %v", archive, err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		path := filepath.Join(dest, filepath.Clean("/"+header.Name))
+		if _, err := os.Lstat(path); err == nil {
+			fmt.Fprintf(w, "Warning: archive %s holds %s more than once; keeping the first\n", archive, header.Name)
+			continue
+		}
+		if err := extractArchiveFile(tr, path); err != nil {
+			return nil, "", fmt.Errorf("failed to extract %s from archive %s: %v", header.Name, archive, err)
+		}
+		dirs[filepath.Dir(path)] = true
+
+		if filepath.Base(path) == archiveBinaryName && isExecutableELF(path) {
+			if binary == "" {
+				binary = path
+			} else {
+				fmt.Fprintf(w, "Warning: archive %s holds several postgres binaries; using %s (see --binary)\n", archive, binary)
+			}
+		}
+	}
+
+	if len(dirs) == 0 {
+		return nil, "", fmt.Errorf("%w: archive %s contains no files", ErrNoCoreFiles, archive)
+	}
+	sorted := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		sorted = append(sorted, dir)
+	}
+	sort.Strings(sorted)
+	return sorted, binary, nil
+}
+
+// extractArchiveFile writes the current archive entry to path.
+func extractArchiveFile(r io.Reader, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// isExecutableELF reports whether path is an ELF executable or
+// position-independent executable.
+func isExecutableELF(path string) bool {
+	f, err := elf.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	return f.Type == elf.ET_EXEC || f.Type == elf.ET_DYN
+}
@@ -0,0 +1,203 @@
+package coreinfo
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTestArchive writes a tar archive with the given entries, gzip
+// compressed when compress is set. Entries with a "->" value are symlinks.
+func writeTestArchive(t *testing.T, path string, compress bool, names []string, contents map[string][]byte) {
+	t.Helper()
+	var buf bytes.Buffer
+	var out io.Writer = &buf
+	var gz *gzip.Writer
+	if compress {
+		gz = gzip.NewWriter(&buf)
+		out = gz
+	}
+	tw := tar.NewWriter(out)
+	for _, name := range names {
+		content := contents[name]
+		header := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if target, ok := strings.CutPrefix(string(content), "->"); ok {
+			header = &tar.Header{Name: name, Linkname: target, Typeflag: tar.TypeSymlink}
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if header.Typeflag == tar.TypeReg {
+			if _, err := tw.Write(content); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestExtractArchive validates extraction of tar and tar.gz archives,
+// binary detection and that entries cannot escape the destination.
+func TestExtractArchive(t *testing.T) {
+	executable, err := os.Executable()
+	if err != nil {
+		t.Skip("test executable not available")
+	}
+	elfBinary, err := os.ReadFile(executable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{"bundle/core.sdw1.12345", "bundle/bin/postgres", "bundle/logs/postgres", "../../escape", "bundle/link"}
+	contents := map[string][]byte{
+		"bundle/core.sdw1.12345": []byte("core"),
+		"bundle/bin/postgres":    elfBinary,
+		"bundle/logs/postgres":   []byte("not a binary"),
+		"../../escape":           []byte("escape"),
+		"bundle/link":            []byte("->/etc/passwd"),
+	}
+
+	for _, compress := range []bool{false, true} {
+		archive := filepath.Join(t.TempDir(), "bundle.tar")
+		writeTestArchive(t, archive, compress, names, contents)
+		dest := t.TempDir()
+
+		var warnings bytes.Buffer
+		dirs, binary, err := extractArchive(&warnings, archive, dest)
+		if err != nil {
+			t.Fatalf("compress=%v: unexpected error: %v", compress, err)
+		}
+		expectedDirs := []string{dest, filepath.Join(dest, "bundle"), filepath.Join(dest, "bundle", "bin"), filepath.Join(dest, "bundle", "logs")}
+		if strings.Join(dirs, ",") != strings.Join(expectedDirs, ",") {
+			t.Errorf("compress=%v: dirs = %q, expected %q", compress, dirs, expectedDirs)
+		}
+		if binary != filepath.Join(dest, "bundle", "bin", "postgres") {
+			t.Errorf("compress=%v: binary = %q", compress, binary)
+		}
+		if warnings.Len() > 0 {
+			t.Errorf("compress=%v: unexpected warnings %q", compress, warnings.String())
+		}
+		if content, err := os.ReadFile(filepath.Join(dest, "escape")); err != nil || string(content) != "escape" {
+			t.Errorf("compress=%v: expected ../../escape inside the destination, got %q, %v", compress, content, err)
+		}
+		if _, err := os.Lstat(filepath.Join(dest, "bundle", "link")); err == nil {
+			t.Errorf("compress=%v: expected the symlink to be skipped", compress)
+		}
+	}
+}
+
+// TestExtractArchiveDuplicates validates that an entry extracted to the
+// path of an earlier entry does not replace it.
+func TestExtractArchiveDuplicates(t *testing.T) {
+	executable, err := os.Executable()
+	if err != nil {
+		t.Skip("test executable not available")
+	}
+	elfBinary, err := os.ReadFile(executable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{"bundle/bin/postgres", "./bundle//bin/postgres"}
+	contents := map[string][]byte{
+		"bundle/bin/postgres":    elfBinary,
+		"./bundle//bin/postgres": []byte("not a binary"),
+	}
+	archive := filepath.Join(t.TempDir(), "bundle.tar")
+	writeTestArchive(t, archive, false, names, contents)
+	dest := t.TempDir()
+
+	var warnings bytes.Buffer
+	_, binary, err := extractArchive(&warnings, archive, dest)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if binary != filepath.Join(dest, "bundle", "bin", "postgres") {
+		t.Errorf("binary = %q", binary)
+	}
+	if content, err := os.ReadFile(binary); err != nil || !bytes.Equal(content, elfBinary) {
+		t.Errorf("expected the first postgres to be kept, got %d bytes, %v", len(content), err)
+	}
+	if !strings.Contains(warnings.String(), "more than once") {
+		t.Errorf("expected a warning for the duplicate entry, got %q", warnings.String())
+	}
+}
+
+// TestArchiveBinaryNotRun validates that a binary taken from an archive is
+// given to gdb but never executed, while a --binary is still asked for its
+// version.
+func TestArchiveBinaryNotRun(t *testing.T) {
+	t.Cleanup(func() { cleanupGDBFiles(io.Discard) })
+	defer func() { binaryPath, archiveBinary = "", "" }()
+
+	dir := t.TempDir()
+	coreFile := filepath.Join(dir, "core.1")
+	if err := os.WriteFile(coreFile, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	marker := filepath.Join(dir, "ran")
+	binary := filepath.Join(dir, "postgres")
+	if err := os.WriteFile(binary, []byte("#!/bin/sh\ntouch '"+marker+"'\necho 'postgres (Apache Cloudberry) 1.6.0'\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	fakeGDB(t, "Core was generated by `postgres: 7000, gpadmin postgres [local] con12 cmd5 SELECT'.\n"+
+		"Program terminated with signal SIGSEGV, Segmentation fault.\n"+
+		loadProbeMarker+"\n$1 = (void (*)()) 0x4005a4 <ExecProcNode+20>\n", 0)
+
+	binaryPath, archiveBinary = binary, binary
+	analysis, _, err := analyzeCore(coreFile, nil, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Fatal("expected the archive binary not to be executed")
+	}
+	if analysis.BinaryVersion != "" {
+		t.Errorf("expected no binary version for an archive binary, got %q", analysis.BinaryVersion)
+	}
+
+	archiveBinary = ""
+	if analysis, _, err = analyzeCore(coreFile, nil, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if analysis.BinaryVersion == "" {
+		t.Error("expected the version of a --binary to be read")
+	}
+}
+
+// TestExtractArchiveErrors validates the errors for empty and malformed archives.
+func TestExtractArchiveErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	empty := filepath.Join(dir, "empty.tar")
+	writeTestArchive(t, empty, false, nil, nil)
+	if _, _, err := extractArchive(&bytes.Buffer{}, empty, t.TempDir()); !errors.Is(err, ErrNoCoreFiles) {
+		t.Errorf("Expected ErrNoCoreFiles for an empty archive, got %v", err)
+	}
+
+	malformed := filepath.Join(dir, "malformed.tar.gz")
+	if err := os.WriteFile(malformed, append([]byte{0x1f, 0x8b}, "garbage"...), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := extractArchive(&bytes.Buffer{}, malformed, t.TempDir()); err == nil {
+		t.Errorf("Expected an error for a malformed archive")
+	}
+
+	if _, _, err := extractArchive(&bytes.Buffer{}, filepath.Join(dir, "missing.tar"), t.TempDir()); err == nil {
+		t.Errorf("Expected an error for a missing archive")
+	}
+}
@@ -0,0 +1,63 @@
+package coreinfo
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Values of the --color flag.
+const (
+	colorAuto   = "auto"
+	colorAlways = "always"
+	colorNever  = "never"
+)
+
+// colorReset ends an ANSI color sequence.
+const colorReset = "\x1b[0m"
+
+// colorMode is the --color setting of PASS/WARN/FAIL tables.
+var colorMode = colorAuto
+
+// severityColors maps each table status to its ANSI color: green, yellow
+// and red. All sequences have the same length, so colored columns stay
+// aligned in a tabwriter table.
+var severityColors = map[string]string{
+	"PASS": "\x1b[32m",
+	"WARN": "\x1b[33m",
+	"FAIL": "\x1b[31m",
+}
+
+// validateColorMode checks the --color flag.
+func validateColorMode() error {
+	switch colorMode {
+	case colorAuto, colorAlways, colorNever:
+		return nil
+	}
+	return fmt.Errorf("--color must be %s, %s or %s, got %q", colorAuto, colorAlways, colorNever, colorMode)
+}
+
+// useColor reports whether status tables written to w are colored. In auto
+// mode, only terminals are colored, and NO_COLOR or TERM=dumb disables it.
+func useColor(w io.Writer) bool {
+	switch colorMode {
+	case colorAlways:
+		return true
+	case colorNever:
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	return ok && isTerminal(f)
+}
+
+// colorSeverity colors a PASS, WARN or FAIL status when color is set.
+// Other statuses are returned unchanged.
+func colorSeverity(status string, color bool) string {
+	if code, ok := severityColors[status]; ok && color {
+		return code + status + colorReset
+	}
+	return status
+}
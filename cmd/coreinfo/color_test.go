@@ -0,0 +1,82 @@
+package coreinfo
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestColorSeverity validates coloring of table statuses by severity.
+func TestColorSeverity(t *testing.T) {
+	tests := []struct {
+		status   string
+		color    bool
+		expected string
+	}{
+		{status: "PASS", color: true, expected: "\x1b[32mPASS\x1b[0m"},
+		{status: "WARN", color: true, expected: "\x1b[33mWARN\x1b[0m"},
+		{status: "FAIL", color: true, expected: "\x1b[31mFAIL\x1b[0m"},
+		{status: "FAIL", color: false, expected: "FAIL"},
+		{status: "SKIP", color: true, expected: "SKIP"},
+	}
+
+	for _, tt := range tests {
+		if got := colorSeverity(tt.status, tt.color); got != tt.expected {
+			t.Errorf("colorSeverity(%q, %v) = %q, expected %q", tt.status, tt.color, got, tt.expected)
+		}
+	}
+}
+
+// TestUseColor validates the --color modes for non-terminal output.
+func TestUseColor(t *testing.T) {
+	tests := []struct {
+		mode      string
+		expected  bool
+		expectErr bool
+	}{
+		{mode: colorAuto, expected: false},
+		{mode: colorAlways, expected: true},
+		{mode: colorNever, expected: false},
+		{mode: "sometimes", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			colorMode = tt.mode
+			defer func() { colorMode = colorAuto }()
+
+			if err := validateColorMode(); (err != nil) != tt.expectErr {
+				t.Fatalf("validateColorMode() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if tt.expectErr {
+				return
+			}
+			if got := useColor(&bytes.Buffer{}); got != tt.expected {
+				t.Errorf("useColor() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestPrintPrereqChecksColor validates that colored statuses keep the table aligned.
+func TestPrintPrereqChecksColor(t *testing.T) {
+	colorMode = colorAlways
+	defer func() { colorMode = colorAuto }()
+
+	var out bytes.Buffer
+	printPrereqChecks(&out, []PrereqCheck{
+		{Name: "gdb", Required: "GDB installed", Satisfied: true, Detail: "/usr/bin/gdb"},
+		{Name: "file", Required: "'file' installed", Detail: "not found"},
+	})
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %q", out.String())
+	}
+	if !strings.Contains(lines[1], "\x1b[32mPASS\x1b[0m") || !strings.Contains(lines[2], "\x1b[31mFAIL\x1b[0m") {
+		t.Errorf("statuses not colored: %q", out.String())
+	}
+	if strings.Index(lines[1], "GDB") != strings.Index(lines[2], "'file'") {
+		t.Errorf("columns misaligned: %q", out.String())
+	}
+}
@@ -2,6 +2,7 @@ package coreinfo
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 )
@@ -17,13 +18,48 @@ var CoreinfoCmd = &cobra.Command{
 }
 
 var (
-	extractBasic    bool
-	extractDetailed bool
-	customGDBFile   string
+	extractBasic     bool
+	extractDetailed  bool
+	customGDBFile    string
+	binaryPath       string
+	binaryInCorePath string
+	includeGDBOutput bool
 )
 
+// validateCommandSourceFlags rejects combinations of flags that each select
+// which GDB commands to extract or run, or which report to print, so that
+// none of them is silently ignored.
+func validateCommandSourceFlags() error {
+	extract := extractBasic || extractDetailed
+	conflicts := []struct {
+		first, second string
+		combined      bool
+	}{
+		{"--extract-basic", "--extract-detailed", extractBasic && extractDetailed},
+		{"--extract-basic/--extract-detailed", "--gdb-file", extract && customGDBFile != ""},
+		{"--extract-basic/--extract-detailed", "--gdb-preset", extract && gdbPreset != ""},
+		{"--gdb-file", "--gdb-preset", customGDBFile != "" && gdbPreset != ""},
+		{"--gdb-file", "--gdb-by-signal", customGDBFile != "" && len(gdbBySignal) > 0},
+		{"--print-signature", "--by-host", printSignature && byHost},
+		{"--expect-signature", "--print-signature", len(expectedSignatures) > 0 && printSignature},
+		{"--expect-signature", "--by-host", len(expectedSignatures) > 0 && byHost},
+		{"--unresolved-only", "--print-signature/--by-host/--expect-signature", unresolvedOnly && (printSignature || byHost || len(expectedSignatures) > 0)},
+		{"--interactive", "--print-signature/--by-host/--expect-signature/--unresolved-only", interactive && (printSignature || byHost || len(expectedSignatures) > 0 || unresolvedOnly)},
+	}
+	for _, conflict := range conflicts {
+		if conflict.combined {
+			return fmt.Errorf("%w: %s cannot be combined with %s", ErrConflictingFlags, conflict.first, conflict.second)
+		}
+	}
+	return nil
+}
+
 // RunCoreInfo contains the logic for the coreinfo command.
 func RunCoreInfo(cmd *cobra.Command, args []string) error {
+	if err := validateCommandSourceFlags(); err != nil {
+		return err
+	}
+
 	// Handle extraction
 	if extractBasic {
 		return extractGDBFile("gdb_commands_basic.txt", "gdb_commands_basic.txt")
@@ -32,29 +68,161 @@ func RunCoreInfo(cmd *cobra.Command, args []string) error {
 		return extractGDBFile("gdb_commands_detailed.txt", "gdb_commands_detailed.txt")
 	}
 
+	formats, err := parseFormats(formatFromFlags(cmd))
+	if err != nil {
+		return err
+	}
+	if err := validateBinaryFlags(); err != nil {
+		return err
+	}
+	if err := validateDebugFile(); err != nil {
+		return err
+	}
+	if err := validateGDBPreset(); err != nil {
+		return err
+	}
+	if err := validateGDBBySignal(); err != nil {
+		return err
+	}
+	if err := validateGDBEval(); err != nil {
+		return err
+	}
+	if repeatCount < 0 {
+		return fmt.Errorf("--repeat must not be negative")
+	}
+	if err := validatePostFlags(); err != nil {
+		return err
+	}
+	if err := validateColorMode(); err != nil {
+		return err
+	}
+	if err := validateFileCommandPath(); err != nil {
+		return err
+	}
+	if err := validateExpectedSignatures(); err != nil {
+		return err
+	}
+	if err := validateInteractive(); err != nil {
+		return err
+	}
+	if err := loadKnownIssues(); err != nil {
+		return err
+	}
+	if perCoreDir && outputDir == "" {
+		return fmt.Errorf("--per-core-dir requires --output-dir")
+	}
+	if appendLogMaxMB < 0 {
+		return fmt.Errorf("--append-log-max-mb must not be negative")
+	}
+	if pidFilter < 0 {
+		return fmt.Errorf("--pid must be positive")
+	}
+	if dedupPrefixMB <= 0 {
+		return fmt.Errorf("--dedup-prefix-mb must be positive")
+	}
+	if maxFrames < 0 {
+		return fmt.Errorf("--max-frames must not be negative")
+	}
+	if err := loadCorePattern(); err != nil {
+		return err
+	}
+
+	defer startTimings(os.Stderr)()
+	defer cleanupGDBFiles(os.Stderr)
+
 	// Step 1: Check prerequisites
 	if err := checkPrerequisites(); err != nil {
-		return fmt.Errorf("prerequisite check failed: %v", err)
+		return fmt.Errorf("prerequisite check failed: %w", err)
+	}
+
+	// Cores of --from-archive are extracted to a temporary directory that
+	// is removed once they are analyzed. A bundled binary is used unless
+	// --binary is given.
+	if fromArchive != "" {
+		dir, err := os.MkdirTemp("", "cbtoolbox-coreinfo-archive-*")
+		if err != nil {
+			return fmt.Errorf("failed to create archive directory: %w", err)
+		}
+		defer os.RemoveAll(dir)
+		dirs, binary, err := extractArchive(os.Stderr, fromArchive, dir)
+		if err != nil {
+			return err
+		}
+		args = append(args, dirs...)
+		if binaryPath == "" && binary != "" {
+			binaryPath, archiveBinary = binary, binary
+			defer func() { binaryPath, archiveBinary = "", "" }()
+			if verbose {
+				fmt.Printf("Using binary %s from archive %s\n", binary, fromArchive)
+			}
+		}
 	}
 
 	// Step 2: Validate core file paths
 	coreFiles, coreInfos, err := validateCoreFiles(args)
 	if err != nil {
-		return fmt.Errorf("core file validation failed: %v", err)
+		return fmt.Errorf("core file validation failed: %w", err)
+	}
+	if pidFilter > 0 {
+		if coreFiles, err = filterCoresByPID(os.Stderr, coreFiles, pidFilter); err != nil {
+			return fmt.Errorf("core file validation failed: %w", err)
+		}
+	}
+	if dedupByContent {
+		if coreFiles, err = dedupCoreFiles(os.Stderr, coreFiles, int64(dedupPrefixMB)<<20); err != nil {
+			return fmt.Errorf("core file deduplication failed: %w", err)
+		}
+	}
+
+	// Only the signatures are printed with --print-signature
+	if printSignature {
+		return runPrintSignature(os.Stdout, coreFiles, func(coreFile string) (*CoreAnalysis, error) {
+			analysis, _, err := analyzeCore(coreFile, coreInfos[coreFile], customGDBFile)
+			return analysis, err
+		})
+	}
+
+	// Only the deviating signatures are printed with --expect-signature
+	if len(expectedSignatures) > 0 {
+		return runExpectSignature(os.Stdout, coreFiles, expectedSignatures, func(coreFile string) (*CoreAnalysis, error) {
+			analysis, _, err := analyzeCore(coreFile, coreInfos[coreFile], customGDBFile)
+			return analysis, err
+		})
 	}
 
-	// Step 3: Print detailed validation results if verbose mode is enabled
+	// Only the cores whose symbols do not resolve are printed with --unresolved-only
+	if unresolvedOnly {
+		return runUnresolvedOnly(os.Stdout, coreFiles, func(coreFile string) (*SymbolProbe, error) {
+			return probeSymbols(coreFile, coreInfos[coreFile])
+		})
+	}
+
+	// Only the per-host signature table is printed with --by-host
+	if byHost {
+		return runByHost(os.Stdout, coreFiles, func(coreFile string) (*CoreAnalysis, error) {
+			analysis, _, err := analyzeCore(coreFile, coreInfos[coreFile], customGDBFile)
+			return analysis, err
+		})
+	}
+
+	// Step 3: Print detailed validation results if verbose mode is enabled.
+	// They stay out of the default output, which scripts parse.
 	if verbose {
 		for _, coreFile := range coreFiles {
 			fmt.Printf("Validating file: %s -> Valid core file\n", coreFile)
 		}
+		fmt.Printf("Validated core files: %v\n", coreFiles)
 	}
 
-	// Placeholder: Print core file paths (replace with actual logic later)
-	fmt.Printf("Validated core files: %v\n", coreFiles)
+	if repeatCount > 1 {
+		return runRepeatCheck(os.Stdout, coreFiles, repeatCount, func(coreFile string) (*CoreAnalysis, error) {
+			analysis, _, err := analyzeCore(coreFile, coreInfos[coreFile], customGDBFile)
+			return analysis, err
+		})
+	}
 
-	if err := RunGDBAnalysisWithSummary(coreFiles, coreInfos, customGDBFile); err != nil {
-		return fmt.Errorf("gdb analysis failed: %v", err)
+	if err := RunGDBAnalysisWithSummary(coreFiles, coreInfos, customGDBFile, formats); err != nil {
+		return fmt.Errorf("gdb analysis failed: %w", err)
 	}
 
 	return nil
@@ -65,4 +233,44 @@ func init() {
 	CoreinfoCmd.Flags().BoolVarP(&extractBasic, "extract-basic", "", false, "Extract the basic GDB command file")
 	CoreinfoCmd.Flags().BoolVarP(&extractDetailed, "extract-detailed", "", false, "Extract the detailed GDB command file")
 	CoreinfoCmd.Flags().StringVarP(&customGDBFile, "gdb-file", "", "", "Path to a custom GDB command file")
+	CoreinfoCmd.Flags().BoolVarP(&keepGDBFiles, "keep-gdb-file", "", false, "Keep the temporary GDB command files and print their directory")
+	CoreinfoCmd.Flags().StringVarP(&fromArchive, "from-archive", "", "", "Analyze the cores in a tar or tar.gz archive, using a postgres binary bundled in it unless --binary is given")
+	CoreinfoCmd.Flags().StringVarP(&binaryPath, "binary", "", "", "Path to the postgres binary to analyze with (default: $GPHOME/bin/postgres)")
+	CoreinfoCmd.Flags().StringVarP(&binaryInCorePath, "binary-in-core-path", "", "", "Executable path recorded in the core that --binary replaces")
+	CoreinfoCmd.Flags().StringVarP(&fileCommandPath, "file-path", "", "", "Path to the 'file' executable used to recognize core files (default: look up in PATH)")
+	CoreinfoCmd.Flags().BoolVarP(&strictELF, "strict-elf", "", false, "Only accept files whose ELF type is ET_CORE, rejecting executables and shared objects")
+	CoreinfoCmd.Flags().StringVarP(&debugFilePath, "debug-file", "", "", "Separate debug file with symbols for a stripped binary (default: auto-detect <binary>.debug)")
+	CoreinfoCmd.Flags().StringVarP(&gdbPreset, "gdb-preset", "", "", "GDB command preset to run: basic or detailed (default: basic)")
+	CoreinfoCmd.Flags().StringToStringVarP(&gdbBySignal, "gdb-by-signal", "", nil, "Map signals to GDB presets (basic, detailed) or command files, e.g. SIGSEGV=detailed")
+	CoreinfoCmd.Flags().BoolVarP(&includeOpenFiles, "open-files", "", false, "Reconstruct the files open at crash time (requires debug symbols)")
+	CoreinfoCmd.Flags().BoolVarP(&includeMemoryMap, "memory-map", "", false, "Report the memory map of the crashed process and the mapping containing the faulting address")
+	CoreinfoCmd.Flags().StringArrayVarP(&gdbEvalCommands, "gdb-eval", "", nil, "Extra gdb command to run after the command file (repeatable)")
+	CoreinfoCmd.Flags().StringVarP(&knownIssuesPath, "known-issues", "", "", "YAML table of known crashes and their issues, replacing the built-in table")
+	CoreinfoCmd.Flags().BoolVarP(&printSignature, "print-signature", "", false, "Print only the crash signature and its hash for each core")
+	CoreinfoCmd.Flags().StringSliceVarP(&expectedSignatures, "expect-signature", "", nil, "Print only cores whose signature hash is not one of these, failing if any deviate (repeatable)")
+	CoreinfoCmd.Flags().BoolVarP(&unresolvedOnly, "unresolved-only", "", false, "Probe each core's backtrace and print only cores whose symbols do not resolve, failing if any are found")
+	CoreinfoCmd.Flags().BoolVarP(&byHost, "by-host", "", false, "Print the crash signature of each core grouped by the host that generated it")
+	CoreinfoCmd.Flags().StringVarP(&corePattern, "core-pattern", "", "", "core_pattern the cores were written with, to take hostnames from their names (default: the local /proc/sys/kernel/core_pattern)")
+	CoreinfoCmd.Flags().IntVarP(&maxFrames, "max-frames", "", defaultMaxFrames, "Truncate each parsed backtrace to N frames (0 for unlimited)")
+	CoreinfoCmd.Flags().IntVarP(&pidFilter, "pid", "", 0, "Only analyze cores generated by this process ID (read from the core's notes or its core.<pid> name)")
+	CoreinfoCmd.Flags().BoolVarP(&dedupByContent, "dedup-by-content", "", false, "Skip cores whose content duplicates an earlier core")
+	CoreinfoCmd.Flags().IntVarP(&dedupPrefixMB, "dedup-prefix-mb", "", defaultDedupPrefixMB, "MiB of each core hashed by --dedup-by-content")
+	CoreinfoCmd.Flags().StringP("format", "", formatText, "Output format: text, markdown or json; comma-separate several formats, e.g. text,json")
+	CoreinfoCmd.Flags().StringVarP(&outputDir, "output-dir", "", "", "Directory to save each analysis to, one file per format (default: print to stdout)")
+	CoreinfoCmd.Flags().BoolVarP(&perCoreDir, "per-core-dir", "", false, "Save each core's analyses and raw gdb output to its own subdirectory of --output-dir")
+	CoreinfoCmd.Flags().BoolVarP(&showTimings, "timings", "", false, "Report the duration of each gdb invocation on stderr")
+	CoreinfoCmd.Flags().StringVarP(&appendLogPath, "append-log", "", "", "Append each analysis as one JSON line to this file, creating it if absent")
+	CoreinfoCmd.Flags().IntVarP(&appendLogMaxMB, "append-log-max-mb", "", defaultAppendLogMaxMB, "Rotate --append-log to <file>.1 beyond this many MiB (0 to never rotate)")
+	CoreinfoCmd.Flags().StringVarP(&postURL, "post-url", "", "", "POST each analysis as JSON to this URL")
+	CoreinfoCmd.Flags().StringArrayVarP(&postHeaders, "header", "", nil, "Extra 'Name: value' header for --post-url requests (repeatable)")
+	CoreinfoCmd.Flags().DurationVarP(&postTimeout, "post-timeout", "", defaultPostTimeout, "Timeout of each --post-url request")
+	CoreinfoCmd.Flags().BoolVarP(&postRequired, "post-required", "", false, "Fail when an analysis cannot be posted to --post-url")
+	CoreinfoCmd.Flags().BoolVarP(&interactive, "interactive", "", false, "Open gdb on each core after printing its analysis; quit gdb to continue")
+	CoreinfoCmd.Flags().BoolVarP(&includeGDBOutput, "include-gdb-output", "", false, "Include the raw gdb output in markdown reports")
+
+	// Hidden: stress-test parser stability by analyzing each core N times
+	CoreinfoCmd.Flags().IntVarP(&repeatCount, "repeat", "n", 0, "Analyze each core N times and report whether the results were identical")
+	_ = CoreinfoCmd.Flags().MarkHidden("repeat")
+	CoreinfoCmd.Flags().StringVarP(&colorMode, "color", "", colorAuto, "Color PASS/FAIL statuses of --repeat: auto, always or never")
+	_ = CoreinfoCmd.Flags().MarkHidden("color")
 }
@@ -0,0 +1,55 @@
+package coreinfo
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestValidateCommandSourceFlags validates that conflicting command-source
+// flags are rejected.
+func TestValidateCommandSourceFlags(t *testing.T) {
+	tests := []struct {
+		name            string
+		extractBasic    bool
+		extractDetailed bool
+		gdbFile         string
+		gdbPreset       string
+		gdbBySignal     map[string]string
+		printSignature  bool
+		byHost          bool
+		unresolvedOnly  bool
+		expectErr       bool
+	}{
+		{name: "no flags"},
+		{name: "gdb file only", gdbFile: "commands.gdb"},
+		{name: "preset with signal mapping", gdbPreset: "basic", gdbBySignal: map[string]string{"SIGSEGV": "detailed"}},
+		{name: "both extract flags", extractBasic: true, extractDetailed: true, expectErr: true},
+		{name: "extract with gdb file", extractBasic: true, gdbFile: "commands.gdb", expectErr: true},
+		{name: "extract with gdb preset", extractDetailed: true, gdbPreset: "basic", expectErr: true},
+		{name: "gdb file with gdb preset", gdbFile: "commands.gdb", gdbPreset: "detailed", expectErr: true},
+		{name: "gdb file with signal mapping", gdbFile: "commands.gdb", gdbBySignal: map[string]string{"SIGSEGV": "detailed"}, expectErr: true},
+		{name: "print signature with by host", printSignature: true, byHost: true, expectErr: true},
+		{name: "unresolved only with print signature", unresolvedOnly: true, printSignature: true, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			extractBasic, extractDetailed = tt.extractBasic, tt.extractDetailed
+			customGDBFile, gdbPreset, gdbBySignal = tt.gdbFile, tt.gdbPreset, tt.gdbBySignal
+			printSignature, byHost, unresolvedOnly = tt.printSignature, tt.byHost, tt.unresolvedOnly
+			defer func() {
+				extractBasic, extractDetailed = false, false
+				customGDBFile, gdbPreset, gdbBySignal = "", "", nil
+				printSignature, byHost, unresolvedOnly = false, false, false
+			}()
+
+			err := validateCommandSourceFlags()
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("expected error: %v, got: %v", tt.expectErr, err)
+			}
+			if tt.expectErr && !errors.Is(err, ErrConflictingFlags) {
+				t.Errorf("expected ErrConflictingFlags, got: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package coreinfo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+)
+
+// defaultDedupPrefixMB is how much of each core --dedup-by-content hashes.
+const defaultDedupPrefixMB = 64
+
+var (
+	// dedupByContent skips cores whose content duplicates an earlier core
+	dedupByContent bool
+
+	// dedupPrefixMB is the size of the hashed prefix of each core in MiB
+	dedupPrefixMB = defaultDedupPrefixMB
+)
+
+// contentKey identifies a core by its size and a SHA-256 hash of its first
+// prefixBytes bytes. Full hashing of multi-GB cores is costly, and copies
+// of the same core agree on both.
+func contentKey(path string, prefixBytes int64) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", permissionError(path, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", fmt.Errorf("failed to stat core %s: %v", path, err)
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(hash, io.LimitReader(f, prefixBytes)); err != nil {
+		return "", fmt.Errorf("failed to hash core %s: %v", path, err)
+	}
+	return fmt.Sprintf("%d:%s", info.Size(), hex.EncodeToString(hash.Sum(nil))), nil
+}
+
+// dedupCoreFiles collapses cores with identical content keys into the first
+// path given for them, reporting each collapsed path to w.
+// Returns the cores to analyze, in their original order.
+func dedupCoreFiles(w io.Writer, coreFiles []string, prefixBytes int64) ([]string, error) {
+	seen := make(map[string]string)
+	var unique []string
+	for _, coreFile := range coreFiles {
+		key, err := contentKey(coreFile, prefixBytes)
+		if err != nil {
+			return nil, err
+		}
+		if original, ok := seen[key]; ok {
+			fmt.Fprintf(w, "Skipping %s: same content as %s\n", coreFile, original)
+			continue
+		}
+		seen[key] = coreFile
+		unique = append(unique, coreFile)
+	}
+	return unique, nil
+}
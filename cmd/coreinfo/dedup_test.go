@@ -0,0 +1,80 @@
+package coreinfo
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestDedupCoreFiles validates collapsing copies of the same core.
+func TestDedupCoreFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	original := write("core.1", "ELF core contents A")
+	backup := write("core.1.bak", "ELF core contents A")
+	other := write("core.2", "ELF core contents B")
+	// Same prefix, different tail and size
+	longer := write("core.3", "ELF core contents A and more")
+	link := filepath.Join(dir, "core.link")
+	if err := os.Symlink(other, link); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		coreFiles []string
+		prefix    int64
+		expected  []string
+		collapsed []string
+	}{
+		{
+			name:      "no duplicates",
+			coreFiles: []string{original, other},
+			prefix:    1 << 20,
+			expected:  []string{original, other},
+		},
+		{
+			name:      "copy and symlink",
+			coreFiles: []string{original, other, backup, link},
+			prefix:    1 << 20,
+			expected:  []string{original, other},
+			collapsed: []string{backup, link},
+		},
+		{
+			name:      "same prefix different size",
+			coreFiles: []string{original, longer},
+			prefix:    4,
+			expected:  []string{original, longer},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			got, err := dedupCoreFiles(&out, tt.coreFiles, tt.prefix)
+			if err != nil {
+				t.Fatalf("dedupCoreFiles() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("dedupCoreFiles() = %v, expected %v", got, tt.expected)
+			}
+			if lines := strings.Count(out.String(), "\n"); lines != len(tt.collapsed) {
+				t.Errorf("expected %d collapsed paths, got %q", len(tt.collapsed), out.String())
+			}
+			for _, path := range tt.collapsed {
+				if !strings.Contains(out.String(), "Skipping "+path) {
+					t.Errorf("%s not reported as collapsed: %q", path, out.String())
+				}
+			}
+		})
+	}
+}
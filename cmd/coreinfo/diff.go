@@ -0,0 +1,241 @@
+package coreinfo
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// DiffCmd analyzes two cores and reports how their crashes differ.
+var DiffCmd = &cobra.Command{
+	Use:   "diff <coreA> <coreB>",
+	Short: "Compare the analyses of two core files",
+	Long:  "Analyze two core files and report how the crashes differ: the signal, the crashed thread's stack frames they share and where they diverge, and the registers whose values differ.",
+	Args:  cobra.ExactArgs(2),
+	RunE:  RunDiff,
+}
+
+// registerSectionMarker is the header the embedded command files print
+// before 'info registers all'.
+const registerSectionMarker = "=== Register State"
+
+// registerRegex matches a scalar register line of 'info registers', e.g.
+// "rip            0x4005a4            0x4005a4 <ExecProcNode+20>".
+// Vector registers, printed as {...}, are not matched.
+var registerRegex = regexp.MustCompile(`^(\w+)\s+(0x[0-9a-fA-F]+)\b`)
+
+// CoreDiff describes how the crashes in two cores differ.
+//
+// CommonFrames lists the functions the crashed threads share from the
+// crash point outwards; DivergentFramesA and DivergentFramesB list the
+// frames below the point where the backtraces part.
+type CoreDiff struct {
+	CoreA            string         `json:"core_a" yaml:"core_a"`
+	CoreB            string         `json:"core_b" yaml:"core_b"`
+	SignalA          string         `json:"signal_a" yaml:"signal_a"`
+	SignalB          string         `json:"signal_b" yaml:"signal_b"`
+	SameSignal       bool           `json:"same_signal" yaml:"same_signal"`
+	SameSignature    bool           `json:"same_signature" yaml:"same_signature"`
+	CommonFrames     []string       `json:"common_frames" yaml:"common_frames"`
+	DivergentFramesA []string       `json:"divergent_frames_a" yaml:"divergent_frames_a"`
+	DivergentFramesB []string       `json:"divergent_frames_b" yaml:"divergent_frames_b"`
+	Registers        []RegisterDiff `json:"registers,omitempty" yaml:"registers,omitempty"`
+}
+
+// RegisterDiff is a register whose value differs between two cores.
+type RegisterDiff struct {
+	Name   string `json:"name" yaml:"name"`
+	ValueA string `json:"value_a" yaml:"value_a"`
+	ValueB string `json:"value_b" yaml:"value_b"`
+}
+
+// parseRegisters returns the scalar register values printed in the
+// Register State section of a gdb transcript, in the order gdb printed
+// them. Returns nil if the transcript has no such section.
+func parseRegisters(gdbOutput string) ([]string, map[string]string) {
+	_, section, found := strings.Cut(gdbOutput, registerSectionMarker)
+	if !found {
+		return nil, nil
+	}
+
+	var names []string
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(section))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		// The section ends at the next section header
+		if strings.HasPrefix(line, "=== ") {
+			break
+		}
+		if match := registerRegex.FindStringSubmatch(line); match != nil {
+			if _, seen := values[match[1]]; !seen {
+				names = append(names, match[1])
+			}
+			values[match[1]] = match[2]
+		}
+	}
+	return names, values
+}
+
+// signalName returns the signal name of an analysis, e.g. "SIGSEGV".
+func signalName(analysis *CoreAnalysis) string {
+	if fields := strings.Fields(analysis.Signal); len(fields) > 0 {
+		return fields[0]
+	}
+	return ""
+}
+
+// frameFunctions returns the function names of a backtrace.
+func frameFunctions(frames []StackFrame) []string {
+	functions := make([]string, 0, len(frames))
+	for _, frame := range frames {
+		functions = append(functions, frame.Function)
+	}
+	return functions
+}
+
+// diffAnalyses compares the analyses of two cores.
+func diffAnalyses(a, b *CoreAnalysis) *CoreDiff {
+	diff := &CoreDiff{
+		CoreA:   a.CoreFile,
+		CoreB:   b.CoreFile,
+		SignalA: a.Signal,
+		SignalB: b.Signal,
+	}
+	diff.SameSignal = signalName(a) != "" && signalName(a) == signalName(b)
+
+	signatureA, errA := crashSignature(a)
+	signatureB, errB := crashSignature(b)
+	diff.SameSignature = errA == nil && errB == nil && signatureA == signatureB
+
+	// Backtraces are compared from the crash point outwards
+	functionsA, functionsB := frameFunctions(a.CrashedThread), frameFunctions(b.CrashedThread)
+	common := 0
+	for common < len(functionsA) && common < len(functionsB) && functionsA[common] == functionsB[common] {
+		common++
+	}
+	diff.CommonFrames = functionsA[:common]
+	diff.DivergentFramesA = functionsA[common:]
+	diff.DivergentFramesB = functionsB[common:]
+
+	namesA, registersA := parseRegisters(a.GDBOutput)
+	_, registersB := parseRegisters(b.GDBOutput)
+	for _, name := range namesA {
+		valueB, ok := registersB[name]
+		if ok && registersA[name] != valueB {
+			diff.Registers = append(diff.Registers, RegisterDiff{Name: name, ValueA: registersA[name], ValueB: valueB})
+		}
+	}
+	return diff
+}
+
+// sameOrDifferent describes a comparison result.
+func sameOrDifferent(same bool) string {
+	if same {
+		return "same"
+	}
+	return "different"
+}
+
+// frameList formats function names for a single-line report entry.
+func frameList(functions []string) string {
+	if len(functions) == 0 {
+		return "none"
+	}
+	return strings.Join(functions, " > ")
+}
+
+// renderDiff renders a core diff in the given output format.
+func renderDiff(diff *CoreDiff, format string) (string, error) {
+	var b strings.Builder
+	switch format {
+	case formatJSON:
+		data, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal diff of %s and %s: %w", diff.CoreA, diff.CoreB, err)
+		}
+		return string(data) + "\n", nil
+
+	case formatMarkdown:
+		fmt.Fprintf(&b, "## Core Dump Diff: `%s` vs `%s`\n\n", diff.CoreA, diff.CoreB)
+		b.WriteString("| Field | Core A | Core B |\n")
+		b.WriteString("| --- | --- | --- |\n")
+		fmt.Fprintf(&b, "| Signal (%s) | %s | %s |\n", sameOrDifferent(diff.SameSignal), markdownCell(diff.SignalA), markdownCell(diff.SignalB))
+		fmt.Fprintf(&b, "| Divergent Frames | %s | %s |\n", markdownCell(frameList(diff.DivergentFramesA)), markdownCell(frameList(diff.DivergentFramesB)))
+		for _, register := range diff.Registers {
+			fmt.Fprintf(&b, "| Register %s | %s | %s |\n", register.Name, register.ValueA, register.ValueB)
+		}
+		fmt.Fprintf(&b, "\n- Crash signature: %s\n", sameOrDifferent(diff.SameSignature))
+		fmt.Fprintf(&b, "- Common frames: %s\n", markdownCell(frameList(diff.CommonFrames)))
+		return b.String(), nil
+
+	default:
+		fmt.Fprintf(&b, "Core A: %s\n", diff.CoreA)
+		fmt.Fprintf(&b, "Core B: %s\n\n", diff.CoreB)
+		fmt.Fprintf(&b, "- Signal: %s (%s vs %s)\n", sameOrDifferent(diff.SameSignal), diff.SignalA, diff.SignalB)
+		fmt.Fprintf(&b, "- Crash Signature: %s\n", sameOrDifferent(diff.SameSignature))
+		fmt.Fprintf(&b, "- Common Frames (%d): %s\n", len(diff.CommonFrames), frameList(diff.CommonFrames))
+		fmt.Fprintf(&b, "- Divergent Frames A (%d): %s\n", len(diff.DivergentFramesA), frameList(diff.DivergentFramesA))
+		fmt.Fprintf(&b, "- Divergent Frames B (%d): %s\n", len(diff.DivergentFramesB), frameList(diff.DivergentFramesB))
+		fmt.Fprintf(&b, "- Register Differences (%d):", len(diff.Registers))
+		for _, register := range diff.Registers {
+			fmt.Fprintf(&b, "\n  - %s: %s vs %s", register.Name, register.ValueA, register.ValueB)
+		}
+		b.WriteString("\n")
+		return b.String(), nil
+	}
+}
+
+// RunDiff contains the logic for the coreinfo diff command.
+func RunDiff(cmd *cobra.Command, args []string) error {
+	format := formatFromFlags(cmd)
+	if err := validateFormat(format); err != nil {
+		return err
+	}
+	if err := validateFileCommandPath(); err != nil {
+		return err
+	}
+
+	if err := checkPrerequisites(); err != nil {
+		return fmt.Errorf("prerequisite check failed: %w", err)
+	}
+	defer startTimings(os.Stderr)()
+
+	coreFiles, coreInfos, err := validateCoreFiles(args)
+	if err != nil {
+		return fmt.Errorf("core file validation failed: %w", err)
+	}
+	if len(coreFiles) != 2 {
+		return fmt.Errorf("%w: diff needs exactly two core files, found %d in %s", ErrNoValidCoreFiles, len(coreFiles), strings.Join(args, ", "))
+	}
+
+	analyses := make([]*CoreAnalysis, 0, 2)
+	for _, coreFile := range coreFiles {
+		analysis, _, err := analyzeCore(coreFile, coreInfos[coreFile], "")
+		if err != nil {
+			return fmt.Errorf("gdb analysis failed: %w", err)
+		}
+		analyses = append(analyses, analysis)
+	}
+
+	report, err := renderDiff(diffAnalyses(analyses[0], analyses[1]), format)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(os.Stdout, report)
+	return nil
+}
+
+func init() {
+	DiffCmd.Flags().StringP("format", "", formatText, "Output format: text, markdown or json")
+	DiffCmd.Flags().BoolVarP(&showTimings, "timings", "", false, "Report the duration of each gdb invocation on stderr")
+	DiffCmd.Flags().BoolVarP(&strictELF, "strict-elf", "", false, "Only accept files whose ELF type is ET_CORE, rejecting executables and shared objects")
+	DiffCmd.Flags().StringVarP(&fileCommandPath, "file-path", "", "", "Path to the 'file' executable used to recognize core files (default: look up in PATH)")
+	CoreinfoCmd.AddCommand(DiffCmd)
+}
@@ -0,0 +1,119 @@
+package coreinfo
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// registerOutput returns a transcript fragment with a Register State section.
+func registerOutput(rip, rsp string) string {
+	return "=== Register State\n" +
+		"======================================================================\n\n" +
+		"rax            0x0                 0\n" +
+		"rsp            " + rsp + "      " + rsp + "\n" +
+		"rip            " + rip + "            " + rip + " <ExecProcNode+20>\n" +
+		"xmm0           {v8_bfloat16 = {0x0, 0x0}}\n" +
+		"\n\n======================================================================\n" +
+		"=== Signal Information\n" +
+		"rbx            0x1                 1\n"
+}
+
+// TestParseRegisters validates parsing of scalar registers from the Register State section only.
+func TestParseRegisters(t *testing.T) {
+	names, values := parseRegisters("rcx 0x5 5\n" + registerOutput("0x4005a4", "0x7ffd1000"))
+
+	if !reflect.DeepEqual(names, []string{"rax", "rsp", "rip"}) {
+		t.Errorf("Unexpected registers %v", names)
+	}
+	if values["rip"] != "0x4005a4" || values["rsp"] != "0x7ffd1000" {
+		t.Errorf("Unexpected register values %v", values)
+	}
+
+	if names, _ := parseRegisters(sampleGDBOutput); names != nil {
+		t.Errorf("Expected no registers without a Register State section, got %v", names)
+	}
+}
+
+// TestDiffAnalyses validates the comparison of signals, frames and registers.
+func TestDiffAnalyses(t *testing.T) {
+	frames := func(functions ...string) []StackFrame {
+		var result []StackFrame
+		for i, function := range functions {
+			result = append(result, StackFrame{Index: i, Function: function})
+		}
+		return result
+	}
+	a := &CoreAnalysis{
+		CoreFile:      "core.1",
+		Signal:        "SIGSEGV (Segmentation fault)",
+		CrashedThread: frames("ExecProcNode", "ExecutePlan", "standard_ExecutorRun", "PortalRun"),
+		GDBOutput:     registerOutput("0x4005a4", "0x7ffd1000"),
+	}
+	b := &CoreAnalysis{
+		CoreFile:      "core.2",
+		Signal:        "SIGSEGV (Segmentation fault)",
+		CrashedThread: frames("ExecProcNode", "ExecutePlan", "ExecInitNode"),
+		GDBOutput:     registerOutput("0x4005a4", "0x7ffd2000"),
+	}
+
+	diff := diffAnalyses(a, b)
+	if !diff.SameSignal || diff.SameSignature {
+		t.Errorf("Expected same signal and different signatures, got %+v", diff)
+	}
+	if !reflect.DeepEqual(diff.CommonFrames, []string{"ExecProcNode", "ExecutePlan"}) {
+		t.Errorf("Unexpected common frames %v", diff.CommonFrames)
+	}
+	if !reflect.DeepEqual(diff.DivergentFramesA, []string{"standard_ExecutorRun", "PortalRun"}) ||
+		!reflect.DeepEqual(diff.DivergentFramesB, []string{"ExecInitNode"}) {
+		t.Errorf("Unexpected divergent frames %v / %v", diff.DivergentFramesA, diff.DivergentFramesB)
+	}
+	expected := []RegisterDiff{{Name: "rsp", ValueA: "0x7ffd1000", ValueB: "0x7ffd2000"}}
+	if !reflect.DeepEqual(diff.Registers, expected) {
+		t.Errorf("Unexpected register differences %v", diff.Registers)
+	}
+
+	if same := diffAnalyses(a, a); !same.SameSignature || len(same.DivergentFramesA) != 0 || len(same.Registers) != 0 {
+		t.Errorf("Expected no differences between a core and itself, got %+v", same)
+	}
+}
+
+// TestRenderDiff validates the text, markdown and json diff output.
+func TestRenderDiff(t *testing.T) {
+	diff := &CoreDiff{
+		CoreA: "core.1", CoreB: "core.2",
+		SignalA: "SIGSEGV (Segmentation fault)", SignalB: "SIGABRT (Aborted)",
+		CommonFrames:     []string{"ExecProcNode"},
+		DivergentFramesA: []string{"ExecutePlan"},
+		DivergentFramesB: []string{},
+		Registers:        []RegisterDiff{{Name: "rip", ValueA: "0x1", ValueB: "0x2"}},
+	}
+
+	text, err := renderDiff(diff, formatText)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, want := range []string{"- Signal: different", "- Common Frames (1): ExecProcNode", "- Divergent Frames B (0): none", "  - rip: 0x1 vs 0x2"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected text diff to contain %q, got:\n%s", want, text)
+		}
+	}
+
+	markdown, err := renderDiff(diff, formatMarkdown)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(markdown, "| Register rip | 0x1 | 0x2 |") {
+		t.Errorf("Expected register row in markdown diff, got:\n%s", markdown)
+	}
+
+	document, err := renderDiff(diff, formatJSON)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var decoded CoreDiff
+	if err := json.Unmarshal([]byte(document), &decoded); err != nil || decoded.CoreB != "core.2" {
+		t.Errorf("Expected a parseable JSON diff, got %v:\n%s", err, document)
+	}
+}
@@ -0,0 +1,55 @@
+package coreinfo
+
+import "errors"
+
+// Sentinel errors returned (usually wrapped) by the coreinfo command.
+// Callers should match them with errors.Is rather than comparing messages.
+var (
+	// ErrGDBNotFound indicates gdb is not installed or not in PATH.
+	ErrGDBNotFound = errors.New("gdb not found")
+
+	// ErrGPHOMENotSet indicates the GPHOME environment variable is not set.
+	ErrGPHOMENotSet = errors.New("GPHOME environment variable is not set")
+
+	// ErrNoCoreFiles indicates no core file arguments were given.
+	ErrNoCoreFiles = errors.New("no core files specified")
+
+	// ErrNoValidCoreFiles indicates none of the given paths was a core file.
+	ErrNoValidCoreFiles = errors.New("no valid core files provided")
+
+	// ErrPostgresNotFound indicates the postgres binary could not be located.
+	ErrPostgresNotFound = errors.New("postgres binary not found")
+
+	// ErrInvalidFormat indicates an unsupported output format was requested.
+	ErrInvalidFormat = errors.New("invalid format")
+
+	// ErrConflictingFlags indicates flags that select the same setting were combined.
+	ErrConflictingFlags = errors.New("conflicting flags")
+
+	// ErrNondeterministicAnalysis indicates repeated analyses of a core differed.
+	ErrNondeterministicAnalysis = errors.New("analysis results differed between runs")
+
+	// ErrNoSignature indicates a crash signature could not be computed.
+	ErrNoSignature = errors.New("crash signature unavailable")
+
+	// ErrUnexpectedSignature indicates a core deviated from --expect-signature.
+	ErrUnexpectedSignature = errors.New("unexpected crash signature")
+
+	// ErrUnresolvedSymbols indicates a core's backtrace did not resolve with --unresolved-only.
+	ErrUnresolvedSymbols = errors.New("symbols did not resolve")
+
+	// ErrCoreLoadFailed indicates gdb could not load a core file.
+	ErrCoreLoadFailed = errors.New("gdb could not load core")
+
+	// ErrELFClassMismatch indicates a core and binary of different word sizes.
+	ErrELFClassMismatch = errors.New("ELF class mismatch")
+
+	// ErrPostFailed indicates an analysis could not be posted to --post-url.
+	ErrPostFailed = errors.New("failed to post analysis")
+
+	// ErrSchemaMismatch indicates a saved analysis does not match the current analysis schema.
+	ErrSchemaMismatch = errors.New("saved analysis does not match the analysis schema")
+
+	// ErrPermissionDenied indicates a core or binary could not be read (EACCES/EPERM).
+	ErrPermissionDenied = errors.New("permission denied")
+)
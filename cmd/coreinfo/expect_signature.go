@@ -0,0 +1,60 @@
+package coreinfo
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// expectedSignatures are the signature hashes accepted by
+// --expect-signature; cores with any other signature deviate.
+var expectedSignatures []string
+
+// validateExpectedSignatures normalizes --expect-signature hashes and
+// checks that they have the form printed by --print-signature.
+func validateExpectedSignatures() error {
+	for i, hash := range expectedSignatures {
+		hash = strings.ToLower(strings.TrimSpace(hash))
+		if _, err := hex.DecodeString(hash); err != nil || len(hash) != len(signatureHash("")) {
+			return fmt.Errorf("--expect-signature must be a %d-character signature hash as printed by --print-signature: %q", len(signatureHash("")), expectedSignatures[i])
+		}
+		expectedSignatures[i] = hash
+	}
+	return nil
+}
+
+// runExpectSignature analyzes each core and writes one tab-separated line
+// per core whose signature hash is not in expected: the signature hash,
+// the signature and the core file. A core without a signature deviates
+// with "-" as its hash.
+// Returns an error wrapping ErrUnexpectedSignature if any core deviates.
+func runExpectSignature(w io.Writer, coreFiles []string, expected []string, analyze func(coreFile string) (*CoreAnalysis, error)) error {
+	accepted := make(map[string]bool, len(expected))
+	for _, hash := range expected {
+		accepted[hash] = true
+	}
+
+	deviating := 0
+	for _, coreFile := range coreFiles {
+		analysis, err := analyze(coreFile)
+		if err != nil {
+			return err
+		}
+		signature, err := crashSignature(analysis)
+		if err != nil {
+			deviating++
+			fmt.Fprintf(w, "-\t%v\t%s\n", err, coreFile)
+			continue
+		}
+		if hash := signatureHash(signature); !accepted[hash] {
+			deviating++
+			fmt.Fprintf(w, "%s\t%s\t%s\n", hash, signature, coreFile)
+		}
+	}
+
+	if deviating > 0 {
+		return fmt.Errorf("%w: %d of %d cores", ErrUnexpectedSignature, deviating, len(coreFiles))
+	}
+	return nil
+}
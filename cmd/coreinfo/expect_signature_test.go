@@ -0,0 +1,82 @@
+package coreinfo
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestRunExpectSignature validates that only cores deviating from the
+// expected signatures are printed, and that any deviation fails.
+func TestRunExpectSignature(t *testing.T) {
+	segv := &CoreAnalysis{Signal: "SIGSEGV (Segmentation fault)", CrashedThread: []StackFrame{{Function: "ExecProcNode"}}}
+	abrt := &CoreAnalysis{Signal: "SIGABRT (Aborted)", CrashedThread: []StackFrame{{Function: "abort"}}}
+	segvSignature, _ := crashSignature(segv)
+	abrtSignature, _ := crashSignature(abrt)
+	analyses := map[string]*CoreAnalysis{"core.1": segv, "core.2": abrt, "core.3": {CoreFile: "core.3"}}
+	analyze := func(coreFile string) (*CoreAnalysis, error) { return analyses[coreFile], nil }
+
+	tests := []struct {
+		name      string
+		coreFiles []string
+		expected  []string
+		output    []string
+		expectErr bool
+	}{
+		{name: "all match", coreFiles: []string{"core.1"}, expected: []string{signatureHash(segvSignature)}},
+		{name: "multiple expected", coreFiles: []string{"core.1", "core.2"}, expected: []string{signatureHash(segvSignature), signatureHash(abrtSignature)}},
+		{
+			name:      "deviating core",
+			coreFiles: []string{"core.1", "core.2"},
+			expected:  []string{signatureHash(segvSignature)},
+			output:    []string{signatureHash(abrtSignature) + "\t" + abrtSignature + "\tcore.2"},
+			expectErr: true,
+		},
+		{
+			name:      "core without signature",
+			coreFiles: []string{"core.3"},
+			expected:  []string{signatureHash(segvSignature)},
+			output:    []string{"-\t"},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := runExpectSignature(&buf, tt.coreFiles, tt.expected, analyze)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("runExpectSignature() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if err != nil && !errors.Is(err, ErrUnexpectedSignature) {
+				t.Errorf("expected ErrUnexpectedSignature, got %v", err)
+			}
+			lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+			if len(tt.output) == 0 && buf.Len() > 0 {
+				t.Errorf("expected no output, got %q", buf.String())
+			}
+			if len(tt.output) > 0 && (len(lines) != len(tt.output) || !strings.HasPrefix(lines[0], tt.output[0])) {
+				t.Errorf("unexpected output %q", buf.String())
+			}
+		})
+	}
+}
+
+// TestValidateExpectedSignatures validates that hashes are normalized and
+// malformed hashes rejected.
+func TestValidateExpectedSignatures(t *testing.T) {
+	defer func() { expectedSignatures = nil }()
+
+	expectedSignatures = []string{" 9C1F0E2AB47D6C55 "}
+	if err := validateExpectedSignatures(); err != nil || expectedSignatures[0] != "9c1f0e2ab47d6c55" {
+		t.Errorf("expected a normalized hash, got %q, %v", expectedSignatures, err)
+	}
+
+	for _, hash := range []string{"9c1f0e2a", "SIGSEGV:ExecProcNode", "zz1f0e2ab47d6c55"} {
+		expectedSignatures = []string{hash}
+		if err := validateExpectedSignatures(); err == nil {
+			t.Errorf("expected %q to be rejected", hash)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package coreinfo
+
+import (
+	"regexp"
+	"strings"
+)
+
+// optimizedOut is the value gdb prints for arguments and locals the
+// compiler did not keep. It is reported as is, so consumers can tell a
+// missing value from an empty one.
+const optimizedOut = "<optimized out>"
+
+// localRegex matches a local variable line printed by 'bt full' below its
+// frame, such as "        slot = 0x0".
+var localRegex = regexp.MustCompile(`^\s+([A-Za-z_]\w*) = (.*)$`)
+
+// parseFrameArgs parses the argument list of a backtrace frame, such as
+// "node=0x0, econtext=<optimized out>", into a map of argument names to
+// values. Commas inside strings, brackets and braces do not split
+// arguments. Returns nil for an empty list.
+func parseFrameArgs(list string) map[string]string {
+	var args map[string]string
+	for _, arg := range splitTopLevel(list) {
+		name, value, found := strings.Cut(arg, "=")
+		name = strings.TrimSpace(name)
+		if !found || name == "" {
+			continue
+		}
+		if args == nil {
+			args = make(map[string]string)
+		}
+		args[name] = strings.TrimSpace(value)
+	}
+	return args
+}
+
+// splitTopLevel splits s at commas outside double-quoted strings and
+// (), [], {} and <> pairs.
+func splitTopLevel(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	inString, escaped := false, false
+	for i, c := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case inString && c == '\\':
+			escaped = true
+		case c == '"':
+			inString = !inString
+		case inString:
+		case strings.ContainsRune("([{<", c):
+			depth++
+		case strings.ContainsRune(")]}>", c):
+			depth--
+		case c == ',' && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// braceDepth returns the number of braces a value opens but does not
+// close, ignoring braces in strings. Pretty-printed structs continue on
+// the following lines until their braces are closed.
+func braceDepth(value string) int {
+	depth := 0
+	inString, escaped := false, false
+	for _, c := range value {
+		switch {
+		case escaped:
+			escaped = false
+		case inString && c == '\\':
+			escaped = true
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+		}
+	}
+	return depth
+}
@@ -0,0 +1,79 @@
+package coreinfo
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestParseFrameArgs validates splitting of backtrace argument lists.
+func TestParseFrameArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		list     string
+		expected map[string]string
+	}{
+		{"no arguments", "", nil},
+		{"pointers", "node=0x0, econtext=0x2b3c4d0", map[string]string{"node": "0x0", "econtext": "0x2b3c4d0"}},
+		{"optimized out", "queryDesc=<optimized out>, count=0", map[string]string{"queryDesc": optimizedOut, "count": "0"}},
+		{
+			name:     "commas in strings and aggregates",
+			list:     `query_string=0x2b3c000 "SELECT a, b FROM t", stats={calls = 1, rows = 2}, fn=0x4a1b2c <ExecScan>`,
+			expected: map[string]string{"query_string": `0x2b3c000 "SELECT a, b FROM t"`, "stats": "{calls = 1, rows = 2}", "fn": "0x4a1b2c <ExecScan>"},
+		},
+		{"escaped quote", `msg=0x1 "say \"hi, there\""`, map[string]string{"msg": `0x1 "say \"hi, there\""`}},
+		{"varargs", "fmt=0x1 \"%s\", ...", map[string]string{"fmt": "0x1 \"%s\""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if args := parseFrameArgs(tt.list); !reflect.DeepEqual(args, tt.expected) {
+				t.Errorf("parseFrameArgs(%q) = %v, want %v", tt.list, args, tt.expected)
+			}
+		})
+	}
+}
+
+// TestParseBacktraceLocals validates that 'bt full' locals are attached to
+// their frame, and that multi-line locals and later output are not.
+func TestParseBacktraceLocals(t *testing.T) {
+	output := `Thread 1 (Thread 0x7f2a1b2c3d40 (LWP 4242)):
+#0  0x00000000004005a4 in ExecProcNode (node=0x0) at execProcnode.c:412
+        result = <optimized out>
+        name = 0x2b3c000 "a = {b}"
+#1  ExecutePlan (estate=0x2b3c4d0, planstate=0x0) at execMain.c:1632
+        slot = 0x0
+        state = {
+          count = 1,
+          done = false
+        }
+        current_tuple_count = 7
+#2  0x00000000004a1b2c in standard_ExecutorRun () at execMain.c:350
+No locals.
+$1 = {
+  si_signo = 11,
+  si_errno = 0
+}
+`
+	threads, _ := parseBacktraces(output)
+	frames := threads["1"]
+	if len(frames) != 3 {
+		t.Fatalf("expected 3 frames, got %+v", frames)
+	}
+
+	expected := []struct {
+		args   map[string]string
+		locals map[string]string
+	}{
+		{map[string]string{"node": "0x0"}, map[string]string{"result": optimizedOut, "name": `0x2b3c000 "a = {b}"`}},
+		{map[string]string{"estate": "0x2b3c4d0", "planstate": "0x0"}, map[string]string{"slot": "0x0", "current_tuple_count": "7"}},
+		{nil, nil},
+	}
+	for i, want := range expected {
+		if !reflect.DeepEqual(frames[i].Args, want.args) {
+			t.Errorf("frame %d: expected args %v, got %v", i, want.args, frames[i].Args)
+		}
+		if !reflect.DeepEqual(frames[i].Locals, want.locals) {
+			t.Errorf("frame %d: expected locals %v, got %v", i, want.locals, frames[i].Locals)
+		}
+	}
+}
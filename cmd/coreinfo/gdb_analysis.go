@@ -1,167 +1,350 @@
 package coreinfo
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"regexp"
+	"slices"
+	"strings"
 )
 
 // getPostgresPath constructs the postgres binary path using GPHOME environment variable
 func getPostgresPath() (string, error) {
 	gphome := os.Getenv("GPHOME")
 	if gphome == "" {
-		return "", fmt.Errorf("GPHOME environment variable is not set")
+		return "", ErrGPHOMENotSet
 	}
 
 	postgresPath := filepath.Join(gphome, "bin", "postgres")
 	if _, err := os.Stat(postgresPath); os.IsNotExist(err) {
-		return "", fmt.Errorf("postgres binary not found: please ensure GPHOME is set and points to a valid Apache Cloudberry installation. Current GPHOME=%s", os.Getenv("GPHOME"))
+		return "", fmt.Errorf("%w: please ensure GPHOME is set and points to a valid Apache Cloudberry installation. Current GPHOME=%s", ErrPostgresNotFound, os.Getenv("GPHOME"))
 	}
 	return postgresPath, nil
 }
 
-// RunGDBAnalysisWithSummary performs GDB analysis and includes a summary at the top of the output.
-func RunGDBAnalysisWithSummary(coreFiles []string, fileInfos map[string]*FileInfo, customGDBFile string) error {
+// validateBinaryFlags checks the --binary and --binary-in-core-path flags.
+// --binary must point at an existing file, and --binary-in-core-path is
+// only meaningful together with --binary.
+func validateBinaryFlags() error {
+	if binaryInCorePath != "" && binaryPath == "" {
+		return fmt.Errorf("--binary-in-core-path requires --binary")
+	}
+	if binaryPath != "" {
+		info, err := os.Stat(binaryPath)
+		if err != nil {
+			return fmt.Errorf("binary not found: %s", binaryPath)
+		}
+		if info.IsDir() {
+			return fmt.Errorf("binary is a directory: %s", binaryPath)
+		}
+	}
+	return nil
+}
+
+// resolveBinaryPath selects the binary gdb should load for a core file.
+//
+// Without --binary, the postgres binary under GPHOME is used. With --binary
+// alone, the given binary is used for every core. With both --binary and
+// --binary-in-core-path, the given binary replaces only the executable path
+// recorded in the core: cores whose recorded path matches use --binary, and
+// all other cores fall back to the GPHOME binary.
+//
+// The returned bool reports whether the binary was retargeted away from the
+// path recorded in the core.
+func resolveBinaryPath(fileInfo *FileInfo) (string, bool, error) {
+	if binaryPath != "" {
+		if binaryInCorePath == "" {
+			return binaryPath, true, nil
+		}
+		if fileInfo != nil && filepath.Clean(fileInfo.ExecPath) == filepath.Clean(binaryInCorePath) {
+			return binaryPath, true, nil
+		}
+	}
 
 	postgresPath, err := getPostgresPath()
 	if err != nil {
-		return fmt.Errorf("failed to get postgres binary path: %v", err)
+		return "", false, err
 	}
+	return postgresPath, false, nil
+}
 
-	for _, coreFile := range coreFiles {
-		var gdbFilePath string
+// movedBinaryArgs returns gdb arguments that make gdb use binary in place of
+// the executable recorded in the core when the recorded path no longer
+// exists, as after an in-place upgrade moved the installation, and a
+// description of the override. Shared libraries of the old installation
+// are looked up in the lib directory next to binary's bin directory.
+// Returns nil and "" if the recorded executable is unknown, is binary or
+// still exists.
+func movedBinaryArgs(fileInfo *FileInfo, binary string) ([]string, string) {
+	if fileInfo == nil || fileInfo.ExecPath == "" || filepath.Clean(fileInfo.ExecPath) == filepath.Clean(binary) {
+		return nil, ""
+	}
+	if _, err := os.Stat(fileInfo.ExecPath); err == nil {
+		return nil, ""
+	}
+	libDir := filepath.Join(filepath.Dir(filepath.Dir(binary)), "lib")
+	args := []string{
+		"-iex", "set exec-file-mismatch off",
+		"-iex", "set solib-search-path " + libDir,
+	}
+	return args, fmt.Sprintf("recorded executable %s is absent; using %s with libraries from %s", fileInfo.ExecPath, binary, libDir)
+}
 
-		// Select GDB file
-		if customGDBFile != "" {
-			gdbFilePath = customGDBFile
-		} else {
-			// Use embedded basic commands file
-			fileContent, err := gdbFiles.ReadFile("resources/gdb_commands_basic.txt")
-			if err != nil {
-				return fmt.Errorf("failed to read embedded GDB file: %v", err)
+// RunGDBAnalysisWithSummary performs GDB analysis and includes a summary at the top of the output.
+// Each analysis is rendered once per requested output format, and saved to
+// the output directory when one is set. With --post-url, each analysis is
+// also POSTed as JSON; a failed POST only fails the run with --post-required.
+func RunGDBAnalysisWithSummary(coreFiles []string, fileInfos map[string]*FileInfo, customGDBFile string, formats []string) error {
+	var skipped []string
+	client := &http.Client{Timeout: postTimeout}
+	dirs := newCoreDirs(outputDir)
+	var appendLog *analysisLog
+	if appendLogPath != "" {
+		var err error
+		if appendLog, err = openAnalysisLog(appendLogPath, int64(appendLogMaxMB)<<20); err != nil {
+			return err
+		}
+		defer appendLog.Close()
+	}
+	for _, coreFile := range coreFiles {
+		analysis, _, err := analyzeCore(coreFile, fileInfos[coreFile], customGDBFile)
+		if errors.Is(err, ErrCoreLoadFailed) || errors.Is(err, ErrPermissionDenied) {
+			// Skip cores gdb cannot use and continue with the rest
+			fmt.Fprintf(os.Stderr, "Skipping core: %v\n", err)
+			skipped = append(skipped, coreFile)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if warnOnStderr(formats) {
+			for _, warning := range analysis.Warnings {
+				fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
 			}
-
-			// Create a temporary file for the GDB commands
-			tmpFile, err := os.CreateTemp("", "gdb_commands_basic_*.txt")
-			if err != nil {
-				return fmt.Errorf("failed to create temporary file: %v", err)
+		}
+		dir := outputDir
+		if perCoreDir {
+			dir = dirs.dir(coreFile)
+		}
+		if err := writeAnalysis(os.Stdout, analysis, formats, dir); err != nil {
+			return err
+		}
+		if perCoreDir {
+			if err := saveRawGDBOutput(os.Stdout, analysis, dir); err != nil {
+				return err
 			}
-			defer os.Remove(tmpFile.Name()) // Ensure cleanup
-
-			if _, err := tmpFile.Write(fileContent); err != nil {
-				return fmt.Errorf("failed to write to temporary file: %v", err)
+		}
+		if appendLog != nil {
+			if err := appendLog.Append(analysis); err != nil {
+				return err
 			}
-			if err := tmpFile.Close(); err != nil {
-				return fmt.Errorf("failed to close temporary file: %v", err)
+		}
+		if postURL != "" {
+			if err := postAnalysis(client, postURL, postHeaders, analysis); err != nil {
+				if postRequired {
+					return err
+				}
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
 			}
-
-			gdbFilePath = tmpFile.Name()
 		}
-
-		// Run GDB command
-		gdbCmd := exec.Command("gdb", "-q", "-x", gdbFilePath, postgresPath, coreFile)
-		output, err := gdbCmd.CombinedOutput()
-		if err != nil {
-			return fmt.Errorf("failed to run GDB on %s: %v", coreFile, err)
+		if interactive {
+			if err := startInteractiveSession(analysis); err != nil {
+				return err
+			}
 		}
+	}
 
-		// Extract and print summary
-		summary, err := extractCoreSummary(string(output), fileInfos[coreFile], coreFile)
-		if err != nil {
-			return fmt.Errorf("failed to extract core summary for %s: %v", coreFile, err)
-		}
-		fmt.Println(summary)
+	if len(skipped) > 0 {
+		return fmt.Errorf("%w: skipped %d of %d core(s): %s", ErrCoreLoadFailed, len(skipped), len(coreFiles), strings.Join(skipped, ", "))
+	}
+	return nil
+}
 
-		// Print the full GDB output after the summary
-		fmt.Println("\n======================================================================")
-		fmt.Println("=== Detailed GDB Output ===")
-		fmt.Print("======================================================================\n\n")
+// warnOnStderr reports whether analysis warnings are printed to stderr. The
+// text and markdown reports list them, so they are printed only when
+// every requested format is structured.
+func warnOnStderr(formats []string) bool {
+	return !slices.Contains(formats, formatText) && !slices.Contains(formats, formatMarkdown)
+}
 
-		fmt.Println(string(output))
+// printGDBInvocation writes the gdb invocation of a core's analysis to w
+// for --verbose: the binary, the command file, the commands and command
+// files injected before it (such as solib and symbol settings), the
+// --gdb-eval commands, and the full argv as a shell command.
+func printGDBInvocation(w io.Writer, coreFile, binary, commandFile string, args []string) {
+	fmt.Fprintf(w, "GDB invocation for core file %s:\n", coreFile)
+	fmt.Fprintf(w, "  Binary: %s\n", binary)
+	fmt.Fprintf(w, "  Command file: %s\n", commandFile)
+	for i := 0; i+1 < len(args); i++ {
+		if args[i] == "-x" && args[i+1] == commandFile {
+			break
+		}
+		switch args[i] {
+		case "-iex", "-ex":
+			fmt.Fprintf(w, "  Injected command: %s\n", args[i+1])
+			i++
+		case "-x":
+			fmt.Fprintf(w, "  Injected command file: %s\n", args[i+1])
+			i++
+		}
 	}
+	for _, command := range gdbEvalCommands {
+		fmt.Fprintf(w, "  --gdb-eval: %s\n", command)
+	}
+	quoted := []string{"gdb"}
+	for _, arg := range args {
+		quoted = append(quoted, shellQuote(arg))
+	}
+	fmt.Fprintf(w, "  Command: %s\n", strings.Join(quoted, " "))
+}
 
-	return nil
+// shellQuote quotes s for a POSIX shell unless it consists of characters
+// that need no quoting.
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_-./=:,+@%") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
 }
 
-var (
-	binaryRegex    = regexp.MustCompile("Core was generated by `(.+): .+\\'")
-	signalRegex    = regexp.MustCompile(`Program terminated with signal (\w+), (.+)`)
-	faultAddrRegex = regexp.MustCompile(`si_addr = ([^,]+)`)
-	threadIDRegex  = regexp.MustCompile(`Current thread is (\d+)`)
-	argsRegex      = regexp.MustCompile("Core was generated by `.*: ([^']+)\\'")
-)
+// gdbTarget returns the binary to load coreFile with and the gdb arguments
+// that keep gdb from swapping in the executable recorded in the core, along
+// with the recorded path that was overridden ("" if none). It checks that
+// the core and binary are readable and of the same ELF class.
+func gdbTarget(coreFile string, fileInfo *FileInfo) (string, []string, string, error) {
+	postgresPath, retargeted, err := resolveBinaryPath(fileInfo)
+	if err != nil {
+		return "", nil, "", fmt.Errorf("failed to get postgres binary path: %w", err)
+	}
+	if verbose && retargeted {
+		fmt.Printf("Using binary %s for core file %s\n", postgresPath, coreFile)
+	}
 
-func extractCoreSummary(gdbOutput string, fileInfo *FileInfo, coreFile string) (string, error) {
-	var binary, signal, faultAddr, threadID, processArgs string
+	// Report unreadable files with a hint instead of a generic gdb failure
+	for _, path := range []string{coreFile, postgresPath} {
+		if err := checkReadable(path); err != nil {
+			return "", nil, "", err
+		}
+	}
 
-	// Match and extract relevant information
-	if match := binaryRegex.FindStringSubmatch(gdbOutput); len(match) > 1 {
-		binary = match[1]
-	} else {
-		return "", fmt.Errorf("failed to extract binary information")
+	if err := checkELFClassMatch(coreFile, fileInfo, postgresPath); err != nil {
+		return "", nil, "", err
 	}
 
-	if match := signalRegex.FindStringSubmatch(gdbOutput); len(match) > 2 {
-		signal = fmt.Sprintf("%s (%s)", match[1], match[2])
-	} else {
-		signal = "Unknown signal"
+	// When the binary differs from the path recorded in the core, stop
+	// gdb from swapping in the recorded executable.
+	if retargeted {
+		return postgresPath, []string{"-iex", "set exec-file-mismatch off"}, "", nil
+	}
+	// The recorded executable may have moved with the installation
+	mismatchArgs, binaryOverride := movedBinaryArgs(fileInfo, postgresPath)
+	if verbose && binaryOverride != "" {
+		fmt.Printf("Binary override for core file %s: %s\n", coreFile, binaryOverride)
 	}
+	return postgresPath, mismatchArgs, binaryOverride, nil
+}
 
-	if match := faultAddrRegex.FindStringSubmatch(gdbOutput); len(match) > 1 {
-		faultAddr = match[1]
-	} else {
-		faultAddr = "N/A"
+// analyzeCore runs gdb against a single core file and parses the transcript.
+// It returns the analysis and the path of the binary gdb was given.
+func analyzeCore(coreFile string, fileInfo *FileInfo, customGDBFile string) (*CoreAnalysis, string, error) {
+	var gdbFilePath string
+
+	postgresPath, mismatchArgs, binaryOverride, err := gdbTarget(coreFile, fileInfo)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Check that gdb can use the core before running the full command file.
+	// The probe also reads the signal used to pick a preset.
+	stop := timings.track("gdb load probe " + coreFile)
+	signal, err := probeCoreLoad(mismatchArgs, postgresPath, coreFile)
+	stop()
+	if err != nil {
+		return nil, "", err
 	}
 
-	if match := threadIDRegex.FindStringSubmatch(gdbOutput); len(match) > 1 {
-		threadID = match[1]
+	// Select GDB file
+	if customGDBFile != "" {
+		gdbFilePath = customGDBFile
 	} else {
-		threadID = "N/A"
+		preset := selectGDBPreset(signal)
+		if verbose && len(gdbBySignal) > 0 {
+			fmt.Printf("Using GDB preset %s for core file %s (signal %s)\n", preset, coreFile, valueOrNA(signal))
+		}
+
+		if _, ok := gdbPresets[preset]; ok {
+			// Use the embedded commands file of the preset
+			gdbFilePath, err = writeEmbeddedGDBFile(gdbPresets[preset])
+			if err != nil {
+				return nil, "", err
+			}
+		} else {
+			gdbFilePath = preset
+		}
 	}
 
-	if match := argsRegex.FindStringSubmatch(gdbOutput); len(match) > 1 {
-		processArgs = match[1]
+	// Run GDB command. gdb runs -ex and -x in order and the command file
+	// ends with 'quit', so every probe's arguments must come before -x;
+	// only the --gdb-eval commands follow it, with the 'quit' removed.
+	gdbArgs := append([]string{"-q"}, mismatchArgs...)
+	symbolArgs, symbolSource := resolveSymbols(postgresPath)
+	gdbArgs = append(gdbArgs, symbolArgs...)
+	gdbArgs = append(gdbArgs, versionProbeArgs()...)
+	gdbArgs = append(gdbArgs, abortMessageArgs(signal)...)
+	if includeOpenFiles {
+		args, err := openFilesArgs()
+		if err != nil {
+			return nil, "", err
+		}
+		gdbArgs = append(gdbArgs, args...)
+	}
+	if includeMemoryMap {
+		gdbArgs = append(gdbArgs, memoryMapArgs()...)
+	}
+	if len(gdbEvalCommands) > 0 {
+		// Run the extra commands once the command file has finished
+		gdbFilePath, err = withoutQuit(gdbFilePath)
+		if err != nil {
+			return nil, "", err
+		}
+		gdbArgs = append(gdbArgs, "-x", gdbFilePath)
+		gdbArgs = append(gdbArgs, gdbEvalArgs(gdbEvalCommands)...)
 	} else {
-		processArgs = "N/A"
-	}
-
-	platform := "unknown"
-	userInfo := "unknown"
-	execPath := "unknown"
-	if fileInfo != nil {
-		platform = fileInfo.Platform
-		userInfo = fmt.Sprintf("uid=%s(%s), gid=%s(%s)",
-			fileInfo.RealUID, fileInfo.EffUID,
-			fileInfo.RealGID, fileInfo.EffGID)
-		execPath = fileInfo.ExecPath
-	}
-
-	// Format the summary
-	summary := fmt.Sprintf(`
-======================================================================
-Apache Cloudberry Core Dump Analysis Summary
-======================================================================
-
-- Core File: %s
-- Binary: %s
-- Platform: %s
-- User/Group: %s
-- Binary Path: %s
-- Signal: %s
-- Faulting Address: %s
-- Thread ID: %s
-- Process Args: %s`,
-		coreFile,
-		binary,
-		platform,
-		userInfo,
-		execPath,
-		signal,
-		faultAddr,
-		threadID,
-		processArgs)
-
-	return summary, nil
+		gdbArgs = append(gdbArgs, "-x", gdbFilePath)
+	}
+	gdbArgs = append(gdbArgs, postgresPath, coreFile)
+	if verbose {
+		printGDBInvocation(os.Stderr, coreFile, postgresPath, gdbFilePath, gdbArgs)
+	}
+	gdbCmd := exec.Command("gdb", gdbArgs...)
+	stop = timings.track("gdb analysis " + coreFile)
+	output, err := gdbCmd.CombinedOutput()
+	stop()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to run GDB on %s: %v", coreFile, err)
+	}
+
+	// Parse the transcript
+	analysis, err := parseCoreAnalysis(string(output), fileInfo, coreFile)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to extract core summary for %s: %v", coreFile, err)
+	}
+	analysis.Hostname = coreHostname(coreFile)
+	// A binary from an archive is never run, so its version stays unknown
+	if postgresPath != archiveBinary {
+		stop = timings.track(postgresPath + " --gp-version")
+		analysis.BinaryVersion = getBinaryVersion(postgresPath)
+		stop()
+	}
+	analysis.SymbolSource = symbolSource
+	analysis.GDBSessionArgs = append(append(append([]string{"-q"}, mismatchArgs...), symbolArgs...), postgresPath, coreFile)
+	analysis.BinaryOverride = binaryOverride
+	analysis.ExtraCommands = extractEvalOutputs(string(output), gdbEvalCommands)
+	analysis.KnownIssues = matchKnownIssues(analysis, knownIssues)
+	analysis.Warnings = analysisWarnings(analysis, postgresPath, gdbEvalCommands)
+	return analysis, postgresPath, nil
 }
@@ -0,0 +1,209 @@
+package coreinfo
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestResolveBinaryPath validates binary selection for --binary and --binary-in-core-path.
+func TestResolveBinaryPath(t *testing.T) {
+	originalGPHOME := os.Getenv("GPHOME")
+	defer os.Setenv("GPHOME", originalGPHOME)
+	defer func() { binaryPath, binaryInCorePath = "", "" }()
+
+	gphome := t.TempDir()
+	gphomePostgres := filepath.Join(gphome, "bin", "postgres")
+	if err := os.MkdirAll(filepath.Dir(gphomePostgres), 0755); err != nil {
+		t.Fatalf("Failed to create bin directory: %v", err)
+	}
+	if err := os.WriteFile(gphomePostgres, []byte{}, 0755); err != nil {
+		t.Fatalf("Failed to write mock postgres: %v", err)
+	}
+	os.Setenv("GPHOME", gphome)
+
+	archived := filepath.Join(t.TempDir(), "postgres")
+	matching := &FileInfo{ExecPath: "/usr/local/cloudberry-db-1.6.0/bin/postgres"}
+	other := &FileInfo{ExecPath: "/opt/other/bin/postgres"}
+
+	tests := []struct {
+		name           string
+		binary         string
+		binaryInCore   string
+		fileInfo       *FileInfo
+		wantPath       string
+		wantRetargeted bool
+	}{
+		{"default uses GPHOME", "", "", matching, gphomePostgres, false},
+		{"binary applies to every core", archived, "", other, archived, true},
+		{"mapped path matches", archived, "/usr/local/cloudberry-db-1.6.0/bin/postgres", matching, archived, true},
+		{"mapped path does not match", archived, "/usr/local/cloudberry-db-1.6.0/bin/postgres", other, gphomePostgres, false},
+		{"missing file info", archived, "/usr/local/cloudberry-db-1.6.0/bin/postgres", nil, gphomePostgres, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			binaryPath, binaryInCorePath = tt.binary, tt.binaryInCore
+
+			path, retargeted, err := resolveBinaryPath(tt.fileInfo)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if path != tt.wantPath || retargeted != tt.wantRetargeted {
+				t.Errorf("resolveBinaryPath() = (%s, %v), want (%s, %v)", path, retargeted, tt.wantPath, tt.wantRetargeted)
+			}
+		})
+	}
+}
+
+// TestGetPostgresPathUnset validates that an unset GPHOME is reported with ErrGPHOMENotSet.
+func TestGetPostgresPathUnset(t *testing.T) {
+	t.Setenv("GPHOME", "")
+	if _, err := getPostgresPath(); !errors.Is(err, ErrGPHOMENotSet) {
+		t.Errorf("Expected ErrGPHOMENotSet for unset GPHOME, got: %v", err)
+	}
+}
+
+// TestValidateBinaryFlags validates error handling for inconsistent binary flags.
+func TestValidateBinaryFlags(t *testing.T) {
+	defer func() { binaryPath, binaryInCorePath = "", "" }()
+
+	binaryPath, binaryInCorePath = "", "/usr/local/cloudberry-db/bin/postgres"
+	if err := validateBinaryFlags(); err == nil {
+		t.Error("Expected error for --binary-in-core-path without --binary")
+	}
+
+	binaryPath, binaryInCorePath = "/nonexistent/postgres", ""
+	if err := validateBinaryFlags(); err == nil {
+		t.Error("Expected error for missing --binary file")
+	}
+
+	binaryPath = t.TempDir()
+	if err := validateBinaryFlags(); err == nil {
+		t.Error("Expected error for --binary pointing at a directory")
+	}
+}
+
+// TestMovedBinaryArgs validates the override for cores whose recorded executable has moved.
+func TestMovedBinaryArgs(t *testing.T) {
+	gphome := t.TempDir()
+	binary := filepath.Join(gphome, "bin", "postgres")
+	present := filepath.Join(t.TempDir(), "postgres")
+	if err := os.WriteFile(present, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		fileInfo *FileInfo
+		override bool
+	}{
+		{name: "no file info"},
+		{name: "unknown executable", fileInfo: &FileInfo{}},
+		{name: "recorded executable is the binary", fileInfo: &FileInfo{ExecPath: binary}},
+		{name: "recorded executable present", fileInfo: &FileInfo{ExecPath: present}},
+		{name: "recorded executable moved", fileInfo: &FileInfo{ExecPath: "/old/path/bin/postgres"}, override: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, override := movedBinaryArgs(tt.fileInfo, binary)
+			if !tt.override {
+				if args != nil || override != "" {
+					t.Errorf("Expected no override, got %v (%q)", args, override)
+				}
+				return
+			}
+			joined := strings.Join(args, " ")
+			if !strings.Contains(joined, "set exec-file-mismatch off") || !strings.Contains(joined, "set solib-search-path "+filepath.Join(gphome, "lib")) {
+				t.Errorf("Unexpected override arguments %v", args)
+			}
+			if !strings.Contains(override, "/old/path/bin/postgres") || !strings.Contains(override, binary) {
+				t.Errorf("Expected override to name both executables, got %q", override)
+			}
+		})
+	}
+
+	// Unresolved symbols after an override are reported
+	analysis := &CoreAnalysis{
+		SymbolSource:   symbolSourceInline,
+		BinaryOverride: "recorded executable /old/path/bin/postgres is absent",
+		CrashedThread:  []StackFrame{{Function: "??"}, {Function: "??"}},
+	}
+	if warnings := analysisWarnings(analysis, binary, nil); len(warnings) != 1 || !strings.Contains(warnings[0], "did not resolve") {
+		t.Errorf("Expected an unresolved symbols warning, got %v", warnings)
+	}
+	analysis.CrashedThread[1].Function = "ExecProcNode"
+	if warnings := analysisWarnings(analysis, binary, nil); len(warnings) != 0 {
+		t.Errorf("Expected no warnings once symbols resolve, got %v", warnings)
+	}
+}
+
+// TestWarnOnStderr validates that warnings go to stderr only when no
+// requested format lists them in the report.
+func TestWarnOnStderr(t *testing.T) {
+	tests := []struct {
+		formats []string
+		stderr  bool
+	}{
+		{[]string{formatText}, false},
+		{[]string{formatMarkdown}, false},
+		{[]string{formatJSON, formatText}, false},
+		{[]string{formatJSON}, true},
+	}
+	for _, tt := range tests {
+		if got := warnOnStderr(tt.formats); got != tt.stderr {
+			t.Errorf("warnOnStderr(%v) = %v, expected %v", tt.formats, got, tt.stderr)
+		}
+	}
+}
+
+// TestPrintGDBInvocation validates the --verbose breakdown of a gdb invocation.
+func TestPrintGDBInvocation(t *testing.T) {
+	gdbEvalCommands = []string{"p MyProcPid"}
+	defer func() { gdbEvalCommands = nil }()
+
+	args := []string{
+		"-q",
+		"-iex", "set exec-file-mismatch off",
+		"-iex", "set solib-search-path /opt/cloudberry/lib",
+		"-x", "/tmp/run/gdb_commands_open_files.txt",
+		"-x", "/tmp/run/eval_gdb_commands_basic.txt",
+		"-ex", `echo \ncbtoolbox-eval-begin: 0\n`, "-ex", "p MyProcPid",
+		"/opt/cloudberry/bin/postgres", "/var/crash/core.4242",
+	}
+	var stderr bytes.Buffer
+	printGDBInvocation(&stderr, "/var/crash/core.4242", "/opt/cloudberry/bin/postgres", "/tmp/run/eval_gdb_commands_basic.txt", args)
+
+	expected := []string{
+		"GDB invocation for core file /var/crash/core.4242:",
+		"  Binary: /opt/cloudberry/bin/postgres",
+		"  Command file: /tmp/run/eval_gdb_commands_basic.txt",
+		"  Injected command: set exec-file-mismatch off",
+		"  Injected command: set solib-search-path /opt/cloudberry/lib",
+		"  Injected command file: /tmp/run/gdb_commands_open_files.txt",
+		"  --gdb-eval: p MyProcPid",
+		`  Command: gdb -q -iex 'set exec-file-mismatch off' -iex 'set solib-search-path /opt/cloudberry/lib' -x /tmp/run/gdb_commands_open_files.txt -x /tmp/run/eval_gdb_commands_basic.txt -ex 'echo \ncbtoolbox-eval-begin: 0\n' -ex 'p MyProcPid' /opt/cloudberry/bin/postgres /var/crash/core.4242`,
+	}
+	if got := strings.TrimRight(stderr.String(), "\n"); got != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected invocation breakdown:\n%s", got)
+	}
+}
+
+// TestShellQuote validates quoting of gdb arguments for display.
+func TestShellQuote(t *testing.T) {
+	tests := map[string]string{
+		"/var/crash/core.4242": "/var/crash/core.4242",
+		"set confirm off":      "'set confirm off'",
+		"it's":                 `'it'\''s'`,
+		"":                     "''",
+	}
+	for input, expected := range tests {
+		if got := shellQuote(input); got != expected {
+			t.Errorf("shellQuote(%q) = %s, want %s", input, got, expected)
+		}
+	}
+}
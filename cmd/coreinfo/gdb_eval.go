@@ -0,0 +1,94 @@
+package coreinfo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Markers delimiting the output of each --gdb-eval command in the transcript.
+const (
+	evalBeginMarker = "cbtoolbox-eval-begin: "
+	evalEndMarker   = "cbtoolbox-eval-end: "
+)
+
+// gdbEvalCommands holds the commands given with the repeatable --gdb-eval flag.
+var gdbEvalCommands []string
+
+var evalOutputRegex = regexp.MustCompile(`(?s)` + regexp.QuoteMeta(evalBeginMarker) + `(\d+)\n(.*?)` + regexp.QuoteMeta(evalEndMarker) + `(\d+)`)
+
+// validateGDBEval trims the --gdb-eval commands and rejects empty commands
+// and commands spanning several lines, which could otherwise open or close
+// gdb command blocks ('define', 'while', 'end') around the generated markers.
+func validateGDBEval() error {
+	for i, command := range gdbEvalCommands {
+		command = strings.TrimSpace(command)
+		if command == "" {
+			return fmt.Errorf("--gdb-eval command must not be empty")
+		}
+		if strings.ContainsAny(command, "\r\n") {
+			return fmt.Errorf("--gdb-eval command must be a single line: %q", command)
+		}
+		gdbEvalCommands[i] = command
+	}
+	return nil
+}
+
+// gdbEvalArgs returns gdb arguments running each command between markers.
+// Each command is passed with its own -ex, so an error in one command does
+// not prevent the others from running. The arguments end with 'quit', and
+// must follow a command file with its own 'quit' removed (see withoutQuit).
+func gdbEvalArgs(commands []string) []string {
+	var args []string
+	for i, command := range commands {
+		args = append(args,
+			"-ex", fmt.Sprintf(`echo \n%s%d\n`, evalBeginMarker, i),
+			"-ex", command,
+			"-ex", fmt.Sprintf(`echo \n%s%d\n`, evalEndMarker, i))
+	}
+	return append(args, "-ex", "quit")
+}
+
+// withoutQuit writes a copy of a GDB command file without its 'quit'
+// commands, so that commands following it on the command line still run.
+// The copy is named eval_<name> in the run's temporary directory.
+// Returns the path of the copy.
+func withoutQuit(gdbFilePath string) (string, error) {
+	content, err := os.ReadFile(gdbFilePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read GDB file %s: %v", gdbFilePath, err)
+	}
+
+	var lines []string
+	for _, line := range strings.Split(string(content), "\n") {
+		if trimmed := strings.TrimSpace(line); trimmed == "quit" || trimmed == "q" {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	return writeGDBFile("eval_"+filepath.Base(gdbFilePath), []byte(strings.Join(lines, "\n")))
+}
+
+// extractEvalOutputs maps each --gdb-eval command to the output gdb printed
+// for it. Commands whose markers are missing are omitted.
+func extractEvalOutputs(gdbOutput string, commands []string) map[string]string {
+	if len(commands) == 0 {
+		return nil
+	}
+	outputs := make(map[string]string)
+	for _, match := range evalOutputRegex.FindAllStringSubmatch(gdbOutput, -1) {
+		if match[1] != match[3] {
+			continue
+		}
+		index, err := strconv.Atoi(match[1])
+		if err != nil || index >= len(commands) {
+			continue
+		}
+		outputs[commands[index]] = strings.TrimRight(match[2], "\n")
+	}
+	return outputs
+}
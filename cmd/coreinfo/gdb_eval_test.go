@@ -0,0 +1,109 @@
+package coreinfo
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestValidateGDBEval validates trimming and rejection of unsafe --gdb-eval commands.
+func TestValidateGDBEval(t *testing.T) {
+	tests := []struct {
+		name      string
+		commands  []string
+		expectErr bool
+		expected  []string
+	}{
+		{name: "trimmed", commands: []string{"  info sharedlibrary ", "p MyProcPid"}, expected: []string{"info sharedlibrary", "p MyProcPid"}},
+		{name: "empty", commands: []string{"  "}, expectErr: true},
+		{name: "multi-line", commands: []string{"end\ndefine x"}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gdbEvalCommands = append([]string(nil), tt.commands...)
+			defer func() { gdbEvalCommands = nil }()
+
+			err := validateGDBEval()
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("expected error: %v, got: %v", tt.expectErr, err)
+			}
+			if !tt.expectErr && strings.Join(gdbEvalCommands, "|") != strings.Join(tt.expected, "|") {
+				t.Errorf("expected %q, got %q", tt.expected, gdbEvalCommands)
+			}
+		})
+	}
+}
+
+// TestGDBEvalArgs validates that each command runs between its markers and gdb quits afterwards.
+func TestGDBEvalArgs(t *testing.T) {
+	args := gdbEvalArgs([]string{"p 1"})
+	expected := []string{
+		"-ex", `echo \ncbtoolbox-eval-begin: 0\n`,
+		"-ex", "p 1",
+		"-ex", `echo \ncbtoolbox-eval-end: 0\n`,
+		"-ex", "quit",
+	}
+	if strings.Join(args, "|") != strings.Join(expected, "|") {
+		t.Errorf("expected %q, got %q", expected, args)
+	}
+}
+
+// TestWithoutQuit validates that quit commands are removed from a command file copy.
+func TestWithoutQuit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "commands.gdb")
+	if err := os.WriteFile(path, []byte("bt\nset logging off\n  quit\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { cleanupGDBFiles(io.Discard) })
+	copyPath, err := withoutQuit(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if filepath.Base(copyPath) != "eval_commands.gdb" {
+		t.Errorf("expected copy named eval_commands.gdb, got %s", copyPath)
+	}
+
+	content, err := os.ReadFile(copyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(content), "quit") || !strings.Contains(string(content), "set logging off") {
+		t.Errorf("unexpected command file copy:\n%s", content)
+	}
+}
+
+// TestExtractEvalOutputs validates capturing the output of each --gdb-eval command.
+func TestExtractEvalOutputs(t *testing.T) {
+	output := sampleGDBOutput +
+		"\ncbtoolbox-eval-begin: 0\n$1 = 4242\n\ncbtoolbox-eval-end: 0\n" +
+		"\ncbtoolbox-eval-begin: 1\nNo symbol \"missing\" in current context.\n\ncbtoolbox-eval-end: 1\n"
+	commands := []string{"p MyProcPid", "p missing"}
+
+	outputs := extractEvalOutputs(output, commands)
+	if outputs["p MyProcPid"] != "$1 = 4242" {
+		t.Errorf("unexpected output for p MyProcPid: %q", outputs["p MyProcPid"])
+	}
+	if outputs["p missing"] != `No symbol "missing" in current context.` {
+		t.Errorf("unexpected output for p missing: %q", outputs["p missing"])
+	}
+
+	if extractEvalOutputs(output, nil) != nil {
+		t.Error("expected no outputs without --gdb-eval commands")
+	}
+
+	analysis, err := parseCoreAnalysis(sampleGDBOutput, nil, "core.1234")
+	if err != nil {
+		t.Fatal(err)
+	}
+	analysis.ExtraCommands = outputs
+	if text := renderText(analysis); !strings.Contains(text, "(gdb) p MyProcPid\n$1 = 4242") {
+		t.Errorf("expected extra command output in text, got:\n%s", text)
+	}
+	if md := renderMarkdown(analysis, false); !strings.Contains(md, "`p MyProcPid`\n\n```text\n$1 = 4242\n```") {
+		t.Errorf("expected extra command output in markdown, got:\n%s", md)
+	}
+}
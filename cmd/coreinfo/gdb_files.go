@@ -3,10 +3,12 @@ package coreinfo
 import (
 	"embed"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 )
 
-//go:embed resources/gdb_commands_basic.txt resources/gdb_commands_detailed.txt
+//go:embed resources/gdb_commands_basic.txt resources/gdb_commands_detailed.txt resources/gdb_commands_open_files.txt
 var gdbFiles embed.FS
 
 func extractGDBFile(filename string, outputPath string) error {
@@ -23,3 +25,55 @@ func extractGDBFile(filename string, outputPath string) error {
 	fmt.Printf("File %s extracted to %s\n", filename, outputPath)
 	return nil
 }
+
+// keepGDBFiles keeps the temporary GDB command files of a run (--keep-gdb-file).
+var keepGDBFiles bool
+
+// gdbFileDir is the temporary directory holding the GDB command files of
+// the current run. It is created on first use and removed with all its
+// files by cleanupGDBFiles.
+var gdbFileDir string
+
+// writeGDBFile writes content to the file name in the run's temporary
+// directory, creating the directory on first use, and returns its path.
+// Files have fixed names and are overwritten by later cores of the run,
+// so a kept directory shows the files of the last analyzed core.
+func writeGDBFile(name string, content []byte) (string, error) {
+	if gdbFileDir == "" {
+		dir, err := os.MkdirTemp("", "cbtoolbox-coreinfo-*")
+		if err != nil {
+			return "", fmt.Errorf("failed to create temporary directory: %v", err)
+		}
+		gdbFileDir = dir
+	}
+	path := filepath.Join(gdbFileDir, name)
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return "", fmt.Errorf("failed to write temporary file: %v", err)
+	}
+	return path, nil
+}
+
+// cleanupGDBFiles removes the run's temporary directory and all GDB command
+// files in it. With --keep-gdb-file the directory is kept and its path is
+// written to w instead.
+func cleanupGDBFiles(w io.Writer) {
+	if gdbFileDir == "" {
+		return
+	}
+	if keepGDBFiles {
+		fmt.Fprintf(w, "Kept GDB command files in %s\n", gdbFileDir)
+	} else {
+		os.RemoveAll(gdbFileDir)
+	}
+	gdbFileDir = ""
+}
+
+// writeEmbeddedGDBFile writes an embedded GDB command file under its own
+// name to the run's temporary directory and returns its path.
+func writeEmbeddedGDBFile(filename string) (string, error) {
+	fileContent, err := gdbFiles.ReadFile("resources/" + filename)
+	if err != nil {
+		return "", fmt.Errorf("failed to read embedded GDB file: %v", err)
+	}
+	return writeGDBFile(filename, fileContent)
+}
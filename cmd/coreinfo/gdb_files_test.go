@@ -0,0 +1,57 @@
+package coreinfo
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestGDBFileDir validates that the GDB command files of a run share one
+// temporary directory under predictable names, and that the directory is
+// removed at the end of the run unless kept.
+func TestGDBFileDir(t *testing.T) {
+	basic, err := writeEmbeddedGDBFile("gdb_commands_basic.txt")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	openFiles, err := openFilesArgs()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dir := gdbFileDir
+	if filepath.Dir(basic) != dir || filepath.Dir(openFiles[1]) != dir {
+		t.Errorf("expected files in %s, got %s and %s", dir, basic, openFiles[1])
+	}
+	if filepath.Base(basic) != "gdb_commands_basic.txt" || filepath.Base(openFiles[1]) != "gdb_commands_open_files.txt" {
+		t.Errorf("expected files named after the embedded files, got %s and %s", basic, openFiles[1])
+	}
+
+	var stderr bytes.Buffer
+	cleanupGDBFiles(&stderr)
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be removed, got %v", dir, err)
+	}
+	if stderr.Len() != 0 {
+		t.Errorf("expected no output, got %q", stderr.String())
+	}
+
+	keepGDBFiles = true
+	defer func() { keepGDBFiles = false }()
+	if _, err := writeEmbeddedGDBFile("gdb_commands_basic.txt"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dir = gdbFileDir
+	defer os.RemoveAll(dir)
+	cleanupGDBFiles(&stderr)
+	if _, err := os.Stat(filepath.Join(dir, "gdb_commands_basic.txt")); err != nil {
+		t.Errorf("expected kept file, got %v", err)
+	}
+	if !strings.Contains(stderr.String(), "Kept GDB command files in "+dir) {
+		t.Errorf("expected kept directory to be reported, got %q", stderr.String())
+	}
+	if gdbFileDir != "" {
+		t.Errorf("expected the next run to get a new directory")
+	}
+}
@@ -0,0 +1,148 @@
+package coreinfo
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// corePatternPath specifies the kernel's core file naming pattern.
+var corePatternPath = "/proc/sys/kernel/core_pattern"
+
+// corePattern is the --core-pattern flag: the core_pattern the cores were
+// written with, for cores collected from hosts whose setting differs from
+// the local one.
+var corePattern string
+
+// hostnameRegex matches core file names against the run's core_pattern,
+// capturing the hostname; nil when the pattern does not record it. It is
+// set once per run by loadCorePattern.
+var hostnameRegex *regexp.Regexp
+
+// byHost enables --by-host, which reports each core's crash signature
+// grouped by the host that generated it.
+var byHost bool
+
+// corePatternSpecifiers maps core_pattern specifiers to the regular
+// expressions matching their expansion; %h is captured separately.
+var corePatternSpecifiers = map[byte]string{
+	'p': `\d+`, 'P': `\d+`, 'i': `\d+`, 'I': `\d+`, 'u': `\d+`, 'g': `\d+`,
+	'd': `\d+`, 's': `\d+`, 't': `\d+`, 'c': `[^/]+?`, 'C': `[^/]+?`,
+	'e': `[^/]+?`, 'E': `[^/]+?`, 'f': `[^/]+?`,
+}
+
+// corePatternRegex converts a kernel core_pattern into a regular expression
+// matching the base names of the cores it produces, capturing the %h
+// hostname. The kernel expands %h from the crashing host's utsname, so the
+// name survives copying the core to a shared collection directory.
+// Returns nil if the pattern does not record the hostname or pipes cores
+// to a handler such as systemd-coredump.
+func corePatternRegex(pattern string) *regexp.Regexp {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" || strings.HasPrefix(pattern, "|") || !strings.Contains(pattern, "%h") {
+		return nil
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	base := filepath.Base(pattern)
+	for i := 0; i < len(base); i++ {
+		if base[i] != '%' || i+1 == len(base) {
+			b.WriteString(regexp.QuoteMeta(base[i : i+1]))
+			continue
+		}
+		i++
+		switch spec := base[i]; {
+		case spec == 'h':
+			b.WriteString(`(?P<host>[^/]+?)`)
+		case spec == '%':
+			b.WriteString("%")
+		case corePatternSpecifiers[spec] != "":
+			b.WriteString(corePatternSpecifiers[spec])
+		default:
+			b.WriteString(`[^/]*?`)
+		}
+	}
+	// With kernel.core_uses_pid the kernel appends the PID to patterns without %p
+	b.WriteString(`(?:\.\d+)?$`)
+	return regexp.MustCompile(b.String())
+}
+
+// loadCorePattern resolves the core_pattern that hostnames are taken from
+// for the run: --core-pattern, or else the local corePatternPath. An
+// unreadable local pattern leaves every hostname empty, while a
+// --core-pattern that does not record the hostname is an error.
+func loadCorePattern() error {
+	pattern := corePattern
+	if pattern == "" {
+		content, err := os.ReadFile(corePatternPath)
+		if err != nil {
+			hostnameRegex = nil
+			return nil
+		}
+		pattern = string(content)
+	}
+	hostnameRegex = corePatternRegex(pattern)
+	if corePattern != "" && hostnameRegex == nil {
+		return fmt.Errorf("--core-pattern %q does not record the hostname (%%h)", corePattern)
+	}
+	return nil
+}
+
+// coreHostname returns the hostname recorded in a core's file name by the
+// run's core_pattern (see loadCorePattern), or "" if the pattern does not
+// include %h or the name does not match it.
+func coreHostname(coreFile string) string {
+	if hostnameRegex == nil {
+		return ""
+	}
+	match := hostnameRegex.FindStringSubmatch(filepath.Base(coreFile))
+	if match == nil {
+		return ""
+	}
+	return match[hostnameRegex.SubexpIndex("host")]
+}
+
+// runByHost analyzes each core and writes a table of the cores grouped by
+// host, with each core's crash signature hash and the number of hosts the
+// signature was seen on. A signature seen on a single host of several
+// points at a host-specific problem such as failing hardware, while one
+// seen across hosts points at a software defect.
+func runByHost(w io.Writer, coreFiles []string, analyze func(coreFile string) (*CoreAnalysis, error)) error {
+	type hostCore struct {
+		host, core, hash, signature string
+	}
+
+	var cores []hostCore
+	hostsBySignature := make(map[string]map[string]bool)
+	for _, coreFile := range coreFiles {
+		analysis, err := analyze(coreFile)
+		if err != nil {
+			return err
+		}
+		signature, err := crashSignature(analysis)
+		if err != nil {
+			return err
+		}
+		entry := hostCore{host: valueOrNA(analysis.Hostname), core: coreFile, hash: signatureHash(signature), signature: signature}
+		cores = append(cores, entry)
+		if hostsBySignature[entry.hash] == nil {
+			hostsBySignature[entry.hash] = make(map[string]bool)
+		}
+		hostsBySignature[entry.hash][entry.host] = true
+	}
+
+	sort.SliceStable(cores, func(i, j int) bool { return cores[i].host < cores[j].host })
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "HOST\tCORE\tHASH\tHOSTS\tSIGNATURE")
+	for _, entry := range cores {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\n", entry.host, entry.core, entry.hash, len(hostsBySignature[entry.hash]), entry.signature)
+	}
+	return tw.Flush()
+}
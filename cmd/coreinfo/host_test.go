@@ -0,0 +1,123 @@
+package coreinfo
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestCoreHostname validates extraction of the hostname from core file
+// names produced by various kernel core_pattern settings.
+func TestCoreHostname(t *testing.T) {
+	originalPath := corePatternPath
+	defer func() { corePatternPath, hostnameRegex = originalPath, nil }()
+	corePatternPath = filepath.Join(t.TempDir(), "core_pattern")
+
+	tests := []struct {
+		name     string
+		pattern  string
+		coreFile string
+		expected string
+	}{
+		{"host and pid", "/var/crash/core.%e.%h.%p", "/collected/core.postgres.sdw1.4242", "sdw1"},
+		{"qualified hostname", "core-%h-%p-%t", "core-sdw2.example.com-4242-1700000000", "sdw2.example.com"},
+		{"pid appended by core_uses_pid", "core.%h", "core.sdw3.4242", "sdw3"},
+		{"literal percent", "core%%%h", "core%sdw4", "sdw4"},
+		{"no hostname in pattern", "core.%e.%p", "core.postgres.4242", ""},
+		{"piped to handler", "|/usr/lib/systemd/systemd-coredump %P %u %g %s %t %c %h", "core.postgres.4242", ""},
+		{"name not matching pattern", "core.%e.%h.%p", "crash.dump", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.WriteFile(corePatternPath, []byte(tt.pattern+"\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if err := loadCorePattern(); err != nil {
+				t.Fatalf("loadCorePattern() error = %v", err)
+			}
+			if host := coreHostname(tt.coreFile); host != tt.expected {
+				t.Errorf("coreHostname(%q) with pattern %q = %q, expected %q", tt.coreFile, tt.pattern, host, tt.expected)
+			}
+		})
+	}
+}
+
+// TestLoadCorePattern validates that the core_pattern is read once per run
+// and that --core-pattern replaces the local pattern.
+func TestLoadCorePattern(t *testing.T) {
+	originalPath := corePatternPath
+	defer func() { corePatternPath, corePattern, hostnameRegex = originalPath, "", nil }()
+	corePatternPath = filepath.Join(t.TempDir(), "core_pattern")
+	if err := os.WriteFile(corePatternPath, []byte("core.%e.%h.%p\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := loadCorePattern(); err != nil {
+		t.Fatalf("loadCorePattern() error = %v", err)
+	}
+	if err := os.WriteFile(corePatternPath, []byte("|/usr/lib/systemd/systemd-coredump %P %u %g %s %t %c %h\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if host := coreHostname("core.postgres.sdw1.4242"); host != "sdw1" {
+		t.Errorf("expected the pattern loaded at the start of the run, got %q", host)
+	}
+
+	// Cores collected from hosts whose pattern differs from the local one
+	corePattern = "core.%e.%h.%p"
+	if err := loadCorePattern(); err != nil {
+		t.Fatalf("loadCorePattern() error = %v", err)
+	}
+	if host := coreHostname("core.postgres.sdw2.4242"); host != "sdw2" {
+		t.Errorf("expected the hostname from --core-pattern, got %q", host)
+	}
+
+	corePattern = "core.%e.%p"
+	if err := loadCorePattern(); err == nil {
+		t.Error("expected an error for a --core-pattern without %h")
+	}
+
+	corePattern, corePatternPath = "", filepath.Join(t.TempDir(), "missing")
+	if err := loadCorePattern(); err != nil || coreHostname("core.postgres.sdw1.4242") != "" {
+		t.Errorf("expected no hostnames without a readable core_pattern, got error %v", err)
+	}
+}
+
+// TestRunByHost validates grouping by host and counting the hosts each signature was seen on.
+func TestRunByHost(t *testing.T) {
+	hosts := map[string]string{"core.a": "sdw2", "core.b": "sdw1", "core.c": "sdw1"}
+	signals := map[string]string{"core.a": "SIGSEGV", "core.b": "SIGSEGV", "core.c": "SIGABRT"}
+
+	var buf bytes.Buffer
+	err := runByHost(&buf, []string{"core.a", "core.b", "core.c"}, func(coreFile string) (*CoreAnalysis, error) {
+		return &CoreAnalysis{
+			CoreFile:      coreFile,
+			Hostname:      hosts[coreFile],
+			Signal:        signals[coreFile],
+			CrashedThread: []StackFrame{{Function: "ExecProcNode"}},
+		}, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "HOST") {
+		t.Fatalf("expected a header and 3 rows, got:\n%s", buf.String())
+	}
+	expected := []struct {
+		host, core, hosts string
+	}{
+		{"sdw1", "core.b", "2"},
+		{"sdw1", "core.c", "1"},
+		{"sdw2", "core.a", "2"},
+	}
+	for i, want := range expected {
+		fields := strings.Fields(lines[i+1])
+		if len(fields) != 5 || fields[0] != want.host || fields[1] != want.core || fields[3] != want.hosts {
+			t.Errorf("row %d: expected host %s, core %s seen on %s hosts, got %q", i, want.host, want.core, want.hosts, lines[i+1])
+		}
+	}
+}
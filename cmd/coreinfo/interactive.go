@@ -0,0 +1,54 @@
+package coreinfo
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+)
+
+// interactive enables --interactive, which opens a gdb session on each
+// core after printing its analysis.
+var interactive bool
+
+// isTerminal reports whether f is a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// validateInteractive checks that --interactive can talk to a user.
+func validateInteractive() error {
+	if interactive && !isTerminal(os.Stdin) {
+		return fmt.Errorf("--interactive requires a terminal on stdin")
+	}
+	return nil
+}
+
+// runGDBSession runs gdb with the terminal attached until the user quits;
+// tests replace it.
+var runGDBSession = func(args []string) error {
+	cmd := exec.Command("gdb", args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return cmd.Run()
+}
+
+// startInteractiveSession opens gdb on an analyzed core with the binary,
+// symbols and settings the analysis used, so the user can continue where
+// the command file stopped. Ctrl-C interrupts gdb rather than coreinfo.
+// The exit status of gdb is the user's choice and is not an error.
+// Returns an error if gdb cannot be started.
+func startInteractiveSession(analysis *CoreAnalysis) error {
+	fmt.Fprintf(os.Stderr, "Starting gdb on %s; quit gdb to continue\n", analysis.CoreFile)
+
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+
+	var exitErr *exec.ExitError
+	if err := runGDBSession(analysis.GDBSessionArgs); err != nil && !errors.As(err, &exitErr) {
+		return fmt.Errorf("failed to start gdb on %s: %w", analysis.CoreFile, err)
+	}
+	return nil
+}
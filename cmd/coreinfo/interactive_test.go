@@ -0,0 +1,47 @@
+package coreinfo
+
+import (
+	"errors"
+	"os/exec"
+	"reflect"
+	"testing"
+)
+
+// TestStartInteractiveSession validates that gdb is reopened with the
+// analysis's arguments, and that only a failure to start gdb is an error.
+func TestStartInteractiveSession(t *testing.T) {
+	original := runGDBSession
+	defer func() { runGDBSession = original }()
+
+	analysis := &CoreAnalysis{CoreFile: "core.1", GDBSessionArgs: []string{"-q", "/usr/local/cloudberry/bin/postgres", "core.1"}}
+	exitErr := exec.Command("sh", "-c", "exit 1").Run()
+	tests := []struct {
+		name      string
+		err       error
+		expectErr bool
+	}{
+		{name: "user quits"},
+		{name: "gdb exits non-zero", err: exitErr},
+		{name: "gdb missing", err: exec.ErrNotFound, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotArgs []string
+			runGDBSession = func(args []string) error {
+				gotArgs = args
+				return tt.err
+			}
+			err := startInteractiveSession(analysis)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("startInteractiveSession() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if err != nil && !errors.Is(err, exec.ErrNotFound) {
+				t.Errorf("expected the start failure to be wrapped, got %v", err)
+			}
+			if !reflect.DeepEqual(gotArgs, analysis.GDBSessionArgs) {
+				t.Errorf("gdb args = %q, expected %q", gotArgs, analysis.GDBSessionArgs)
+			}
+		})
+	}
+}
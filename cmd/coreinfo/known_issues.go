@@ -0,0 +1,99 @@
+package coreinfo
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+//go:embed resources/known_issues.yaml
+var defaultKnownIssues []byte
+
+// knownIssuesPath is the --known-issues table replacing the embedded one.
+var knownIssuesPath string
+
+// knownIssues is the table analyses are matched against, loaded by
+// loadKnownIssues.
+var knownIssues []KnownIssue
+
+// KnownIssue maps a crash to the issue tracking it. All non-empty
+// criteria must match.
+type KnownIssue struct {
+	Signal    string `yaml:"signal"`
+	Function  string `yaml:"function"`
+	Signature string `yaml:"signature"`
+	Issue     string `yaml:"issue"`
+}
+
+// parseKnownIssues parses a known issues table.
+// Returns an error if an entry has no issue or no criteria.
+func parseKnownIssues(data []byte) ([]KnownIssue, error) {
+	var table struct {
+		Issues []KnownIssue `yaml:"issues"`
+	}
+	if err := yaml.UnmarshalStrict(data, &table); err != nil {
+		return nil, err
+	}
+	for i, entry := range table.Issues {
+		if entry.Issue == "" {
+			return nil, fmt.Errorf("entry %d has no issue", i+1)
+		}
+		if entry.Signal == "" && entry.Function == "" && entry.Signature == "" {
+			return nil, fmt.Errorf("entry %d (%s) needs a signal, function or signature", i+1, entry.Issue)
+		}
+		table.Issues[i].Signature = strings.ToLower(entry.Signature)
+	}
+	return table.Issues, nil
+}
+
+// loadKnownIssues loads the --known-issues table, or the embedded table
+// without it, into knownIssues.
+func loadKnownIssues() error {
+	data, source := defaultKnownIssues, "embedded known issues"
+	if knownIssuesPath != "" {
+		var err error
+		if data, err = os.ReadFile(knownIssuesPath); err != nil {
+			return fmt.Errorf("--known-issues: %w", err)
+		}
+		source = knownIssuesPath
+	}
+	table, err := parseKnownIssues(data)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", source, err)
+	}
+	knownIssues = table
+	return nil
+}
+
+// matchKnownIssues returns the issues of the table entries matching an
+// analysis, in table order and without duplicates.
+func matchKnownIssues(analysis *CoreAnalysis, table []KnownIssue) []string {
+	var signal, function, hash string
+	if fields := strings.Fields(analysis.Signal); len(fields) > 0 {
+		signal = fields[0]
+	}
+	if len(analysis.CrashedThread) > 0 {
+		function = analysis.CrashedThread[0].Function
+	}
+	if signature, err := crashSignature(analysis); err == nil {
+		hash = signatureHash(signature)
+	}
+
+	var issues []string
+	seen := make(map[string]bool)
+	for _, entry := range table {
+		if (entry.Signal != "" && entry.Signal != signal) ||
+			(entry.Function != "" && entry.Function != function) ||
+			(entry.Signature != "" && entry.Signature != hash) {
+			continue
+		}
+		if !seen[entry.Issue] {
+			seen[entry.Issue] = true
+			issues = append(issues, entry.Issue)
+		}
+	}
+	return issues
+}
@@ -0,0 +1,83 @@
+package coreinfo
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestMatchKnownIssues validates matching on signal, top function and
+// signature hash, with all criteria of an entry required.
+func TestMatchKnownIssues(t *testing.T) {
+	analysis := &CoreAnalysis{
+		Signal:        "SIGSEGV (Segmentation fault)",
+		CrashedThread: []StackFrame{{Function: "ExecProcNode"}, {Function: "ExecutePlan"}},
+	}
+	signature, _ := crashSignature(analysis)
+	hash := signatureHash(signature)
+
+	table := []KnownIssue{
+		{Signal: "SIGSEGV", Function: "ExecProcNode", Issue: "issue-1"},
+		{Signal: "SIGABRT", Function: "ExecProcNode", Issue: "issue-2"},
+		{Function: "ExecutePlan", Issue: "issue-3"},
+		{Signature: hash, Issue: "issue-4"},
+		{Signal: "SIGSEGV", Issue: "issue-1"},
+	}
+	expected := []string{"issue-1", "issue-4"}
+	if got := matchKnownIssues(analysis, table); !reflect.DeepEqual(got, expected) {
+		t.Errorf("matchKnownIssues() = %q, expected %q", got, expected)
+	}
+
+	if got := matchKnownIssues(&CoreAnalysis{Signal: "Unknown signal"}, table); got != nil {
+		t.Errorf("expected no match without a backtrace, got %q", got)
+	}
+}
+
+// TestLoadKnownIssues validates loading the embedded table and a
+// --known-issues file, and rejecting invalid entries.
+func TestLoadKnownIssues(t *testing.T) {
+	defer func() { knownIssuesPath, knownIssues = "", nil }()
+
+	if err := loadKnownIssues(); err != nil {
+		t.Fatalf("embedded table: unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		content   string
+		expectErr string
+	}{
+		{name: "valid", content: "issues:\n  - signal: SIGSEGV\n    signature: 9C1F0E2AB47D6C55\n    issue: https://example.com/1\n"},
+		{name: "no issue", content: "issues:\n  - signal: SIGSEGV\n", expectErr: "has no issue"},
+		{name: "no criteria", content: "issues:\n  - issue: https://example.com/1\n", expectErr: "needs a signal"},
+		{name: "unknown key", content: "issues:\n  - signl: SIGSEGV\n    issue: https://example.com/1\n", expectErr: "signl"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			knownIssuesPath = filepath.Join(t.TempDir(), "known_issues.yaml")
+			if err := os.WriteFile(knownIssuesPath, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			err := loadKnownIssues()
+			if tt.expectErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if len(knownIssues) != 1 || knownIssues[0].Signature != "9c1f0e2ab47d6c55" {
+					t.Errorf("unexpected table %+v", knownIssues)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectErr) {
+				t.Errorf("expected error containing %q, got %v", tt.expectErr, err)
+			}
+		})
+	}
+
+	knownIssuesPath = filepath.Join(t.TempDir(), "missing.yaml")
+	if err := loadKnownIssues(); err == nil {
+		t.Error("expected an error for a missing --known-issues file")
+	}
+}
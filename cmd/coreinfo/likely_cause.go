@@ -0,0 +1,102 @@
+package coreinfo
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// systemFunctions are the libc, pthread and kernel entry frames that sit
+// between a crash and the code that caused it, such as the signal
+// trampoline and the frames abort() and assert() go through.
+var systemFunctions = map[string]bool{
+	"raise":                         true,
+	"abort":                         true,
+	"__GI_raise":                    true,
+	"__GI_abort":                    true,
+	"pthread_kill":                  true,
+	"__pthread_kill":                true,
+	"__pthread_kill_implementation": true,
+	"__pthread_kill_internal":       true,
+	"__restore_rt":                  true,
+	"__assert_fail":                 true,
+	"__assert_fail_base":            true,
+	"__libc_message":                true,
+	"__libc_start_main":             true,
+	"__libc_start_call_main":        true,
+	"_start":                        true,
+	"start_thread":                  true,
+	"clone":                         true,
+	"clone3":                        true,
+	"<signal handler called>":       true,
+}
+
+// systemLibraryPrefixes are the base names of the C runtime libraries, whose
+// frames are system frames whatever their function.
+var systemLibraryPrefixes = []string{"libc.so", "libc-", "libpthread", "ld-linux", "linux-vdso"}
+
+// errorPathFunctions are the Cloudberry functions that report an error or
+// a failed assertion, and the handlers that turn a fatal signal into an
+// error report. They are on the stack of nearly every crash of a Cloudberry
+// backend, above the frame that raised the error.
+var errorPathFunctions = map[string]bool{
+	"errstart":                   true,
+	"errstart_cold":              true,
+	"errfinish":                  true,
+	"elog_start":                 true,
+	"elog_finish":                true,
+	"EmitErrorReport":            true,
+	"ExceptionalCondition":       true,
+	"pg_re_throw":                true,
+	"ReThrowError":               true,
+	"send_message_to_server_log": true,
+	"write_stderr":               true,
+	"StandardHandlerForSigillSigsegvSigbus_OnMainThread": true,
+	"CdbProgramErrorHandler":                             true,
+}
+
+// isSystemFunction reports whether frame belongs to the C runtime rather
+// than to the crashed program: a known libc, pthread or signal frame, a
+// frame in a C runtime library, or a frame gdb could not resolve.
+func isSystemFunction(frame StackFrame) bool {
+	if frame.Function == "" || frame.Function == "??" || systemFunctions[frame.Function] {
+		return true
+	}
+	library := filepath.Base(frame.Library)
+	for _, prefix := range systemLibraryPrefixes {
+		if strings.HasPrefix(library, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// likelyCause returns the first frame of the crashed thread that is neither
+// a system frame (see isSystemFunction) nor on Cloudberry's error reporting
+// path, as the most likely place the crash originated. Returns nil when
+// every frame is a system or error path frame.
+func likelyCause(frames []StackFrame) *StackFrame {
+	for _, frame := range frames {
+		if isSystemFunction(frame) || errorPathFunctions[frame.Function] {
+			continue
+		}
+		return &frame
+	}
+	return nil
+}
+
+// String describes the frame by its index, function and source location,
+// e.g. "#3 ExecProcNode at execProcnode.c:463", or its library when gdb
+// reported no source.
+func (f StackFrame) String() string {
+	s := fmt.Sprintf("#%d %s", f.Index, f.Function)
+	switch {
+	case f.File != "" && f.Line > 0:
+		s += fmt.Sprintf(" at %s:%d", f.File, f.Line)
+	case f.File != "":
+		s += " at " + f.File
+	case f.Library != "":
+		s += " from " + f.Library
+	}
+	return s
+}
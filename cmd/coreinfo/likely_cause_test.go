@@ -0,0 +1,98 @@
+package coreinfo
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestLikelyCause validates that system and error path frames are skipped
+// when picking the likely cause of a crash.
+func TestLikelyCause(t *testing.T) {
+	tests := []struct {
+		name      string
+		backtrace string
+		expected  string
+	}{
+		{
+			name: "segfault through the signal handler",
+			backtrace: `#0  0x00007f2b3c8a2e0f in raise () from /lib64/libpthread.so.0
+#1  0x0000000000c1a2b3 in StandardHandlerForSigillSigsegvSigbus_OnMainThread (processName=0x1 "", postgres_signal_arg=11) at elog.c:5100
+#2  <signal handler called>
+#3  0x0000000000b5e7a9 in ExecProcNode (node=0x0) at execProcnode.c:463
+#4  0x0000000000b5e7b0 in ExecutePlan (estate=0x2a4e0f8) at execMain.c:2970`,
+			expected: "#3 ExecProcNode at execProcnode.c:463",
+		},
+		{
+			name: "failed assertion",
+			backtrace: `#0  0x00007f2b3c8a2e0f in __pthread_kill_implementation () from /lib64/libc.so.6
+#1  0x00007f2b3c855e86 in raise () from /lib64/libc.so.6
+#2  0x00007f2b3c83f7f3 in abort () from /lib64/libc.so.6
+#3  0x0000000000c1a2b3 in ExceptionalCondition (conditionName=0x1 "!(tuple != NULL)", fileName=0x2 "heapam.c", lineNumber=2110) at assert.c:66
+#4  0x0000000000a1b2c3 in heap_insert (relation=0x7f2b3a001234) at heapam.c:2110`,
+			expected: "#4 heap_insert at heapam.c:2110",
+		},
+		{
+			name: "error raised by elog",
+			backtrace: `#0  0x0000000000c1a2b3 in errfinish (filename=<optimized out>, lineno=<optimized out>) at elog.c:600
+#1  0x0000000000a1b2c3 in ??
+#2  0x0000000000a1b2d4 in ProcessUtility () from /usr/local/cloudberry/lib/postgresql/extension.so`,
+			expected: "#2 ProcessUtility from /usr/local/cloudberry/lib/postgresql/extension.so",
+		},
+		{
+			name: "only system frames",
+			backtrace: `#0  0x00007f2b3c855e86 in raise () from /lib64/libc.so.6
+#1  0x00007f2b3c83f7f3 in abort () from /lib64/libc.so.6`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			threads, _ := parseBacktraces(tt.backtrace)
+			cause := likelyCause(threads[""])
+			if tt.expected == "" {
+				if cause != nil {
+					t.Errorf("expected no likely cause, got %s", cause)
+				}
+				return
+			}
+			if cause == nil || cause.String() != tt.expected {
+				t.Errorf("likelyCause() = %v, expected %q", cause, tt.expected)
+			}
+		})
+	}
+}
+
+// TestIsSystemFunction validates the classification of C runtime frames.
+func TestIsSystemFunction(t *testing.T) {
+	for _, frame := range []StackFrame{
+		{Function: "raise"},
+		{Function: "??"},
+		{Function: "memcpy", Library: "/lib64/libc.so.6"},
+		{Function: "_dl_fixup", Library: "/lib64/ld-linux-x86-64.so.2"},
+	} {
+		if !isSystemFunction(frame) {
+			t.Errorf("expected %s to be a system frame", frame)
+		}
+	}
+	for _, frame := range []StackFrame{
+		{Function: "ExecProcNode", File: "execProcnode.c"},
+		{Function: "gp_udf", Library: "/usr/local/cloudberry/lib/postgresql/gp_udf.so"},
+		{Function: "errfinish"},
+	} {
+		if isSystemFunction(frame) {
+			t.Errorf("expected %s not to be a system frame", frame)
+		}
+	}
+}
+
+// TestRenderLikelyCause validates that the likely cause is shown in the
+// text summary and the markdown table.
+func TestRenderLikelyCause(t *testing.T) {
+	analysis := &CoreAnalysis{CoreFile: "core.1", LikelyCause: &StackFrame{Index: 3, Function: "ExecProcNode", File: "execProcnode.c", Line: 463}}
+	if summary := textSummary(analysis); !strings.Contains(summary, "- Likely Cause: #3 ExecProcNode at execProcnode.c:463") {
+		t.Errorf("expected the likely cause in the summary, got:\n%s", summary)
+	}
+	if markdown := renderMarkdown(analysis, false); !strings.Contains(markdown, "| Likely Cause | #3 ExecProcNode at execProcnode.c:463 |") {
+		t.Errorf("expected the likely cause in the markdown table, got:\n%s", markdown)
+	}
+}
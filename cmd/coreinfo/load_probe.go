@@ -0,0 +1,78 @@
+package coreinfo
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// loadProbeMarker separates gdb's startup output from the probe's register
+// check in the load probe transcript.
+const loadProbeMarker = "cbtoolbox-load-probe"
+
+// loadFailureRegex matches gdb messages reporting that a core or binary
+// cannot be used. Messages gdb also prints for usable cores, such as
+// missing source files or shared libraries, are deliberately not matched.
+var loadFailureRegex = regexp.MustCompile(`(?m)^.*(is not a core dump|not in executable format|File format not recognized|Core file format not supported|is not compatible with|Couldn't find general-purpose registers|Permission denied).*$`)
+
+// probeCoreLoad runs gdb in batch mode with a minimal command set to check
+// that it can load the core before the full, potentially long, command file
+// is run. The core is usable when gdb identifies the process that generated
+// it and can read its registers.
+// Returns the signal that terminated the process ("" if gdb does not report
+// one), or an error wrapping ErrCoreLoadFailed if gdb cannot use the core.
+func probeCoreLoad(gdbArgs []string, binary string, coreFile string) (string, error) {
+	args := append([]string{"-q", "-batch"}, gdbArgs...)
+	args = append(args, "-ex", `echo `+loadProbeMarker+`\n`, "-ex", "print $pc", binary, coreFile)
+	output, err := exec.Command("gdb", args...).CombinedOutput()
+	// With -batch, gdb exits non-zero when the last command fails, as
+	// 'print $pc' does on unusable cores, so the transcript is classified
+	// before the exit status
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return "", fmt.Errorf("failed to probe core %s: %v", coreFile, err)
+	}
+	if err := loadFailure(coreFile, string(output)); err != nil {
+		return "", err
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to probe core %s: %v", coreFile, err)
+	}
+	if match := signalRegex.FindStringSubmatch(string(output)); len(match) > 1 {
+		return match[1], nil
+	}
+	return "", nil
+}
+
+// loadFailure returns an error wrapping ErrCoreLoadFailed, and
+// ErrPermissionDenied with a hint when the core is unreadable, if the load
+// probe transcript shows that gdb could not use coreFile. Returns nil otherwise.
+func loadFailure(coreFile, output string) error {
+	reason := loadProbeFailure(output)
+	switch {
+	case reason == "":
+		return nil
+	case strings.Contains(reason, "Permission denied"):
+		return fmt.Errorf("%w: %w: %s: %s; %s", ErrCoreLoadFailed, ErrPermissionDenied, coreFile, reason, permissionHint(coreFile))
+	default:
+		return fmt.Errorf("%w: %s: %s", ErrCoreLoadFailed, coreFile, reason)
+	}
+}
+
+// loadProbeFailure returns why gdb could not use the core according to the
+// load probe transcript, or "" if the core loaded successfully.
+func loadProbeFailure(output string) string {
+	if match := loadFailureRegex.FindString(output); match != "" {
+		return strings.TrimSpace(match)
+	}
+	if !binaryRegex.MatchString(output) {
+		return "gdb did not recognize the process that generated the core"
+	}
+	_, registers, found := strings.Cut(output, loadProbeMarker)
+	if !found || strings.Contains(registers, "No registers.") {
+		return "gdb could not read registers from the core"
+	}
+	return ""
+}
@@ -0,0 +1,124 @@
+package coreinfo
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// fakeGDB puts a gdb on PATH that prints output and exits with status,
+// as gdb -batch does when its last command fails.
+func fakeGDB(t *testing.T, output string, status int) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "transcript"), []byte(output), 0644); err != nil {
+		t.Fatal(err)
+	}
+	script := "#!/bin/sh\ncat '" + filepath.Join(dir, "transcript") + "'\nexit " + strconv.Itoa(status) + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "gdb"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// TestLoadProbeFailure validates recognition of cores gdb cannot use.
+func TestLoadProbeFailure(t *testing.T) {
+	loaded := "[New LWP 4242]\n" +
+		"Core was generated by `postgres: 7000, gpadmin postgres [local] con12 cmd5 SELECT'.\n" +
+		"Program terminated with signal SIGSEGV, Segmentation fault.\n" +
+		"#0  0x00000000004005a4 in ExecProcNode ()\n" +
+		loadProbeMarker + "\n$1 = (void (*)()) 0x4005a4 <ExecProcNode+20>\n"
+
+	tests := []struct {
+		name   string
+		output string
+		reason string
+	}{
+		{name: "loaded", output: loaded},
+		{name: "loaded without sources", output: loaded + "412\texecProcnode.c: No such file or directory.\n"},
+		{
+			name:   "not a core",
+			output: `"/var/crash/core.1" is not a core dump: file format not recognized` + "\n" + loadProbeMarker + "\nNo registers.\n",
+			reason: "is not a core dump",
+		},
+		{
+			name:   "wrong architecture",
+			output: "warning: core file may not match specified executable file.\nCouldn't find general-purpose registers in core file.\n",
+			reason: "Couldn't find general-purpose registers",
+		},
+		{
+			name:   "permission denied",
+			output: "/var/crash/core.1: Permission denied.\n" + loadProbeMarker + "\nNo registers.\n",
+			reason: "Permission denied",
+		},
+		{
+			name:   "no process recognized",
+			output: loadProbeMarker + "\n$1 = (void (*)()) 0x0\n",
+			reason: "did not recognize the process",
+		},
+		{
+			name:   "no registers",
+			output: strings.Replace(loaded, "$1 = (void (*)()) 0x4005a4 <ExecProcNode+20>", "No registers.", 1),
+			reason: "could not read registers",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason := loadProbeFailure(tt.output)
+			if tt.reason == "" {
+				if reason != "" {
+					t.Errorf("expected core to load, got failure %q", reason)
+				}
+				return
+			}
+			if !strings.Contains(reason, tt.reason) {
+				t.Errorf("expected failure containing %q, got %q", tt.reason, reason)
+			}
+		})
+	}
+}
+
+// TestProbeCoreLoad validates that the probe classifies gdb's transcript
+// before its exit status, which is non-zero when 'print $pc' fails.
+func TestProbeCoreLoad(t *testing.T) {
+	loaded := "Core was generated by `postgres: 7000, gpadmin postgres [local] con12 cmd5 SELECT'.\n" +
+		"Program terminated with signal SIGSEGV, Segmentation fault.\n" +
+		loadProbeMarker + "\n$1 = (void (*)()) 0x4005a4 <ExecProcNode+20>\n"
+
+	tests := []struct {
+		name       string
+		output     string
+		status     int
+		signal     string
+		loadFailed bool
+		permission bool
+	}{
+		{name: "loaded", output: loaded, signal: "SIGSEGV"},
+		{name: "no registers", output: strings.Replace(loaded, "$1 = (void (*)()) 0x4005a4 <ExecProcNode+20>", "No registers.", 1), status: 1, loadFailed: true},
+		{name: "permission denied", output: "/var/crash/core.1: Permission denied.\n" + loadProbeMarker + "\nNo registers.\n", status: 1, loadFailed: true, permission: true},
+		{name: "unexplained failure", output: loaded, status: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeGDB(t, tt.output, tt.status)
+			signal, err := probeCoreLoad(nil, "postgres", "/var/crash/core.1")
+			if errors.Is(err, ErrCoreLoadFailed) != tt.loadFailed {
+				t.Errorf("expected ErrCoreLoadFailed %v, got %v", tt.loadFailed, err)
+			}
+			if errors.Is(err, ErrPermissionDenied) != tt.permission {
+				t.Errorf("expected ErrPermissionDenied %v, got %v", tt.permission, err)
+			}
+			if tt.status != 0 && err == nil {
+				t.Errorf("expected an error for exit status %d", tt.status)
+			}
+			if signal != tt.signal {
+				t.Errorf("expected signal %q, got %q", tt.signal, signal)
+			}
+		})
+	}
+}
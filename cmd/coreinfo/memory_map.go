@@ -0,0 +1,98 @@
+package coreinfo
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Markers delimiting the memory map printed by the memory map commands.
+const (
+	memoryMapBegin = "cbtoolbox-memory-map-begin"
+	memoryMapEnd   = "cbtoolbox-memory-map-end"
+)
+
+// includeMemoryMap enables the --memory-map collection.
+var includeMemoryMap bool
+
+var memoryMapRegex = regexp.MustCompile(`(?s)` + memoryMapBegin + `\n(.*?)` + memoryMapEnd)
+
+// MemoryRegion is a single mapping of the crashed process's address space.
+// Permissions is only known to gdb versions printing a Perms column.
+type MemoryRegion struct {
+	Start       string `json:"start" yaml:"start"`
+	End         string `json:"end" yaml:"end"`
+	Permissions string `json:"permissions,omitempty" yaml:"permissions,omitempty"`
+	Object      string `json:"object,omitempty" yaml:"object,omitempty"`
+}
+
+// String formats the region as "start-end perms object".
+func (r MemoryRegion) String() string {
+	return strings.Join(strings.Fields(fmt.Sprintf("%s-%s %s %s", r.Start, r.End, r.Permissions, r.Object)), " ")
+}
+
+// memoryMapArgs returns gdb arguments that print the mappings of the
+// crashed process between markers.
+func memoryMapArgs() []string {
+	return []string{
+		"-ex", `echo \n` + memoryMapBegin + `\n`,
+		"-ex", "info proc mappings",
+		"-ex", `echo ` + memoryMapEnd + `\n`,
+	}
+}
+
+// extractMemoryMap returns the mappings printed by the memory map commands
+// in address order. Returns nil when the commands did not run or gdb
+// found no mappings.
+func extractMemoryMap(gdbOutput string) []MemoryRegion {
+	match := memoryMapRegex.FindStringSubmatch(gdbOutput)
+	if len(match) < 2 {
+		return nil
+	}
+
+	// Newer gdb versions print a Perms column between Offset and objfile
+	hasPerms := false
+	var regions []MemoryRegion
+	for _, line := range strings.Split(match[1], "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "Start" {
+			hasPerms = strings.Contains(line, "Perms")
+			continue
+		}
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "0x") {
+			continue
+		}
+		region := MemoryRegion{Start: fields[0], End: fields[1]}
+		rest := fields[4:]
+		if hasPerms && len(rest) > 0 {
+			region.Permissions, rest = rest[0], rest[1:]
+		}
+		region.Object = strings.Join(rest, " ")
+		regions = append(regions, region)
+	}
+	return regions
+}
+
+// faultRegion describes the region of regions containing the faulting
+// address, e.g. "0x7f2a1c000000-0x7f2a1c1c6000 r-xp /usr/lib64/libc.so.6".
+// Cores only record file-backed mappings, so an address outside all of
+// them lies in anonymous memory such as the heap or a stack, or is
+// unmapped. Returns "" when the address or the map is unknown.
+func faultRegion(faultAddress string, regions []MemoryRegion) string {
+	addr, err := strconv.ParseUint(faultAddress, 0, 64)
+	if err != nil || len(regions) == 0 {
+		return ""
+	}
+	for _, region := range regions {
+		start, errStart := strconv.ParseUint(region.Start, 0, 64)
+		end, errEnd := strconv.ParseUint(region.End, 0, 64)
+		if errStart == nil && errEnd == nil && start <= addr && addr < end {
+			return region.String()
+		}
+	}
+	return "not in a file-backed mapping (anonymous memory such as heap or stack, or unmapped)"
+}
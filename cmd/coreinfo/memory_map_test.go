@@ -0,0 +1,129 @@
+package coreinfo
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const (
+	// memoryMapOutput is 'info proc mappings' of a core as printed by gdb 12.
+	memoryMapOutput = `
+cbtoolbox-memory-map-begin
+Mapped address spaces:
+
+          Start Addr           End Addr       Size     Offset objfile
+      0x555555554000     0x555555a00000   0x4ac000        0x0 /usr/local/cloudberry-db/bin/postgres
+      0x7ffff7a00000     0x7ffff7bc6000   0x1c6000        0x0 /usr/lib64/libc-2.28.so
+cbtoolbox-memory-map-end
+`
+	// memoryMapPermsOutput is the same with the Perms column of newer gdb versions.
+	memoryMapPermsOutput = `
+cbtoolbox-memory-map-begin
+Mapped address spaces:
+
+          Start Addr           End Addr       Size     Offset  Perms  objfile
+      0x555555554000     0x555555a00000   0x4ac000        0x0  r-xp   /usr/local/cloudberry-db/bin/postgres
+      0x7ffff7a00000     0x7ffff7bc6000   0x1c6000        0x0  r-xp   /usr/lib64/libc-2.28.so
+cbtoolbox-memory-map-end
+`
+)
+
+// TestExtractMemoryMap validates parsing of the mappings printed by gdb.
+func TestExtractMemoryMap(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected []MemoryRegion
+	}{
+		{
+			name:   "without permissions",
+			output: memoryMapOutput,
+			expected: []MemoryRegion{
+				{Start: "0x555555554000", End: "0x555555a00000", Object: "/usr/local/cloudberry-db/bin/postgres"},
+				{Start: "0x7ffff7a00000", End: "0x7ffff7bc6000", Object: "/usr/lib64/libc-2.28.so"},
+			},
+		},
+		{
+			name:   "with permissions",
+			output: memoryMapPermsOutput,
+			expected: []MemoryRegion{
+				{Start: "0x555555554000", End: "0x555555a00000", Permissions: "r-xp", Object: "/usr/local/cloudberry-db/bin/postgres"},
+				{Start: "0x7ffff7a00000", End: "0x7ffff7bc6000", Permissions: "r-xp", Object: "/usr/lib64/libc-2.28.so"},
+			},
+		},
+		{
+			name:     "no mappings recorded",
+			output:   "\ncbtoolbox-memory-map-begin\nunable to open /proc file '/proc/1234/maps'\ncbtoolbox-memory-map-end\n",
+			expected: nil,
+		},
+		{
+			name:     "not collected",
+			output:   sampleGDBOutput,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if regions := extractMemoryMap(tt.output); !reflect.DeepEqual(regions, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, regions)
+			}
+		})
+	}
+}
+
+// TestFaultRegion validates locating the faulting address in the memory map.
+func TestFaultRegion(t *testing.T) {
+	regions := extractMemoryMap(memoryMapPermsOutput)
+	tests := []struct {
+		address  string
+		expected string
+	}{
+		{"0x7ffff7a12345", "0x7ffff7a00000-0x7ffff7bc6000 r-xp /usr/lib64/libc-2.28.so"},
+		{"0x555555554000", "0x555555554000-0x555555a00000 r-xp /usr/local/cloudberry-db/bin/postgres"},
+		{"0x555555a00000", "not in a file-backed mapping (anonymous memory such as heap or stack, or unmapped)"},
+		{"0x0", "not in a file-backed mapping (anonymous memory such as heap or stack, or unmapped)"},
+		{"N/A", ""},
+	}
+	for _, tt := range tests {
+		if got := faultRegion(tt.address, regions); got != tt.expected {
+			t.Errorf("faultRegion(%q) = %q, want %q", tt.address, got, tt.expected)
+		}
+	}
+	if got := faultRegion("0x7ffff7a12345", nil); got != "" {
+		t.Errorf("expected no region without a memory map, got %q", got)
+	}
+}
+
+// TestRenderMemoryMap validates that the memory map and fault region appear
+// in both output formats only when collected.
+func TestRenderMemoryMap(t *testing.T) {
+	output := strings.Replace(sampleGDBOutput, "si_addr = 0x0", "si_addr = 0x7ffff7a12345", 1) + memoryMapPermsOutput
+	analysis, err := parseCoreAnalysis(output, nil, "core.1234")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	text := renderText(analysis)
+	for _, want := range []string{
+		"- Fault Region: 0x7ffff7a00000-0x7ffff7bc6000 r-xp /usr/lib64/libc-2.28.so",
+		"- Memory Map:\n  - 0x555555554000-0x555555a00000 r-xp /usr/local/cloudberry-db/bin/postgres",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in text summary, got:\n%s", want, text)
+		}
+	}
+	md := renderMarkdown(analysis, false)
+	if !strings.Contains(md, "| Fault Region | 0x7ffff7a00000-0x7ffff7bc6000 r-xp /usr/lib64/libc-2.28.so |") || !strings.Contains(md, "<summary>Memory map (2 regions)</summary>") {
+		t.Errorf("expected memory map in markdown, got:\n%s", md)
+	}
+
+	plain, err := parseCoreAnalysis(sampleGDBOutput, nil, "core.1234")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(renderText(plain), "Memory Map") || strings.Contains(renderMarkdown(plain, false), "Memory map") {
+		t.Errorf("expected no memory map without --memory-map")
+	}
+}
@@ -0,0 +1,35 @@
+package coreinfo
+
+import (
+	"regexp"
+	"strings"
+)
+
+// openFileMarker prefixes each open file printed by the open files commands.
+const openFileMarker = "cbtoolbox-open-file: "
+
+// includeOpenFiles enables the --open-files reconstruction.
+var includeOpenFiles bool
+
+var openFileRegex = regexp.MustCompile(`(?m)^` + regexp.QuoteMeta(openFileMarker) + `(.+)$`)
+
+// openFilesArgs returns gdb arguments that run the embedded open files
+// commands.
+func openFilesArgs() ([]string, error) {
+	path, err := writeEmbeddedGDBFile("gdb_commands_open_files.txt")
+	if err != nil {
+		return nil, err
+	}
+	return []string{"-x", path}, nil
+}
+
+// extractOpenFiles returns the open files printed by the open files commands,
+// in the order gdb printed them. Returns nil when none could be reconstructed,
+// e.g. because the binary has no debug symbols.
+func extractOpenFiles(gdbOutput string) []string {
+	var files []string
+	for _, match := range openFileRegex.FindAllStringSubmatch(gdbOutput, -1) {
+		files = append(files, strings.TrimSpace(match[1]))
+	}
+	return files
+}
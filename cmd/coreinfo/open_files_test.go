@@ -0,0 +1,58 @@
+package coreinfo
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestExtractOpenFiles validates parsing of the open files printed by gdb.
+func TestExtractOpenFiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected []string
+	}{
+		{
+			name: "files and socket",
+			output: "cbtoolbox-open-file: fd 9: client socket\n" +
+				"cbtoolbox-open-file: fd 12: base/16384/1259\n" +
+				"cbtoolbox-open-file: fd 13: pg_wal/000000010000000000000001\n",
+			expected: []string{"fd 9: client socket", "fd 12: base/16384/1259", "fd 13: pg_wal/000000010000000000000001"},
+		},
+		{
+			name:     "no debug symbols",
+			output:   "No symbol table is loaded.  Use the \"file\" command.\n",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := extractOpenFiles(tt.output)
+			if strings.Join(files, "|") != strings.Join(tt.expected, "|") {
+				t.Errorf("expected %q, got %q", tt.expected, files)
+			}
+		})
+	}
+}
+
+// TestRenderOpenFiles validates that open files appear in both output formats
+// only when they were reconstructed.
+func TestRenderOpenFiles(t *testing.T) {
+	analysis, err := parseCoreAnalysis(sampleGDBOutput+"cbtoolbox-open-file: fd 12: base/16384/1259\n", nil, "core.1234")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if text := renderText(analysis); !strings.Contains(text, "- Open Files:\n  - fd 12: base/16384/1259") {
+		t.Errorf("expected open files in text summary, got:\n%s", text)
+	}
+	if md := renderMarkdown(analysis, false); !strings.Contains(md, "### Open Files\n\n- `fd 12: base/16384/1259`") {
+		t.Errorf("expected open files in markdown, got:\n%s", md)
+	}
+
+	analysis.OpenFiles = nil
+	if text := renderText(analysis); strings.Contains(text, "Open Files") {
+		t.Error("expected no open files section when none were reconstructed")
+	}
+}
@@ -0,0 +1,106 @@
+package coreinfo
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// outputDir is the directory analyses are saved to instead of stdout.
+var outputDir string
+
+// perCoreDir saves each core's files to its own subdirectory of outputDir.
+var perCoreDir bool
+
+// rawGDBOutputName is the file the raw gdb output is saved to with --per-core-dir.
+const rawGDBOutputName = "gdb_output.txt"
+
+// formatExtensions maps each output format to the extension of its saved file.
+var formatExtensions = map[string]string{
+	formatText:     "txt",
+	formatMarkdown: "md",
+	formatJSON:     "json",
+}
+
+// analysisFileName returns the name an analysis is saved under in the given
+// format. Each format gets its own extension, so formats rendered in the same
+// run do not overwrite one another.
+func analysisFileName(coreFile, format string) string {
+	return fmt.Sprintf("core_analysis_%s.%s", filepath.Base(coreFile), formatExtensions[format])
+}
+
+// writeAnalysis renders an analysis in each of the given formats, in order.
+// Without an output directory the reports are written to w; otherwise each
+// report is saved to its own file in dir and the saved path is written to w.
+func writeAnalysis(w io.Writer, analysis *CoreAnalysis, formats []string, dir string) error {
+	for _, format := range formats {
+		report, err := renderAnalysis(analysis, format)
+		if err != nil {
+			return err
+		}
+		if dir == "" {
+			fmt.Fprint(w, report)
+			continue
+		}
+		path, err := saveAnalysis(report, dir, analysisFileName(analysis.CoreFile, format))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "Saved %s analysis of %s to %s\n", format, analysis.CoreFile, path)
+	}
+	return nil
+}
+
+// coreDirs assigns each core a subdirectory of an output directory, named
+// after the core's base name. Cores from different directories can share
+// a base name, so later ones get a numeric suffix, e.g. "core.4242-2".
+type coreDirs struct {
+	root     string
+	assigned map[string]string // core path -> subdirectory
+	taken    map[string]bool   // subdirectory names in use
+}
+
+// newCoreDirs returns the subdirectory assignment under root.
+func newCoreDirs(root string) *coreDirs {
+	return &coreDirs{root: root, assigned: make(map[string]string), taken: make(map[string]bool)}
+}
+
+// dir returns the subdirectory of coreFile, assigning one on first use.
+func (d *coreDirs) dir(coreFile string) string {
+	key := filepath.Clean(coreFile)
+	if dir, ok := d.assigned[key]; ok {
+		return dir
+	}
+	base := filepath.Base(key)
+	name := base
+	for n := 2; d.taken[name]; n++ {
+		name = fmt.Sprintf("%s-%d", base, n)
+	}
+	d.taken[name] = true
+	d.assigned[key] = filepath.Join(d.root, name)
+	return d.assigned[key]
+}
+
+// saveRawGDBOutput saves the raw gdb output of an analysis to dir and
+// writes the saved path to w.
+func saveRawGDBOutput(w io.Writer, analysis *CoreAnalysis, dir string) error {
+	path, err := saveAnalysis(analysis.GDBOutput, dir, rawGDBOutputName)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Saved gdb output of %s to %s\n", analysis.CoreFile, path)
+	return nil
+}
+
+// saveAnalysis writes a rendered report to name in dir, creating dir if needed.
+func saveAnalysis(report, dir, name string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create output directory %s: %w", dir, err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(report), 0644); err != nil {
+		return "", fmt.Errorf("failed to write analysis to %s: %w", path, err)
+	}
+	return path, nil
+}
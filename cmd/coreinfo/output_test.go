@@ -0,0 +1,91 @@
+package coreinfo
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestWriteAnalysis validates rendering several formats to stdout and to an output directory.
+func TestWriteAnalysis(t *testing.T) {
+	analysis := &CoreAnalysis{CoreFile: "/var/crash/core.4242", Signal: "SIGSEGV", GDBOutput: "raw transcript"}
+
+	var stdout bytes.Buffer
+	if err := writeAnalysis(&stdout, analysis, []string{formatText, formatJSON}, ""); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	text := stdout.String()
+	summary := strings.Index(text, "Core Dump Analysis Summary")
+	document := strings.Index(text, `"core_file": "/var/crash/core.4242"`)
+	if summary < 0 || document < 0 || summary > document {
+		t.Errorf("Expected the text report followed by the JSON document, got:\n%s", text)
+	}
+
+	dir := filepath.Join(t.TempDir(), "reports")
+	stdout.Reset()
+	if err := writeAnalysis(&stdout, analysis, []string{formatText, formatMarkdown, formatJSON}, dir); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, name := range []string{"core_analysis_core.4242.txt", "core_analysis_core.4242.md", "core_analysis_core.4242.json"} {
+		if !strings.Contains(stdout.String(), filepath.Join(dir, name)) {
+			t.Errorf("Expected saved path of %s to be reported, got:\n%s", name, stdout.String())
+		}
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("Expected %s to be saved: %v", name, err)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "core_analysis_core.4242.json"))
+	if err != nil {
+		t.Fatalf("Failed to read saved JSON: %v", err)
+	}
+	var saved CoreAnalysis
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatalf("Saved JSON does not parse: %v", err)
+	}
+	if saved.Signal != "SIGSEGV" || saved.GDBOutput != "" {
+		t.Errorf("Unexpected saved analysis: %+v", saved)
+	}
+}
+
+func TestCoreDirs(t *testing.T) {
+	dirs := newCoreDirs("/tmp/reports")
+	tests := []struct {
+		coreFile string
+		want     string
+	}{
+		{"/var/crash/core.4242", "/tmp/reports/core.4242"},
+		{"/data/cores/core.4242", "/tmp/reports/core.4242-2"},
+		{"/var/crash/core.17", "/tmp/reports/core.17"},
+		{"/var/crash/../crash/core.4242", "/tmp/reports/core.4242"},
+		{"/home/gpadmin/core.4242", "/tmp/reports/core.4242-3"},
+	}
+	for _, tt := range tests {
+		if got := dirs.dir(tt.coreFile); got != tt.want {
+			t.Errorf("dir(%q) = %q, want %q", tt.coreFile, got, tt.want)
+		}
+	}
+}
+
+func TestSaveRawGDBOutput(t *testing.T) {
+	analysis := &CoreAnalysis{CoreFile: "/var/crash/core.4242", GDBOutput: "raw transcript"}
+	dir := filepath.Join(t.TempDir(), "core.4242")
+
+	var stdout bytes.Buffer
+	if err := saveRawGDBOutput(&stdout, analysis, dir); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, rawGDBOutputName))
+	if err != nil {
+		t.Fatalf("Failed to read saved gdb output: %v", err)
+	}
+	if string(data) != "raw transcript" {
+		t.Errorf("Saved gdb output = %q", data)
+	}
+	if !strings.Contains(stdout.String(), filepath.Join(dir, rawGDBOutputName)) {
+		t.Errorf("Expected saved path to be reported, got:\n%s", stdout.String())
+	}
+}
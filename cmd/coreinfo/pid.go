@@ -0,0 +1,107 @@
+package coreinfo
+
+import (
+	"debug/elf"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+)
+
+// pidFilter is the --pid of the crashed process whose cores are analyzed;
+// 0 analyzes every core.
+var pidFilter int
+
+// ntPRStatus is the type of the per-thread NT_PRSTATUS notes of a core.
+const ntPRStatus = 1
+
+// prstatusPIDOffset is the offset of pr_pid in struct elf_prstatus, after
+// pr_info, pr_cursig (padded) and the pr_sigpend and pr_sighold words.
+var prstatusPIDOffset = map[elf.Class]int{elf.ELFCLASS32: 24, elf.ELFCLASS64: 32}
+
+// corePIDSuffixRegex matches the PID the default core_pattern ("core") and
+// kernel.core_uses_pid append to core file names, e.g. "core.12345".
+var corePIDSuffixRegex = regexp.MustCompile(`\.(\d+)$`)
+
+// coreThreadPIDs returns the pr_pid of each NT_PRSTATUS note of a core,
+// one per thread. The main thread's pr_pid is the process ID.
+// Returns an error if the core's notes cannot be read.
+func coreThreadPIDs(coreFile string) ([]int, error) {
+	f, err := elf.Open(coreFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	offset, ok := prstatusPIDOffset[f.Class]
+	if !ok {
+		return nil, fmt.Errorf("unsupported ELF class %v", f.Class)
+	}
+	var pids []int
+	for _, prog := range f.Progs {
+		if prog.Type != elf.PT_NOTE {
+			continue
+		}
+		notes, err := io.ReadAll(prog.Open())
+		if err != nil {
+			return nil, err
+		}
+		// Each note is namesz, descsz and type, then the name and the
+		// descriptor, each padded to 4 bytes
+		for len(notes) >= 12 {
+			nameSize := int(f.ByteOrder.Uint32(notes[0:4]))
+			descSize := int(f.ByteOrder.Uint32(notes[4:8]))
+			noteType := f.ByteOrder.Uint32(notes[8:12])
+			descStart := 12 + (nameSize+3)&^3
+			descEnd := descStart + descSize
+			if nameSize < 0 || descSize < 0 || descEnd > len(notes) {
+				break
+			}
+			if noteType == ntPRStatus && descSize >= offset+4 {
+				pids = append(pids, int(int32(f.ByteOrder.Uint32(notes[descStart+offset:]))))
+			}
+			notes = notes[min(len(notes), descStart+(descSize+3)&^3):]
+		}
+	}
+	if len(pids) == 0 {
+		return nil, fmt.Errorf("no NT_PRSTATUS notes")
+	}
+	return pids, nil
+}
+
+// coreMatchesPID reports whether a core was generated by process pid. The
+// thread IDs recorded in the core's NT_PRSTATUS notes are authoritative;
+// cores without readable notes fall back to the PID suffix of the file
+// name.
+func coreMatchesPID(coreFile string, pid int) bool {
+	if pids, err := coreThreadPIDs(coreFile); err == nil {
+		for _, threadPID := range pids {
+			if threadPID == pid {
+				return true
+			}
+		}
+		return false
+	}
+	match := corePIDSuffixRegex.FindStringSubmatch(filepath.Base(coreFile))
+	return match != nil && match[1] == strconv.Itoa(pid)
+}
+
+// filterCoresByPID keeps the cores generated by process pid, reporting the
+// number of skipped cores to w.
+// Returns an error wrapping ErrNoValidCoreFiles if no core matches.
+func filterCoresByPID(w io.Writer, coreFiles []string, pid int) ([]string, error) {
+	var matching []string
+	for _, coreFile := range coreFiles {
+		if coreMatchesPID(coreFile, pid) {
+			matching = append(matching, coreFile)
+		}
+	}
+	if len(matching) == 0 {
+		return nil, fmt.Errorf("%w: none of the %d cores was generated by PID %d", ErrNoValidCoreFiles, len(coreFiles), pid)
+	}
+	if skipped := len(coreFiles) - len(matching); skipped > 0 {
+		fmt.Fprintf(w, "Skipping %d cores not generated by PID %d\n", skipped, pid)
+	}
+	return matching, nil
+}
@@ -0,0 +1,100 @@
+package coreinfo
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeCoreWithPIDs writes a minimal 64-bit ELF core to path with one
+// NT_PRSTATUS note per thread PID, preceded by an NT_PRPSINFO note.
+func writeCoreWithPIDs(t *testing.T, path string, pids ...int) {
+	t.Helper()
+
+	var notes bytes.Buffer
+	writeNote := func(noteType uint32, desc []byte) {
+		name := []byte("CORE\x00\x00\x00\x00")
+		_ = binary.Write(&notes, binary.LittleEndian, []uint32{5, uint32(len(desc)), noteType})
+		notes.Write(name)
+		notes.Write(desc)
+	}
+	writeNote(3, make([]byte, 136))
+	for _, pid := range pids {
+		desc := make([]byte, 336)
+		binary.LittleEndian.PutUint32(desc[prstatusPIDOffset[elf.ELFCLASS64]:], uint32(pid))
+		writeNote(ntPRStatus, desc)
+	}
+
+	const headerSize, progSize = 64, 56
+	ident := [elf.EI_NIDENT]byte{0x7f, 'E', 'L', 'F', byte(elf.ELFCLASS64), byte(elf.ELFDATA2LSB), byte(elf.EV_CURRENT)}
+	var buf bytes.Buffer
+	hdr := elf.Header64{
+		Ident: ident, Type: uint16(elf.ET_CORE), Machine: uint16(elf.EM_X86_64), Version: uint32(elf.EV_CURRENT),
+		Phoff: headerSize, Ehsize: headerSize, Phentsize: progSize, Phnum: 1,
+	}
+	prog := elf.Prog64{Type: uint32(elf.PT_NOTE), Off: headerSize + progSize, Filesz: uint64(notes.Len()), Align: 4}
+	if err := binary.Write(&buf, binary.LittleEndian, hdr); err != nil {
+		t.Fatalf("Failed to encode ELF header: %v", err)
+	}
+	if err := binary.Write(&buf, binary.LittleEndian, prog); err != nil {
+		t.Fatalf("Failed to encode program header: %v", err)
+	}
+	buf.Write(notes.Bytes())
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("Failed to write core %s: %v", path, err)
+	}
+}
+
+// TestCoreThreadPIDs validates reading thread PIDs from NT_PRSTATUS notes.
+func TestCoreThreadPIDs(t *testing.T) {
+	core := filepath.Join(t.TempDir(), "core")
+	writeCoreWithPIDs(t, core, 4242, 4243)
+
+	pids, err := coreThreadPIDs(core)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pids) != 2 || pids[0] != 4242 || pids[1] != 4243 {
+		t.Errorf("coreThreadPIDs() = %v, expected [4242 4243]", pids)
+	}
+
+	withoutNotes := filepath.Join(t.TempDir(), "core.1")
+	writeELFHeader(t, withoutNotes, elf.ELFCLASS64, elf.ET_CORE)
+	if _, err := coreThreadPIDs(withoutNotes); err == nil {
+		t.Error("expected an error for a core without notes")
+	}
+}
+
+// TestFilterCoresByPID validates matching cores by their notes, falling
+// back to the core.<pid> file name.
+func TestFilterCoresByPID(t *testing.T) {
+	dir := t.TempDir()
+	// The notes win over a misleading file name
+	byNotes := filepath.Join(dir, "core.9999")
+	writeCoreWithPIDs(t, byNotes, 4242)
+	other := filepath.Join(dir, "core.postgres")
+	writeCoreWithPIDs(t, other, 5000)
+	byName := filepath.Join(dir, "core.4242")
+	writeELFHeader(t, byName, elf.ELFCLASS64, elf.ET_CORE)
+
+	var buf bytes.Buffer
+	matching, err := filterCoresByPID(&buf, []string{byNotes, other, byName}, 4242)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(matching) != 2 || matching[0] != byNotes || matching[1] != byName {
+		t.Errorf("filterCoresByPID() = %v, expected [%s %s]", matching, byNotes, byName)
+	}
+	if !strings.Contains(buf.String(), "Skipping 1 cores not generated by PID 4242") {
+		t.Errorf("unexpected report %q", buf.String())
+	}
+
+	if _, err := filterCoresByPID(&buf, []string{other}, 9999); !errors.Is(err, ErrNoValidCoreFiles) {
+		t.Errorf("expected ErrNoValidCoreFiles when no core matches, got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package coreinfo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// defaultPostTimeout bounds each POST of an analysis with --post-url.
+const defaultPostTimeout = 10 * time.Second
+
+var (
+	// postURL is the endpoint each analysis is POSTed to as JSON
+	postURL string
+
+	// postHeaders are extra "Name: value" request headers for --post-url
+	postHeaders []string
+
+	// postTimeout bounds each request, including a retry
+	postTimeout = defaultPostTimeout
+
+	// postRequired makes a failed POST fail the command
+	postRequired bool
+)
+
+// validatePostFlags checks --post-url and --header.
+func validatePostFlags() error {
+	if postURL == "" {
+		if len(postHeaders) > 0 || postRequired {
+			return fmt.Errorf("--header and --post-required require --post-url")
+		}
+		return nil
+	}
+	u, err := url.Parse(postURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("--post-url must be an http or https URL: %q", postURL)
+	}
+	if postTimeout <= 0 {
+		return fmt.Errorf("--post-timeout must be positive")
+	}
+	for _, header := range postHeaders {
+		if name, _, found := strings.Cut(header, ":"); !found || strings.TrimSpace(name) == "" {
+			return fmt.Errorf("--header must have the form 'Name: value': %q", header)
+		}
+	}
+	return nil
+}
+
+// isTransientPostError reports whether a failed POST is worth retrying:
+// timeouts, dropped or refused connections, and gateway errors of a
+// restarting service.
+func isTransientPostError(err error, status int) bool {
+	if err == nil {
+		return status == http.StatusBadGateway || status == http.StatusServiceUnavailable || status == http.StatusGatewayTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// postAnalysis POSTs an analysis as JSON to endpoint with the given extra
+// headers. A transient failure is retried once.
+// Returns an error wrapping ErrPostFailed if the analysis was not accepted.
+func postAnalysis(client *http.Client, endpoint string, headers []string, analysis *CoreAnalysis) error {
+	body, err := json.Marshal(analysis)
+	if err != nil {
+		return fmt.Errorf("%w: failed to marshal analysis of %s: %v", ErrPostFailed, analysis.CoreFile, err)
+	}
+
+	for attempt := 1; ; attempt++ {
+		status, err := postOnce(client, endpoint, headers, body)
+		if err == nil && status >= 200 && status < 300 {
+			return nil
+		}
+		if attempt == 1 && isTransientPostError(err, status) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrPostFailed, analysis.CoreFile, err)
+		}
+		return fmt.Errorf("%w: %s: %s responded %d %s", ErrPostFailed, analysis.CoreFile, endpoint, status, http.StatusText(status))
+	}
+}
+
+// postOnce sends a single POST and returns the response status.
+func postOnce(client *http.Client, endpoint string, headers []string, body []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for _, header := range headers {
+		name, value, _ := strings.Cut(header, ":")
+		req.Header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Drain the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,105 @@
+package coreinfo
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestPostAnalysis validates that analyses are posted as JSON with the
+// configured headers, and that only transient failures are retried.
+func TestPostAnalysis(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		expectErr    bool
+		expectPosted int
+	}{
+		{name: "accepted", statuses: []int{http.StatusCreated}, expectPosted: 1},
+		{name: "retried after unavailable", statuses: []int{http.StatusServiceUnavailable, http.StatusOK}, expectPosted: 2},
+		{name: "retried once only", statuses: []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusOK}, expectErr: true, expectPosted: 2},
+		{name: "rejected without retry", statuses: []int{http.StatusBadRequest, http.StatusOK}, expectErr: true, expectPosted: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			posted := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var analysis CoreAnalysis
+				if err := json.NewDecoder(r.Body).Decode(&analysis); err != nil || analysis.CoreFile != "core.1" {
+					t.Errorf("unexpected body: %+v, %v", analysis, err)
+				}
+				if got := r.Header.Get("Content-Type"); got != "application/json" {
+					t.Errorf("Content-Type = %q", got)
+				}
+				if got := r.Header.Get("Authorization"); got != "Bearer token" {
+					t.Errorf("Authorization = %q", got)
+				}
+				w.WriteHeader(tt.statuses[posted])
+				posted++
+			}))
+			defer server.Close()
+
+			err := postAnalysis(server.Client(), server.URL, []string{"Authorization: Bearer token"}, &CoreAnalysis{CoreFile: "core.1"})
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("postAnalysis() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if err != nil && !errors.Is(err, ErrPostFailed) {
+				t.Errorf("expected ErrPostFailed, got %v", err)
+			}
+			if posted != tt.expectPosted {
+				t.Errorf("posted %d times, expected %d", posted, tt.expectPosted)
+			}
+		})
+	}
+}
+
+// TestPostAnalysisUnreachable validates that a refused connection is
+// reported as ErrPostFailed.
+func TestPostAnalysisUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	endpoint := server.URL
+	server.Close()
+
+	client := &http.Client{Timeout: time.Second}
+	if err := postAnalysis(client, endpoint, nil, &CoreAnalysis{CoreFile: "core.1"}); !errors.Is(err, ErrPostFailed) {
+		t.Errorf("expected ErrPostFailed, got %v", err)
+	}
+}
+
+// TestValidatePostFlags validates the --post-url, --header and
+// --post-timeout checks.
+func TestValidatePostFlags(t *testing.T) {
+	tests := []struct {
+		name      string
+		url       string
+		headers   []string
+		timeout   time.Duration
+		required  bool
+		expectErr bool
+	}{
+		{name: "unset", timeout: defaultPostTimeout},
+		{name: "valid", url: "https://example.com/cores", headers: []string{"X-Team: db"}, timeout: defaultPostTimeout, required: true},
+		{name: "header without url", headers: []string{"X-Team: db"}, timeout: defaultPostTimeout, expectErr: true},
+		{name: "required without url", required: true, timeout: defaultPostTimeout, expectErr: true},
+		{name: "unsupported scheme", url: "ftp://example.com", timeout: defaultPostTimeout, expectErr: true},
+		{name: "malformed header", url: "http://example.com", headers: []string{"X-Team"}, timeout: defaultPostTimeout, expectErr: true},
+		{name: "zero timeout", url: "http://example.com", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postURL, postHeaders, postTimeout, postRequired = tt.url, tt.headers, tt.timeout, tt.required
+			defer func() {
+				postURL, postHeaders, postTimeout, postRequired = "", nil, defaultPostTimeout, false
+			}()
+
+			if err := validatePostFlags(); (err != nil) != tt.expectErr {
+				t.Errorf("validatePostFlags() error = %v, expectErr %v", err, tt.expectErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package coreinfo
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// PrereqsCmd lists the coreinfo prerequisites and whether each is satisfied.
+// Unlike the analysis itself, it needs no core file arguments.
+var PrereqsCmd = &cobra.Command{
+	Use:   "prereqs",
+	Short: "Check coreinfo prerequisites",
+	Long:  "Run the coreinfo prerequisite checks and report what is needed and whether each requirement is satisfied, without analyzing any core files.",
+	Args:  cobra.NoArgs,
+	RunE:  RunPrereqs,
+}
+
+// PrereqCheck is the result of a single prerequisite check.
+type PrereqCheck struct {
+	Name      string
+	Required  string
+	Satisfied bool
+	Detail    string
+}
+
+// checkGDB reports whether gdb is available and which version it is.
+func checkGDB() PrereqCheck {
+	check := PrereqCheck{Name: "gdb", Required: "GDB installed and in PATH"}
+	path, err := exec.LookPath("gdb")
+	if err != nil {
+		check.Detail = "not found in PATH; install it with your package manager (e.g. 'yum install gdb')"
+		return check
+	}
+	check.Satisfied = true
+	check.Detail = path
+	if output, err := exec.Command(path, "--version").Output(); err == nil {
+		firstLine := strings.SplitN(string(output), "\n", 2)[0]
+		check.Detail = fmt.Sprintf("%s (%s)", strings.TrimSpace(firstLine), path)
+	}
+	return check
+}
+
+// checkFileCommand reports whether the 'file' utility used to recognize
+// core files is available, at --file-path when set.
+func checkFileCommand() PrereqCheck {
+	check := PrereqCheck{Name: "file", Required: "'file' utility installed and in PATH"}
+	if fileCommandPath != "" {
+		check.Required = "'file' utility at " + fileCommandPath
+		if err := validateFileCommandPath(); err != nil {
+			check.Detail = err.Error()
+			return check
+		}
+	}
+	path, err := exec.LookPath(fileCommand())
+	if err != nil {
+		check.Detail = "not found in PATH; install it with your package manager (e.g. 'yum install file') or set --file-path"
+		return check
+	}
+	check.Satisfied = true
+	check.Detail = path
+	return check
+}
+
+// checkPostgresBinary reports whether the postgres binary under GPHOME exists.
+func checkPostgresBinary() PrereqCheck {
+	check := PrereqCheck{Name: "postgres", Required: "$GPHOME/bin/postgres present"}
+	path, err := getPostgresPath()
+	if err != nil {
+		check.Detail = err.Error()
+		return check
+	}
+	check.Satisfied = true
+	check.Detail = path
+	return check
+}
+
+// checkOutputDir reports whether the output directory is writable, which is
+// where extracted GDB command files and gdb logs are written.
+func checkOutputDir(dir string) PrereqCheck {
+	check := PrereqCheck{Name: "output directory", Required: "write access to " + dir}
+	f, err := os.CreateTemp(dir, ".cbtoolbox-prereq-*")
+	if err != nil {
+		check.Detail = fmt.Sprintf("not writable: %v", err)
+		return check
+	}
+	f.Close()
+	os.Remove(f.Name())
+	check.Satisfied = true
+	check.Detail = "writable"
+	return check
+}
+
+// runPrereqChecks runs every coreinfo prerequisite check.
+func runPrereqChecks(outputDir string) []PrereqCheck {
+	return []PrereqCheck{
+		checkGDB(),
+		checkFileCommand(),
+		checkPostgresBinary(),
+		checkOutputDir(outputDir),
+	}
+}
+
+// printPrereqChecks writes the checks as an aligned table, with the status
+// colored by severity when color is enabled for w.
+func printPrereqChecks(w io.Writer, checks []PrereqCheck) {
+	color := useColor(w)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHECK\tSTATUS\tREQUIRED\tDETAIL")
+	for _, check := range checks {
+		status := "FAIL"
+		if check.Satisfied {
+			status = "PASS"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", check.Name, colorSeverity(status, color), check.Required, check.Detail)
+	}
+	tw.Flush()
+}
+
+// RunPrereqs contains the logic for the coreinfo prereqs command.
+// Returns an error if any prerequisite is not satisfied.
+func RunPrereqs(cmd *cobra.Command, args []string) error {
+	if err := validateColorMode(); err != nil {
+		return err
+	}
+	outputDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to determine working directory: %v", err)
+	}
+
+	checks := runPrereqChecks(outputDir)
+	printPrereqChecks(os.Stdout, checks)
+
+	failed := 0
+	for _, check := range checks {
+		if !check.Satisfied {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d prerequisite(s) not satisfied", failed)
+	}
+	return nil
+}
+
+func init() {
+	PrereqsCmd.Flags().StringVarP(&fileCommandPath, "file-path", "", "", "Path to the 'file' executable used to recognize core files (default: look up in PATH)")
+	PrereqsCmd.Flags().StringVarP(&colorMode, "color", "", colorAuto, "Color PASS/FAIL statuses: auto, always or never")
+	CoreinfoCmd.AddCommand(PrereqsCmd)
+}
@@ -0,0 +1,108 @@
+package coreinfo
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFakeTool creates an executable shell script named name in dir.
+func writeFakeTool(t *testing.T, dir, name, script string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script+"\n"), 0755); err != nil {
+		t.Fatalf("failed to write fake %s: %v", name, err)
+	}
+}
+
+// TestRunPrereqChecks validates that each prerequisite is reported with its
+// status and detail.
+func TestRunPrereqChecks(t *testing.T) {
+	binDir := t.TempDir()
+	writeFakeTool(t, binDir, "gdb", `echo "GNU gdb (GDB) 12.1"; echo "Copyright"`)
+	writeFakeTool(t, binDir, "file", `echo "data"`)
+
+	gphome := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(gphome, "bin"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFakeTool(t, filepath.Join(gphome, "bin"), "postgres", "exit 0")
+
+	tests := []struct {
+		name      string
+		path      string
+		gphome    string
+		satisfied map[string]bool
+	}{
+		{
+			name:   "all satisfied",
+			path:   binDir,
+			gphome: gphome,
+			satisfied: map[string]bool{
+				"gdb": true, "file": true, "postgres": true, "output directory": true,
+			},
+		},
+		{
+			name:   "tools and GPHOME missing",
+			path:   t.TempDir(),
+			gphome: "",
+			satisfied: map[string]bool{
+				"gdb": false, "file": false, "postgres": false, "output directory": true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("PATH", tt.path)
+			t.Setenv("GPHOME", tt.gphome)
+
+			checks := runPrereqChecks(t.TempDir())
+			if len(checks) != len(tt.satisfied) {
+				t.Fatalf("expected %d checks, got %d", len(tt.satisfied), len(checks))
+			}
+			for _, check := range checks {
+				want, ok := tt.satisfied[check.Name]
+				if !ok {
+					t.Errorf("unexpected check %q", check.Name)
+					continue
+				}
+				if check.Satisfied != want {
+					t.Errorf("check %q: expected satisfied=%v, got %v (%s)", check.Name, want, check.Satisfied, check.Detail)
+				}
+				if check.Name == "gdb" && want && !strings.Contains(check.Detail, "GNU gdb (GDB) 12.1") {
+					t.Errorf("expected gdb version in detail, got %q", check.Detail)
+				}
+			}
+		})
+	}
+}
+
+// TestCheckOutputDirNotWritable validates that an unwritable directory fails the check.
+func TestCheckOutputDirNotWritable(t *testing.T) {
+	check := checkOutputDir(filepath.Join(t.TempDir(), "missing"))
+	if check.Satisfied {
+		t.Error("expected missing directory to fail the output directory check")
+	}
+}
+
+// TestPrintPrereqChecks validates the table layout of the prereqs output.
+func TestPrintPrereqChecks(t *testing.T) {
+	var buf bytes.Buffer
+	printPrereqChecks(&buf, []PrereqCheck{
+		{Name: "gdb", Required: "GDB installed and in PATH", Satisfied: true, Detail: "/usr/bin/gdb"},
+		{Name: "file", Required: "'file' utility installed and in PATH", Detail: "not found"},
+	})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected header and 2 rows, got %d lines:\n%s", len(lines), buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "CHECK") {
+		t.Errorf("expected header row, got %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "PASS") || !strings.Contains(lines[2], "FAIL") {
+		t.Errorf("unexpected status columns:\n%s", buf.String())
+	}
+}
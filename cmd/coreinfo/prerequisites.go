@@ -1,18 +1,22 @@
 package coreinfo
 
 import (
+	"debug/elf"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"syscall"
 )
 
 // checkPrerequisites verifies that all necessary tools and configurations are available.
 var checkPrerequisites = func() error {
 	if err := checkGDBAvailability(); err != nil {
-		return fmt.Errorf("gdb not found: please install GDB using your system package manager (e.g. 'yum install gdb' or 'apt-get install gdb')")
+		return fmt.Errorf("%w: please install GDB using your system package manager (e.g. 'yum install gdb' or 'apt-get install gdb')", ErrGDBNotFound)
 	}
 
 	// Add more prerequisite checks here if needed
@@ -28,18 +32,52 @@ func checkGDBAvailability() error {
 	return nil
 }
 
+// fileCommandPath is the --file-path override of the 'file' executable used
+// to recognize core files; when empty, 'file' is looked up in PATH.
+var fileCommandPath string
+
+// fileCommand returns the 'file' executable used to recognize core files.
+func fileCommand() string {
+	if fileCommandPath != "" {
+		return fileCommandPath
+	}
+	return "file"
+}
+
+// validateFileCommandPath checks that --file-path names an executable file,
+// so a wrong path fails up front rather than once per core.
+func validateFileCommandPath() error {
+	if fileCommandPath == "" {
+		return nil
+	}
+	info, err := os.Stat(fileCommandPath)
+	if err != nil {
+		return fmt.Errorf("invalid --file-path: %w", err)
+	}
+	if !info.Mode().IsRegular() || info.Mode().Perm()&0111 == 0 {
+		return fmt.Errorf("invalid --file-path: %s is not an executable file", fileCommandPath)
+	}
+	return nil
+}
+
+// strictELF enables --strict-elf, which only accepts files whose ELF header
+// type is ET_CORE. By default any file 'file' describes as ELF is accepted,
+// including executables and shared objects.
+var strictELF bool
+
 // prerequisites.go
 type FileInfo struct {
-	Platform    string
-	RealUID    string
-	EffUID     string
-	RealGID    string
-	EffGID     string
-	ExecPath   string
+	Platform string
+	RealUID  string
+	EffUID   string
+	RealGID  string
+	EffGID   string
+	ExecPath string
+	ELFClass elf.Class // ELFCLASSNONE when the header could not be read
 }
 
 func isCoreFile(filePath string) (bool, *FileInfo, error) {
-	cmd := exec.Command("file", filePath)
+	cmd := exec.Command(fileCommand(), filePath)
 	output, err := cmd.Output()
 	if err != nil {
 		fmt.Printf("Debug: 'file' command failed for '%s': %v\n", filePath, err)
@@ -47,6 +85,9 @@ func isCoreFile(filePath string) (bool, *FileInfo, error) {
 	}
 	outputStr := string(output)
 	isCore := strings.Contains(outputStr, "core file") || strings.Contains(outputStr, "ELF")
+	if isCore && strictELF {
+		isCore = getELFType(filePath) == elf.ET_CORE
+	}
 
 	var info *FileInfo
 	if isCore {
@@ -73,14 +114,109 @@ func isCoreFile(filePath string) (bool, *FileInfo, error) {
 		if match := regexp.MustCompile(`execfn: '([^']+)'`).FindStringSubmatch(outputStr); len(match) > 1 {
 			info.ExecPath = match[1]
 		}
+		// ELF class (32-bit or 64-bit)
+		info.ELFClass = getELFClass(filePath)
 	}
 
 	return isCore, info, nil
 }
 
+// getELFClass returns the ELF class of the file at path, or ELFCLASSNONE
+// if the file is not a readable ELF file.
+func getELFClass(path string) elf.Class {
+	f, err := elf.Open(path)
+	if err != nil {
+		return elf.ELFCLASSNONE
+	}
+	defer f.Close()
+	return f.Class
+}
+
+// getELFType returns the ELF type of the file at path, such as ET_CORE or
+// ET_EXEC, or ET_NONE if the file is not a readable ELF file.
+func getELFType(path string) elf.Type {
+	f, err := elf.Open(path)
+	if err != nil {
+		return elf.ET_NONE
+	}
+	defer f.Close()
+	return f.Type
+}
+
+// elfClassBits describes an ELF class as a word size for error messages.
+func elfClassBits(class elf.Class) string {
+	switch class {
+	case elf.ELFCLASS32:
+		return "32-bit"
+	case elf.ELFCLASS64:
+		return "64-bit"
+	default:
+		return "unknown"
+	}
+}
+
+// checkELFClassMatch verifies that the core and the binary selected to
+// analyze it share the same ELF class. gdb fails with cryptic errors when
+// a 64-bit core is loaded against a 32-bit binary or vice versa.
+// The check is skipped when either class cannot be determined.
+func checkELFClassMatch(coreFile string, coreInfo *FileInfo, binary string) error {
+	if coreInfo == nil || coreInfo.ELFClass == elf.ELFCLASSNONE {
+		return nil
+	}
+	binaryClass := getELFClass(binary)
+	if binaryClass == elf.ELFCLASSNONE || binaryClass == coreInfo.ELFClass {
+		return nil
+	}
+	return fmt.Errorf("%w: core file %s is %s but binary %s is %s; analyze it with a %s build of postgres (see --binary)",
+		ErrELFClassMismatch, coreFile, elfClassBits(coreInfo.ELFClass), binary, elfClassBits(binaryClass), elfClassBits(coreInfo.ELFClass))
+}
+
+// coreRejection records why a path given for analysis was not analyzed.
+type coreRejection struct {
+	Path   string
+	Reason string
+}
+
+// permissionError wraps an EACCES/EPERM error for path in ErrPermissionDenied
+// with a hint on how to gain access. Other errors are returned unchanged.
+func permissionError(path string, err error) error {
+	if !errors.Is(err, fs.ErrPermission) {
+		return err
+	}
+	return fmt.Errorf("%w: %s: %s", ErrPermissionDenied, path, permissionHint(path))
+}
+
+// permissionHint suggests how to make path readable, naming its owner when
+// it can be determined.
+func permissionHint(path string) string {
+	owner := "its owner"
+	if info, err := os.Lstat(path); err == nil {
+		if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+			owner = fmt.Sprintf("its owner (uid %d)", stat.Uid)
+		}
+	}
+	return fmt.Sprintf("run cbtoolbox as %s or root (e.g. with sudo), or adjust its ownership or permissions, e.g. 'sudo chown $(id -un) %s'", owner, path)
+}
+
+// checkReadable verifies that path can be opened for reading. `file` and
+// gdb report unreadable paths with generic messages, so permission problems
+// are detected up front.
+func checkReadable(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return permissionError(path, err)
+	}
+	return f.Close()
+}
+
 // validateAndAddCoreFile handles the validation of a single potential core file
-// Returns error if validation fails
-func validateAndAddCoreFile(file string, coreFiles *[]string, coreInfos map[string]*FileInfo) error {
+// Unreadable files are added to rejections. Returns error if validation fails
+func validateAndAddCoreFile(file string, coreFiles *[]string, coreInfos map[string]*FileInfo, rejections *[]coreRejection) error {
+	if err := checkReadable(file); err != nil {
+		*rejections = append(*rejections, coreRejection{Path: file, Reason: err.Error()})
+		return nil
+	}
+
 	valid, info, err := isCoreFile(file)
 	if err != nil {
 		return fmt.Errorf("failed to check core file %s: %v", file, err)
@@ -94,41 +230,82 @@ func validateAndAddCoreFile(file string, coreFiles *[]string, coreInfos map[stri
 	return nil
 }
 
+// expandGlobArgs expands arguments that do not exist as paths but contain
+// glob metacharacters, for when the shell did not expand them (quoted, or
+// run via exec without a shell). Patterns that are invalid or match no files
+// are added to rejections.
+func expandGlobArgs(args []string, rejections *[]coreRejection) []string {
+	var paths []string
+	for _, arg := range args {
+		if _, err := os.Stat(arg); err == nil || !strings.ContainsAny(arg, "*?[") {
+			paths = append(paths, arg)
+			continue
+		}
+		matches, err := filepath.Glob(arg)
+		switch {
+		case err != nil:
+			*rejections = append(*rejections, coreRejection{Path: arg, Reason: fmt.Sprintf("invalid pattern %s: %v", arg, err)})
+		case len(matches) == 0:
+			*rejections = append(*rejections, coreRejection{Path: arg, Reason: fmt.Sprintf("pattern %s matched no files", arg)})
+		default:
+			paths = append(paths, matches...)
+		}
+	}
+	return paths
+}
+
 // validateCoreFiles validates the input paths to determine if they are core files or directories containing core files.
+// Unexpanded glob patterns are expanded first (see expandGlobArgs).
 func validateCoreFiles(args []string) ([]string, map[string]*FileInfo, error) {
 	if len(args) == 0 {
-		return nil, nil, fmt.Errorf("no core files specified: usage 'cbtoolbox coreinfo <path-to-core-file>' or 'cbtoolbox coreinfo <directory-with-cores>'")
+		return nil, nil, fmt.Errorf("%w: usage 'cbtoolbox coreinfo <path-to-core-file>' or 'cbtoolbox coreinfo <directory-with-cores>'", ErrNoCoreFiles)
 	}
 
 	var coreFiles []string
+	var rejections []coreRejection
 	coreInfos := make(map[string]*FileInfo)
 
-	for _, arg := range args {
+	for _, arg := range expandGlobArgs(args, &rejections) {
 		info, err := os.Stat(arg)
 		if err != nil {
-			fmt.Printf("Debug: Error accessing path '%s': %v\n", arg, err)
+			rejections = append(rejections, coreRejection{Path: arg, Reason: permissionError(arg, err).Error()})
 			continue
 		}
 
 		if info.IsDir() {
+			// An unreadable directory would otherwise look empty
+			if err := checkReadable(arg); err != nil {
+				rejections = append(rejections, coreRejection{Path: arg, Reason: err.Error()})
+				continue
+			}
 			files, err := filepath.Glob(filepath.Join(arg, "*"))
 			if err != nil {
 				return nil, nil, fmt.Errorf("failed to read directory %s: %v", arg, err)
 			}
 			for _, file := range files {
-				if err := validateAndAddCoreFile(file, &coreFiles, coreInfos); err != nil {
+				if err := validateAndAddCoreFile(file, &coreFiles, coreInfos, &rejections); err != nil {
 					return nil, nil, err
 				}
 			}
 		} else {
-			if err := validateAndAddCoreFile(arg, &coreFiles, coreInfos); err != nil {
+			if err := validateAndAddCoreFile(arg, &coreFiles, coreInfos, &rejections); err != nil {
 				return nil, nil, err
 			}
 		}
 	}
 
+	// Report rejected paths and continue with the remaining cores
+	var reasons []string
+	for _, rejection := range rejections {
+		fmt.Fprintf(os.Stderr, "Rejected %s: %s\n", rejection.Path, rejection.Reason)
+		reasons = append(reasons, rejection.Reason)
+	}
+
 	if len(coreFiles) == 0 {
-		return nil, nil, fmt.Errorf("no valid core files provided")
+		if len(reasons) > 0 {
+			return nil, nil, fmt.Errorf("%w: %s", ErrNoValidCoreFiles, strings.Join(reasons, "; "))
+		}
+		return nil, nil, ErrNoValidCoreFiles
 	}
 	return coreFiles, coreInfos, nil
 }
@@ -2,13 +2,17 @@ package coreinfo
 
 import (
 	"bytes"
+	"debug/elf"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"testing"
 )
 
@@ -109,6 +113,17 @@ func TestValidateCoreFiles(t *testing.T) {
 	}
 }
 
+// TestValidateCoreFilesErrors validates the sentinel errors returned for missing or invalid core files.
+func TestValidateCoreFilesErrors(t *testing.T) {
+	if _, _, err := validateCoreFiles(nil); !errors.Is(err, ErrNoCoreFiles) {
+		t.Errorf("Expected ErrNoCoreFiles for no arguments, got: %v", err)
+	}
+
+	if _, _, err := validateCoreFiles([]string{"/nonexistent/core"}); !errors.Is(err, ErrNoValidCoreFiles) {
+		t.Errorf("Expected ErrNoValidCoreFiles for missing path, got: %v", err)
+	}
+}
+
 func TestCoreInfoVerboseOutput(t *testing.T) {
 	// Mock checkPrerequisites to always succeed
 	checkPrerequisites = func() error {
@@ -178,7 +193,7 @@ func TestCoreInfoVerboseOutput(t *testing.T) {
 		}
 	}
 
-	// Validate summary output
+	// Validate summary output, which is only printed in verbose mode
 	if len(coreFiles) > 1 {
 		if !strings.Contains(output, fmt.Sprintf("Validated core files: [%s %s]", coreFiles[0], coreFiles[1])) &&
 			!strings.Contains(output, fmt.Sprintf("Validated core files: [%s %s]", coreFiles[1], coreFiles[0])) {
@@ -189,6 +204,17 @@ func TestCoreInfoVerboseOutput(t *testing.T) {
 			t.Errorf("Expected summary output to contain single core file, got:\n%s", output)
 		}
 	}
+
+	// Without verbose mode the validation lines stay out of the output
+	verbose = false
+	output = captureOutput(func() {
+		if err := RunCoreInfo(nil, coreFiles); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+	if strings.Contains(output, "Validated core files") || strings.Contains(output, "Validating file") {
+		t.Errorf("Expected no validation lines without verbose, got:\n%s", output)
+	}
 }
 
 func captureOutput(f func()) string {
@@ -207,3 +233,286 @@ func captureOutput(f func()) string {
 
 	return buf.String()
 }
+
+// writeELFHeader writes a minimal ELF file header of the given class and type
+// to path, which is enough for debug/elf to identify the file.
+func writeELFHeader(t *testing.T, path string, class elf.Class, typ elf.Type) {
+	t.Helper()
+
+	ident := [elf.EI_NIDENT]byte{0x7f, 'E', 'L', 'F', byte(class), byte(elf.ELFDATA2LSB), byte(elf.EV_CURRENT)}
+	var buf bytes.Buffer
+	var hdr interface{}
+	if class == elf.ELFCLASS32 {
+		hdr = elf.Header32{Ident: ident, Type: uint16(typ), Machine: uint16(elf.EM_386), Version: uint32(elf.EV_CURRENT), Ehsize: 52}
+	} else {
+		hdr = elf.Header64{Ident: ident, Type: uint16(typ), Machine: uint16(elf.EM_X86_64), Version: uint32(elf.EV_CURRENT), Ehsize: 64}
+	}
+	if err := binary.Write(&buf, binary.LittleEndian, hdr); err != nil {
+		t.Fatalf("Failed to encode ELF header: %v", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("Failed to write ELF file %s: %v", path, err)
+	}
+}
+
+// TestCheckELFClassMatch validates detection of 32-bit vs 64-bit core/binary mismatches.
+func TestCheckELFClassMatch(t *testing.T) {
+	tempDir := t.TempDir()
+
+	core64 := filepath.Join(tempDir, "core.64")
+	binary32 := filepath.Join(tempDir, "postgres32")
+	binary64 := filepath.Join(tempDir, "postgres64")
+	notELF := filepath.Join(tempDir, "postgres.sh")
+
+	writeELFHeader(t, core64, elf.ELFCLASS64, elf.ET_CORE)
+	writeELFHeader(t, binary32, elf.ELFCLASS32, elf.ET_EXEC)
+	writeELFHeader(t, binary64, elf.ELFCLASS64, elf.ET_EXEC)
+	if err := os.WriteFile(notELF, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatalf("Failed to write non-ELF binary: %v", err)
+	}
+
+	coreInfo := &FileInfo{ELFClass: getELFClass(core64)}
+	if coreInfo.ELFClass != elf.ELFCLASS64 {
+		t.Fatalf("Expected core ELF class ELFCLASS64, got %v", coreInfo.ELFClass)
+	}
+
+	if err := checkELFClassMatch(core64, coreInfo, binary64); err != nil {
+		t.Errorf("Unexpected error for matching ELF classes: %v", err)
+	}
+
+	err := checkELFClassMatch(core64, coreInfo, binary32)
+	if !errors.Is(err, ErrELFClassMismatch) {
+		t.Errorf("Expected ErrELFClassMismatch, got: %v", err)
+	} else if !strings.Contains(err.Error(), "64-bit") || !strings.Contains(err.Error(), "32-bit") {
+		t.Errorf("Expected error to name both word sizes, got: %v", err)
+	}
+
+	if err := checkELFClassMatch(core64, coreInfo, notELF); err != nil {
+		t.Errorf("Expected check to be skipped for a non-ELF binary, got: %v", err)
+	}
+	if err := checkELFClassMatch(core64, &FileInfo{}, binary32); err != nil {
+		t.Errorf("Expected check to be skipped for an unknown core class, got: %v", err)
+	}
+}
+
+// TestPermissionError validates that EACCES and EPERM are reported with a privilege hint.
+func TestPermissionError(t *testing.T) {
+	core := filepath.Join(t.TempDir(), "core.1")
+	if err := os.WriteFile(core, []byte("\x7fELF"), 0644); err != nil {
+		t.Fatalf("Failed to write core file: %v", err)
+	}
+
+	for _, errno := range []syscall.Errno{syscall.EACCES, syscall.EPERM} {
+		err := permissionError(core, &fs.PathError{Op: "open", Path: core, Err: errno})
+		if !errors.Is(err, ErrPermissionDenied) {
+			t.Errorf("Expected ErrPermissionDenied for %v, got: %v", errno, err)
+			continue
+		}
+		if !strings.Contains(err.Error(), "sudo") || !strings.Contains(err.Error(), fmt.Sprintf("uid %d", os.Getuid())) {
+			t.Errorf("Expected a hint naming the owner, got: %v", err)
+		}
+	}
+
+	notFound := &fs.PathError{Op: "open", Path: core, Err: syscall.ENOENT}
+	if err := permissionError(core, notFound); err != notFound {
+		t.Errorf("Expected other errors to be returned unchanged, got: %v", err)
+	}
+}
+
+// TestValidateCoreFilesPermissionDenied validates that unreadable cores are rejected and the rest analyzed.
+func TestValidateCoreFilesPermissionDenied(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root can read files regardless of their permissions")
+	}
+
+	tempDir := t.TempDir()
+	readable := filepath.Join(tempDir, "core.1")
+	unreadable := filepath.Join(tempDir, "core.2")
+	for _, path := range []string{readable, unreadable} {
+		if err := os.WriteFile(path, []byte("\x7fELF"), 0644); err != nil {
+			t.Fatalf("Failed to write core file: %v", err)
+		}
+	}
+	if err := os.Chmod(unreadable, 0); err != nil {
+		t.Fatalf("Failed to chmod core file: %v", err)
+	}
+
+	files, _, err := validateCoreFiles([]string{readable, unreadable})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(files) != 1 || files[0] != readable {
+		t.Errorf("Expected only %s to be validated, got %v", readable, files)
+	}
+
+	_, _, err = validateCoreFiles([]string{unreadable})
+	if !errors.Is(err, ErrNoValidCoreFiles) || !strings.Contains(err.Error(), "permission denied") {
+		t.Errorf("Expected ErrNoValidCoreFiles naming the permission problem, got: %v", err)
+	}
+}
+
+// TestFileCommandPath validates --file-path checks and that the selected
+// 'file' executable is used to recognize core files.
+func TestFileCommandPath(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "file")
+	content := "#!/bin/sh\necho \"$1: ELF 64-bit LSB core file, x86-64, from 'postgres', execfn: '/opt/cbdb/bin/postgres', platform: 'x86_64'\"\n"
+	if err := os.WriteFile(script, []byte(content), 0755); err != nil {
+		t.Fatal(err)
+	}
+	notExecutable := filepath.Join(dir, "file.txt")
+	if err := os.WriteFile(notExecutable, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		path      string
+		expectErr bool
+	}{
+		{name: "unset", path: ""},
+		{name: "executable", path: script},
+		{name: "missing", path: filepath.Join(dir, "missing"), expectErr: true},
+		{name: "not executable", path: notExecutable, expectErr: true},
+		{name: "directory", path: dir, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileCommandPath = tt.path
+			defer func() { fileCommandPath = "" }()
+
+			if err := validateFileCommandPath(); (err != nil) != tt.expectErr {
+				t.Errorf("validateFileCommandPath() error = %v, expectErr %v", err, tt.expectErr)
+			}
+		})
+	}
+
+	fileCommandPath = script
+	defer func() { fileCommandPath = "" }()
+	core := filepath.Join(dir, "core.1")
+	if err := os.WriteFile(core, []byte("\x7fELF"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	isCore, info, err := isCoreFile(core)
+	if err != nil || !isCore {
+		t.Fatalf("isCoreFile() = %v, %v", isCore, err)
+	}
+	if info.ExecPath != "/opt/cbdb/bin/postgres" {
+		t.Errorf("expected exec path from --file-path output, got %q", info.ExecPath)
+	}
+}
+
+// fakeFileCommand selects a stand-in for file(1) with --file-path, so tests
+// do not depend on the host's file utility. It reports every ELF file as a
+// core file and anything else as data.
+func fakeFileCommand(t *testing.T) {
+	t.Helper()
+	script := filepath.Join(t.TempDir(), "file")
+	content := `#!/bin/sh
+if [ "$(head -c 4 "$1")" = "$(printf '\177ELF')" ]; then
+	echo "$1: ELF 64-bit LSB core file, x86-64, version 1 (SYSV), SVR4-style, from 'postgres'"
+else
+	echo "$1: data"
+fi
+`
+	if err := os.WriteFile(script, []byte(content), 0755); err != nil {
+		t.Fatal(err)
+	}
+	fileCommandPath = script
+	t.Cleanup(func() { fileCommandPath = "" })
+}
+
+// TestStrictELF validates that --strict-elf only accepts ET_CORE files.
+func TestStrictELF(t *testing.T) {
+	fakeFileCommand(t)
+	tempDir := t.TempDir()
+	core := filepath.Join(tempDir, "core.1")
+	executable := filepath.Join(tempDir, "core.exec")
+	sharedObject := filepath.Join(tempDir, "core.so")
+	writeELFHeader(t, core, elf.ELFCLASS64, elf.ET_CORE)
+	writeELFHeader(t, executable, elf.ELFCLASS64, elf.ET_EXEC)
+	writeELFHeader(t, sharedObject, elf.ELFCLASS64, elf.ET_DYN)
+
+	tests := []struct {
+		name     string
+		strict   bool
+		expected int
+	}{
+		{name: "lenient", strict: false, expected: 3},
+		{name: "strict", strict: true, expected: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strictELF = tt.strict
+			defer func() { strictELF = false }()
+
+			files, _, err := validateCoreFiles([]string{tempDir})
+			if err != nil {
+				t.Fatalf("validateCoreFiles() error = %v", err)
+			}
+			if len(files) != tt.expected {
+				t.Errorf("expected %d core files, got %v", tt.expected, files)
+			}
+			if tt.strict && (len(files) != 1 || files[0] != core) {
+				t.Errorf("expected only %s, got %v", core, files)
+			}
+		})
+	}
+}
+
+// TestValidateCoreFilesGlob validates expansion of glob patterns the shell
+// did not expand.
+func TestValidateCoreFilesGlob(t *testing.T) {
+	fakeFileCommand(t)
+	tempDir := t.TempDir()
+	for _, name := range []string{"core.1", "core.2", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte("\x7fELF"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		args      []string
+		expected  int
+		expectErr bool
+	}{
+		{name: "pattern", args: []string{filepath.Join(tempDir, "core.*")}, expected: 2},
+		{name: "pattern and path", args: []string{filepath.Join(tempDir, "core.[1]"), filepath.Join(tempDir, "core.2")}, expected: 2},
+		{name: "no matches", args: []string{filepath.Join(tempDir, "vmcore.*")}, expectErr: true},
+		{name: "invalid pattern", args: []string{filepath.Join(tempDir, "core.[")}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, _, err := validateCoreFiles(tt.args)
+			if tt.expectErr {
+				if !errors.Is(err, ErrNoValidCoreFiles) {
+					t.Errorf("expected ErrNoValidCoreFiles, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("validateCoreFiles() error = %v", err)
+			}
+			if len(files) != tt.expected {
+				t.Errorf("expected %d core files, got %v", tt.expected, files)
+			}
+		})
+	}
+}
+
+// TestExpandGlobArgsLiteral validates that an existing path containing glob
+// metacharacters is used as is.
+func TestExpandGlobArgsLiteral(t *testing.T) {
+	literal := filepath.Join(t.TempDir(), "core.[1]")
+	if err := os.WriteFile(literal, []byte("\x7fELF"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var rejections []coreRejection
+	if paths := expandGlobArgs([]string{literal}, &rejections); len(paths) != 1 || paths[0] != literal || len(rejections) > 0 {
+		t.Errorf("expandGlobArgs() = %v, rejections %v", paths, rejections)
+	}
+}
@@ -0,0 +1,64 @@
+package coreinfo
+
+import (
+	"regexp"
+	"strings"
+)
+
+// commandLineRegex captures the command line gdb reports for a core, e.g.
+// "Core was generated by `postgres -D /data/gpseg0 -p 7000'.".
+var commandLineRegex = regexp.MustCompile("(?m)^Core was generated by `(.*)'\\.?\\s*$")
+
+// extractCommandLine returns the command line of the process that dumped
+// the core, as gdb printed it, or "" when gdb did not report it.
+func extractCommandLine(gdbOutput string) string {
+	if match := commandLineRegex.FindStringSubmatch(gdbOutput); len(match) > 1 {
+		return match[1]
+	}
+	return ""
+}
+
+// splitCommandLine splits a command line into its arguments on whitespace.
+// Single and double quotes group an argument containing spaces, and a
+// backslash outside single quotes escapes the next character; the quotes
+// and backslashes themselves are removed. An unterminated quote extends to
+// the end of the line, since the kernel truncates long command lines.
+func splitCommandLine(commandLine string) []string {
+	var (
+		args    []string
+		current strings.Builder
+		inArg   bool
+		quote   rune
+		escaped bool
+	)
+	for _, r := range commandLine {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inArg = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args
+}
@@ -0,0 +1,52 @@
+package coreinfo
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestSplitCommandLine validates splitting command lines into arguments,
+// including quoted and escaped arguments.
+func TestSplitCommandLine(t *testing.T) {
+	tests := []struct {
+		name        string
+		commandLine string
+		expected    []string
+	}{
+		{name: "empty", commandLine: "", expected: nil},
+		{name: "postmaster", commandLine: "/usr/local/cloudberry/bin/postgres -D /data/gpseg0 -p 7000", expected: []string{"/usr/local/cloudberry/bin/postgres", "-D", "/data/gpseg0", "-p", "7000"}},
+		{name: "repeated spaces", commandLine: "postgres:  7000,  idle", expected: []string{"postgres:", "7000,", "idle"}},
+		{name: "double quotes", commandLine: `postgres -c "search_path=a b"`, expected: []string{"postgres", "-c", "search_path=a b"}},
+		{name: "single quotes keep backslashes", commandLine: `postgres -D '/data/my seg\0'`, expected: []string{"postgres", "-D", `/data/my seg\0`}},
+		{name: "escaped space", commandLine: `postgres -D /data/my\ seg`, expected: []string{"postgres", "-D", "/data/my seg"}},
+		{name: "empty quoted argument", commandLine: `postgres ''`, expected: []string{"postgres", ""}},
+		{name: "truncated quote", commandLine: `postgres -c "application_name=long`, expected: []string{"postgres", "-c", "application_name=long"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitCommandLine(tt.commandLine); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("splitCommandLine(%q) = %q, expected %q", tt.commandLine, got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestParseCommandLine validates that the command line and its arguments
+// are parsed from gdb output.
+func TestParseCommandLine(t *testing.T) {
+	analysis, err := parseCoreAnalysis(sampleGDBOutput, nil, "core.1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if analysis.CommandLine != "postgres: 7000, gpadmin postgres [local] con12 cmd3 SELECT" {
+		t.Errorf("unexpected command line %q", analysis.CommandLine)
+	}
+	expected := []string{"postgres:", "7000,", "gpadmin", "postgres", "[local]", "con12", "cmd3", "SELECT"}
+	if !reflect.DeepEqual(analysis.ProcessArgv, expected) {
+		t.Errorf("ProcessArgv = %q, expected %q", analysis.ProcessArgv, expected)
+	}
+	if analysis.ProcessArgs != "7000, gpadmin postgres [local] con12 cmd3 SELECT" {
+		t.Errorf("unexpected process args %q", analysis.ProcessArgs)
+	}
+}
@@ -0,0 +1,348 @@
+package coreinfo
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/edespino/cbtoolbox/cmd/internal/partial"
+	"github.com/spf13/cobra"
+)
+
+// Supported output formats for the coreinfo command.
+const (
+	formatText     = "text"
+	formatMarkdown = "markdown"
+	formatJSON     = "json"
+)
+
+// Formats lists the output formats supported by the coreinfo commands.
+var Formats = []string{formatText, formatMarkdown, formatJSON}
+
+// validateFormat checks if the provided format is supported.
+// Returns nil for valid formats (text, markdown, json) and an error for unsupported formats.
+func validateFormat(format string) error {
+	if slices.Contains(Formats, format) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s (supported formats: %s)", ErrInvalidFormat, format, strings.Join(Formats, ", "))
+}
+
+// parseFormats splits a comma-separated --format value into its formats,
+// so one analysis can be rendered several ways without re-running gdb.
+// Each format is validated, and repeated formats are only kept once.
+func parseFormats(value string) ([]string, error) {
+	var formats []string
+	seen := make(map[string]bool)
+	for _, format := range strings.Split(value, ",") {
+		format = strings.TrimSpace(format)
+		if err := validateFormat(format); err != nil {
+			return nil, err
+		}
+		if !seen[format] {
+			seen[format] = true
+			formats = append(formats, format)
+		}
+	}
+	return formats, nil
+}
+
+// formatFromFlags returns the output format selected on the command line.
+// The format is read per invocation rather than stored in a package global.
+// Returns the default text format when cmd is nil or has no --format flag.
+func formatFromFlags(cmd *cobra.Command) string {
+	if cmd == nil {
+		return formatText
+	}
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return formatText
+	}
+	return format
+}
+
+// renderAnalysis renders a core analysis in the given output format.
+func renderAnalysis(analysis *CoreAnalysis, format string) (string, error) {
+	switch format {
+	case formatMarkdown:
+		return renderMarkdown(analysis, includeGDBOutput), nil
+	case formatJSON:
+		return renderJSON(analysis)
+	default:
+		return renderText(analysis), nil
+	}
+}
+
+// renderJSON renders a core analysis as indented JSON. The raw gdb output
+// is not part of the document. Fields that cannot be marshaled are left
+// out of the document and reported to stderr.
+func renderJSON(analysis *CoreAnalysis) (string, error) {
+	data, failures, err := partial.JSON(analysis, "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal analysis of %s: %w", analysis.CoreFile, err)
+	}
+	for _, failure := range failures {
+		fmt.Fprintf(os.Stderr, "Warning: left out field %s of the analysis of %s\n", failure, analysis.CoreFile)
+	}
+	return string(data) + "\n", nil
+}
+
+// textSummary formats the human-readable summary block of an analysis.
+func textSummary(analysis *CoreAnalysis) string {
+	summary := fmt.Sprintf(`
+======================================================================
+Apache Cloudberry Core Dump Analysis Summary
+======================================================================
+
+- Core File: %s
+- Hostname: %s
+- Binary: %s
+- Platform: %s
+- User/Group: %s
+- Binary Path: %s
+- Signal: %s
+- Faulting Address: %s
+- Thread ID: %s
+- Process Args: %s
+- Detected Version: %s
+- Symbol Source: %s`,
+		analysis.CoreFile,
+		valueOrNA(analysis.Hostname),
+		analysis.Binary,
+		analysis.Platform,
+		analysis.UserInfo,
+		analysis.ExecPath,
+		analysis.Signal,
+		analysis.FaultAddress,
+		analysis.ThreadID,
+		analysis.ProcessArgs,
+		valueOrNA(analysis.DetectedVersion),
+		valueOrNA(analysis.SymbolSource))
+
+	if analysis.LikelyCause != nil {
+		summary += "\n- Likely Cause: " + analysis.LikelyCause.String()
+	}
+	if analysis.FaultRegion != "" {
+		summary += "\n- Fault Region: " + analysis.FaultRegion
+	}
+	if analysis.AbortMessage != "" {
+		summary += "\n- Abort Message: " + analysis.AbortMessage
+	}
+	if len(analysis.KnownIssues) > 0 {
+		summary += "\n- Known Issues: " + strings.Join(analysis.KnownIssues, ", ")
+	}
+	if analysis.BinaryOverride != "" {
+		summary += "\n- Binary Override: " + analysis.BinaryOverride
+	}
+	if analysis.CrashedThreadFrames > len(analysis.CrashedThread) {
+		summary += fmt.Sprintf("\n- Crashed Thread Frames: %d ... (truncated, %d shown)", analysis.CrashedThreadFrames, len(analysis.CrashedThread))
+	}
+	if analysis.ThreadSummary != nil {
+		summary += "\n- Threads: " + analysis.ThreadSummary.String()
+	}
+	if len(analysis.OpenFiles) > 0 {
+		summary += "\n- Open Files:"
+		for _, file := range analysis.OpenFiles {
+			summary += "\n  - " + file
+		}
+	}
+	if len(analysis.MemoryMap) > 0 {
+		summary += "\n- Memory Map:"
+		for _, region := range analysis.MemoryMap {
+			summary += "\n  - " + region.String()
+		}
+	}
+	if len(analysis.Warnings) > 0 {
+		summary += "\n- Warnings:"
+		for _, warning := range analysis.Warnings {
+			summary += "\n  - " + warning
+		}
+	}
+	return summary
+}
+
+// valueOrNA returns value, or "N/A" when it is empty.
+func valueOrNA(value string) string {
+	if value == "" {
+		return "N/A"
+	}
+	return value
+}
+
+// renderText renders the summary followed by the full gdb output.
+func renderText(analysis *CoreAnalysis) string {
+	var b strings.Builder
+	b.WriteString(textSummary(analysis))
+	b.WriteString("\n")
+
+	// Print the output of any --gdb-eval commands before the full transcript
+	if len(analysis.ExtraCommands) > 0 {
+		b.WriteString("\n======================================================================\n")
+		b.WriteString("=== Extra GDB Commands ===\n")
+		b.WriteString("======================================================================\n")
+		for _, command := range sortedKeys(analysis.ExtraCommands) {
+			fmt.Fprintf(&b, "\n(gdb) %s\n%s\n", command, analysis.ExtraCommands[command])
+		}
+	}
+
+	// Print the full GDB output after the summary. Saved analyses
+	// re-rendered by 'coreinfo show' have none.
+	if analysis.GDBOutput != "" {
+		b.WriteString("\n======================================================================\n")
+		b.WriteString("=== Detailed GDB Output ===\n")
+		b.WriteString("======================================================================\n\n")
+		b.WriteString(analysis.GDBOutput)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// sortedKeys returns the keys of m in sorted order, for stable output.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// markdownCell escapes a value for use inside a Markdown table cell.
+func markdownCell(value string) string {
+	value = strings.ReplaceAll(value, "|", `\|`)
+	return strings.ReplaceAll(value, "\n", " ")
+}
+
+// markdownFence returns a code fence longer than any backtick run in content,
+// so the fenced block cannot be terminated early by the content itself.
+func markdownFence(content string) string {
+	longest, run := 0, 0
+	for _, r := range content {
+		if r == '`' {
+			run++
+			if run > longest {
+				longest = run
+			}
+		} else {
+			run = 0
+		}
+	}
+	if longest < 3 {
+		return "```"
+	}
+	return strings.Repeat("`", longest+1)
+}
+
+// renderMarkdown renders an analysis as GitHub-flavored Markdown: a summary
+// table, the crashed thread's backtrace in a collapsible block, and the raw
+// gdb output when includeRaw is set.
+func renderMarkdown(analysis *CoreAnalysis, includeRaw bool) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "## Core Dump Analysis: `%s`\n\n", analysis.CoreFile)
+	b.WriteString("| Field | Value |\n")
+	b.WriteString("| --- | --- |\n")
+	rows := [][2]string{
+		{"Core File", analysis.CoreFile},
+		{"Hostname", valueOrNA(analysis.Hostname)},
+		{"Binary", analysis.Binary},
+		{"Platform", analysis.Platform},
+		{"User/Group", analysis.UserInfo},
+		{"Binary Path", analysis.ExecPath},
+		{"Signal", analysis.Signal},
+		{"Faulting Address", analysis.FaultAddress},
+		{"Thread ID", analysis.ThreadID},
+		{"Process Args", analysis.ProcessArgs},
+		{"Detected Version", valueOrNA(analysis.DetectedVersion)},
+		{"Symbol Source", valueOrNA(analysis.SymbolSource)},
+	}
+	if analysis.LikelyCause != nil {
+		rows = append(rows, [2]string{"Likely Cause", analysis.LikelyCause.String()})
+	}
+	if analysis.FaultRegion != "" {
+		rows = append(rows, [2]string{"Fault Region", analysis.FaultRegion})
+	}
+	if analysis.AbortMessage != "" {
+		rows = append(rows, [2]string{"Abort Message", analysis.AbortMessage})
+	}
+	if len(analysis.KnownIssues) > 0 {
+		rows = append(rows, [2]string{"Known Issues", strings.Join(analysis.KnownIssues, ", ")})
+	}
+	if analysis.BinaryOverride != "" {
+		rows = append(rows, [2]string{"Binary Override", analysis.BinaryOverride})
+	}
+	if analysis.ThreadSummary != nil {
+		rows = append(rows, [2]string{"Threads", analysis.ThreadSummary.String()})
+	}
+	for _, row := range rows {
+		fmt.Fprintf(&b, "| %s | %s |\n", row[0], markdownCell(row[1]))
+	}
+
+	if len(analysis.Warnings) > 0 {
+		b.WriteString("\n### Warnings\n\n")
+		for _, warning := range analysis.Warnings {
+			fmt.Fprintf(&b, "- %s\n", warning)
+		}
+	}
+
+	if len(analysis.CrashedThread) > 0 {
+		frames := make([]string, 0, len(analysis.CrashedThread))
+		for _, frame := range analysis.CrashedThread {
+			frames = append(frames, frame.Raw)
+		}
+		depth := fmt.Sprintf("%d frames", len(analysis.CrashedThread))
+		if analysis.CrashedThreadFrames > len(analysis.CrashedThread) {
+			frames = append(frames, "... (truncated)")
+			depth = fmt.Sprintf("%d of %d frames", len(analysis.CrashedThread), analysis.CrashedThreadFrames)
+		}
+		backtrace := strings.Join(frames, "\n")
+		fence := markdownFence(backtrace)
+
+		b.WriteString("\n<details>\n")
+		fmt.Fprintf(&b, "<summary>Crashed thread %s backtrace (%s)</summary>\n\n", analysis.ThreadID, depth)
+		fmt.Fprintf(&b, "%s\n%s\n%s\n\n", fence, backtrace, fence)
+		b.WriteString("</details>\n")
+	}
+
+	if len(analysis.OpenFiles) > 0 {
+		b.WriteString("\n### Open Files\n\n")
+		for _, file := range analysis.OpenFiles {
+			fmt.Fprintf(&b, "- `%s`\n", strings.ReplaceAll(file, "`", "'"))
+		}
+	}
+
+	if len(analysis.MemoryMap) > 0 {
+		regions := make([]string, 0, len(analysis.MemoryMap))
+		for _, region := range analysis.MemoryMap {
+			regions = append(regions, region.String())
+		}
+		memoryMap := strings.Join(regions, "\n")
+		fence := markdownFence(memoryMap)
+
+		b.WriteString("\n<details>\n")
+		fmt.Fprintf(&b, "<summary>Memory map (%d regions)</summary>\n\n", len(analysis.MemoryMap))
+		fmt.Fprintf(&b, "%s\n%s\n%s\n\n", fence, memoryMap, fence)
+		b.WriteString("</details>\n")
+	}
+
+	if len(analysis.ExtraCommands) > 0 {
+		b.WriteString("\n### Extra GDB Commands\n")
+		for _, command := range sortedKeys(analysis.ExtraCommands) {
+			output := analysis.ExtraCommands[command]
+			fence := markdownFence(output)
+			fmt.Fprintf(&b, "\n`%s`\n\n%stext\n%s\n%s\n", strings.ReplaceAll(command, "`", "'"), fence, output, fence)
+		}
+	}
+
+	if includeRaw {
+		raw := strings.TrimRight(analysis.GDBOutput, "\n")
+		fence := markdownFence(raw)
+		b.WriteString("\n### GDB Output\n\n")
+		fmt.Fprintf(&b, "%stext\n%s\n%s\n", fence, raw, fence)
+	}
+
+	b.WriteString("\n")
+	return b.String()
+}
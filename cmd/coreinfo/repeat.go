@@ -0,0 +1,72 @@
+package coreinfo
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// repeatCount is the number of times each core is analyzed with the hidden
+// --repeat flag. Values below 2 disable the stability check.
+var repeatCount int
+
+// analysisDiff returns the names of the CoreAnalysis fields that differ
+// between a and b. The raw gdb transcript is ignored, since only the parsed
+// result has to be stable.
+func analysisDiff(a, b *CoreAnalysis) []string {
+	va, vb := reflect.ValueOf(*a), reflect.ValueOf(*b)
+	var fields []string
+	for i := 0; i < va.NumField(); i++ {
+		name := va.Type().Field(i).Name
+		if name == "GDBOutput" {
+			continue
+		}
+		if !reflect.DeepEqual(va.Field(i).Interface(), vb.Field(i).Interface()) {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+// runRepeatCheck analyzes each core runs times and reports whether every run
+// produced the same CoreAnalysis as the first. Runs that differ are reported
+// with the fields that changed, followed by a pass/fail summary. The PASS
+// and FAIL statuses are colored when color is enabled for w.
+// Returns ErrNondeterministicAnalysis if any core's results differed.
+func runRepeatCheck(w io.Writer, coreFiles []string, runs int, analyze func(coreFile string) (*CoreAnalysis, error)) error {
+	color := useColor(w)
+	passed, failed := 0, 0
+	for _, coreFile := range coreFiles {
+		first, err := analyze(coreFile)
+		if err != nil {
+			return err
+		}
+
+		stable := true
+		for run := 2; run <= runs; run++ {
+			analysis, err := analyze(coreFile)
+			if err != nil {
+				return err
+			}
+			if diff := analysisDiff(first, analysis); len(diff) > 0 {
+				stable = false
+				fmt.Fprintf(w, "%s: run %d differs from run 1 in: %s\n", coreFile, run, strings.Join(diff, ", "))
+			}
+		}
+
+		if stable {
+			passed++
+			fmt.Fprintf(w, "%s %s (%d identical runs)\n", colorSeverity("PASS", color), coreFile, runs)
+		} else {
+			failed++
+			fmt.Fprintf(w, "%s %s\n", colorSeverity("FAIL", color), coreFile)
+		}
+	}
+
+	fmt.Fprintf(w, "\nRepeat summary: %d passed, %d failed (%d runs per core)\n", passed, failed, runs)
+	if failed > 0 {
+		return fmt.Errorf("%w: %d of %d core(s)", ErrNondeterministicAnalysis, failed, len(coreFiles))
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package coreinfo
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestRunRepeatCheck validates that identical runs pass and differing runs
+// are reported with the fields that changed.
+func TestRunRepeatCheck(t *testing.T) {
+	base := func() *CoreAnalysis {
+		return &CoreAnalysis{
+			CoreFile:      "core.1",
+			Signal:        "SIGSEGV (Segmentation fault)",
+			ThreadID:      "1",
+			CrashedThread: []StackFrame{{Index: 0, Function: "ExecProcNode"}},
+			GDBOutput:     "transcript",
+		}
+	}
+
+	t.Run("stable", func(t *testing.T) {
+		var buf bytes.Buffer
+		calls := 0
+		err := runRepeatCheck(&buf, []string{"core.1"}, 3, func(string) (*CoreAnalysis, error) {
+			calls++
+			analysis := base()
+			// The raw transcript may vary without failing the check
+			analysis.GDBOutput += strings.Repeat(" ", calls)
+			return analysis, nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if calls != 3 {
+			t.Errorf("expected 3 runs, got %d", calls)
+		}
+		if !strings.Contains(buf.String(), "PASS core.1 (3 identical runs)") {
+			t.Errorf("expected PASS line, got:\n%s", buf.String())
+		}
+	})
+
+	t.Run("nondeterministic", func(t *testing.T) {
+		var buf bytes.Buffer
+		calls := 0
+		err := runRepeatCheck(&buf, []string{"core.1"}, 2, func(string) (*CoreAnalysis, error) {
+			calls++
+			analysis := base()
+			if calls == 2 {
+				analysis.ThreadID = "2"
+				analysis.CrashedThread = nil
+			}
+			return analysis, nil
+		})
+		if !errors.Is(err, ErrNondeterministicAnalysis) {
+			t.Fatalf("expected ErrNondeterministicAnalysis, got: %v", err)
+		}
+		output := buf.String()
+		if !strings.Contains(output, "run 2 differs from run 1 in: ThreadID, CrashedThread") {
+			t.Errorf("expected differing fields, got:\n%s", output)
+		}
+		if !strings.Contains(output, "Repeat summary: 0 passed, 1 failed") {
+			t.Errorf("expected summary, got:\n%s", output)
+		}
+	})
+}
@@ -0,0 +1,122 @@
+package coreinfo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// ShowCmd re-renders a saved analysis without running gdb.
+var ShowCmd = &cobra.Command{
+	Use:   "show <file>",
+	Short: "Validate and re-render a saved analysis",
+	Long:  "Load an analysis saved as JSON (or YAML), validate it against the current analysis schema, and render it in the requested formats without running gdb again.",
+	Args:  cobra.ExactArgs(1),
+	RunE:  RunShow,
+}
+
+// loadAnalysis reads a saved analysis. Files ending in .yaml or .yml are
+// read as YAML, all others as JSON. Unknown fields and a missing core_file
+// or binary are reported as ErrSchemaMismatch, since they indicate a file
+// of another version or an edit gone wrong.
+func loadAnalysis(path string) (*CoreAnalysis, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read analysis: %w", err)
+	}
+
+	var analysis CoreAnalysis
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		if err := yaml.UnmarshalStrict(data, &analysis); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrSchemaMismatch, path, err)
+		}
+	default:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&analysis); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrSchemaMismatch, path, err)
+		}
+		if decoder.More() {
+			return nil, fmt.Errorf("%w: %s: data after the analysis document", ErrSchemaMismatch, path)
+		}
+	}
+
+	for _, field := range []struct{ name, value string }{
+		{"core_file", analysis.CoreFile},
+		{"binary", analysis.Binary},
+	} {
+		if field.value == "" {
+			return nil, fmt.Errorf("%w: %s: missing %s", ErrSchemaMismatch, path, field.name)
+		}
+	}
+
+	// The raw frame lines are not saved; rebuild them for the backtrace
+	for i := range analysis.CrashedThread {
+		analysis.CrashedThread[i].Raw = formatFrame(analysis.CrashedThread[i])
+	}
+	return &analysis, nil
+}
+
+// formatFrame formats a frame the way gdb prints it in a backtrace, e.g.
+// "#1  0x00000000004005a4 in ExecProcNode (node=0x0) at execProcnode.c:412".
+// Arguments are listed by name, since their original order is not saved.
+func formatFrame(frame StackFrame) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "#%-2d ", frame.Index)
+	if frame.Address != "" {
+		b.WriteString(frame.Address + " in ")
+	}
+	names := make([]string, 0, len(frame.Args))
+	for name := range frame.Args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	args := make([]string, 0, len(names))
+	for _, name := range names {
+		args = append(args, name+"="+frame.Args[name])
+	}
+	fmt.Fprintf(&b, "%s (%s)", frame.Function, strings.Join(args, ", "))
+	if frame.File != "" {
+		b.WriteString(" at " + frame.File)
+		if frame.Line > 0 {
+			b.WriteString(":" + strconv.Itoa(frame.Line))
+		}
+	}
+	if frame.Library != "" {
+		b.WriteString(" from " + frame.Library)
+	}
+	return b.String()
+}
+
+// runShow renders a saved analysis in each format to w.
+func runShow(w io.Writer, path string, formats []string) error {
+	analysis, err := loadAnalysis(path)
+	if err != nil {
+		return err
+	}
+	return writeAnalysis(w, analysis, formats, "")
+}
+
+// RunShow contains the logic for the coreinfo show command.
+func RunShow(cmd *cobra.Command, args []string) error {
+	formats, err := parseFormats(formatFromFlags(cmd))
+	if err != nil {
+		return err
+	}
+	return runShow(os.Stdout, args[0], formats)
+}
+
+func init() {
+	ShowCmd.Flags().StringP("format", "", formatText, "Output format: text, markdown or json; comma-separate several formats, e.g. text,json")
+	CoreinfoCmd.AddCommand(ShowCmd)
+}
@@ -0,0 +1,89 @@
+package coreinfo
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestRunShow validates that a saved JSON analysis renders like the original.
+func TestRunShow(t *testing.T) {
+	analysis, err := parseCoreAnalysis(sampleGDBOutput, nil, "/var/crash/core.4242")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	document, err := renderJSON(analysis)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "core_analysis_core.4242.json")
+	if err := os.WriteFile(path, []byte(document), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout bytes.Buffer
+	if err := runShow(&stdout, path, []string{formatText}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout.String(), textSummary(analysis)) || strings.Contains(stdout.String(), "Detailed GDB Output") {
+		t.Errorf("Expected the original summary without gdb output, got:\n%s", stdout.String())
+	}
+
+	stdout.Reset()
+	if err := runShow(&stdout, path, []string{formatMarkdown}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, frame := range []string{
+		"#0  0x00000000004005a4 in ExecProcNode (node=0x0) at execProcnode.c:412",
+		"#1  ExecutePlan (estate=0x2b3c4d0, planstate=0x0) at execMain.c:1632",
+	} {
+		if !strings.Contains(stdout.String(), frame) {
+			t.Errorf("Expected backtrace frame %q, got:\n%s", frame, stdout.String())
+		}
+	}
+}
+
+// TestLoadAnalysis validates schema checks of saved analyses.
+func TestLoadAnalysis(t *testing.T) {
+	tests := []struct {
+		name      string
+		file      string
+		content   string
+		expectErr bool
+	}{
+		{"json", "a.json", `{"core_file": "core.1", "binary": "postgres", "signal": "SIGSEGV"}`, false},
+		{"yaml", "a.yaml", "core_file: core.1\nbinary: postgres\ncrashed_thread:\n  - index: 0\n    function: ExecProcNode\n", false},
+		{"unknown json field", "a.json", `{"core_file": "core.1", "binary": "postgres", "crash_reason": "x"}`, true},
+		{"unknown yaml field", "a.yml", "core_file: core.1\nbinary: postgres\ncrash_reason: x\n", true},
+		{"wrong type", "a.json", `{"core_file": "core.1", "binary": "postgres", "crashed_thread": "ExecProcNode"}`, true},
+		{"missing binary", "a.json", `{"core_file": "core.1"}`, true},
+		{"trailing data", "a.json", `{"core_file": "core.1", "binary": "postgres"} {}`, true},
+		{"not an analysis", "a.json", "Core Dump Analysis Summary", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			analysis, err := loadAnalysis(path)
+			if tt.expectErr {
+				if !errors.Is(err, ErrSchemaMismatch) {
+					t.Errorf("Expected ErrSchemaMismatch, got %v", err)
+				}
+				return
+			}
+			if err != nil || analysis.CoreFile != "core.1" {
+				t.Errorf("Unexpected result %+v, %v", analysis, err)
+			}
+		})
+	}
+
+	if _, err := loadAnalysis(filepath.Join(t.TempDir(), "missing.json")); err == nil || errors.Is(err, ErrSchemaMismatch) {
+		t.Errorf("Expected a read error for a missing file, got %v", err)
+	}
+}
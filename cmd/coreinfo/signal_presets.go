@@ -0,0 +1,92 @@
+package coreinfo
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// defaultGDBPreset is the preset used for signals without a mapping.
+const defaultGDBPreset = "basic"
+
+// gdbPresets maps preset names to the embedded GDB command files.
+var gdbPresets = map[string]string{
+	"basic":    "gdb_commands_basic.txt",
+	"detailed": "gdb_commands_detailed.txt",
+}
+
+// gdbPreset is the preset selected with --gdb-preset. When empty, the
+// default preset is used.
+var gdbPreset string
+
+// gdbBySignal maps signal names (e.g. SIGSEGV) to a preset name or to the
+// path of a GDB command file, as set with --gdb-by-signal.
+var gdbBySignal map[string]string
+
+// normalizeSignalName upper-cases a signal name and adds the SIG prefix,
+// so that "segv", "SEGV" and "SIGSEGV" all refer to the same signal.
+func normalizeSignalName(name string) string {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if !strings.HasPrefix(name, "SIG") {
+		name = "SIG" + name
+	}
+	return name
+}
+
+// validateGDBBySignal normalizes the --gdb-by-signal keys and checks that
+// every value is either a known preset or an existing GDB command file.
+func validateGDBBySignal() error {
+	if len(gdbBySignal) == 0 {
+		return nil
+	}
+
+	normalized := make(map[string]string, len(gdbBySignal))
+	for signal, preset := range gdbBySignal {
+		if _, ok := gdbPresets[preset]; !ok {
+			if _, err := os.Stat(preset); err != nil {
+				return fmt.Errorf("invalid --gdb-by-signal preset %q for %s: must be one of %s or an existing GDB command file",
+					preset, signal, strings.Join(presetNames(), ", "))
+			}
+		}
+		normalized[normalizeSignalName(signal)] = preset
+	}
+	gdbBySignal = normalized
+	return nil
+}
+
+// validateGDBPreset checks that --gdb-preset names an embedded preset.
+func validateGDBPreset() error {
+	if gdbPreset == "" {
+		return nil
+	}
+	if _, ok := gdbPresets[gdbPreset]; !ok {
+		return fmt.Errorf("invalid --gdb-preset %q: must be one of %s", gdbPreset, strings.Join(presetNames(), ", "))
+	}
+	return nil
+}
+
+// presetNames returns the sorted names of the embedded presets.
+func presetNames() []string {
+	names := make([]string, 0, len(gdbPresets))
+	for name := range gdbPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// selectGDBPreset returns the preset or GDB command file mapped to signal,
+// falling back to --gdb-preset, or the default preset, for unmapped or
+// unknown signals.
+func selectGDBPreset(signal string) string {
+	if signal != "" {
+		if preset, ok := gdbBySignal[normalizeSignalName(signal)]; ok {
+			return preset
+		}
+	}
+	if gdbPreset != "" {
+		return gdbPreset
+	}
+	return defaultGDBPreset
+}
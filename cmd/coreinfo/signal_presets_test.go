@@ -0,0 +1,90 @@
+package coreinfo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestValidateGDBBySignal validates preset mapping validation and signal name normalization.
+func TestValidateGDBBySignal(t *testing.T) {
+	commandFile := filepath.Join(t.TempDir(), "abort.gdb")
+	if err := os.WriteFile(commandFile, []byte("bt\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		mapping   map[string]string
+		expectErr bool
+		expected  map[string]string
+	}{
+		{name: "no mapping", mapping: nil},
+		{
+			name:     "presets and file",
+			mapping:  map[string]string{"segv": "detailed", "SIGABRT": commandFile},
+			expected: map[string]string{"SIGSEGV": "detailed", "SIGABRT": commandFile},
+		},
+		{name: "unknown preset", mapping: map[string]string{"SIGSEGV": "nonexistent"}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gdbBySignal = tt.mapping
+			defer func() { gdbBySignal = nil }()
+
+			err := validateGDBBySignal()
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("expected error: %v, got: %v", tt.expectErr, err)
+			}
+			if tt.expected == nil {
+				return
+			}
+			for signal, preset := range tt.expected {
+				if gdbBySignal[signal] != preset {
+					t.Errorf("expected %s mapped to %s, got %q", signal, preset, gdbBySignal[signal])
+				}
+			}
+		})
+	}
+}
+
+// TestSelectGDBPreset validates preset selection and the fallback for unmapped signals.
+func TestSelectGDBPreset(t *testing.T) {
+	gdbBySignal = map[string]string{"SIGSEGV": "detailed", "SIGABRT": "/tmp/abort.gdb"}
+	defer func() { gdbBySignal = nil }()
+
+	tests := []struct {
+		signal   string
+		expected string
+	}{
+		{signal: "SIGSEGV", expected: "detailed"},
+		{signal: "sigabrt", expected: "/tmp/abort.gdb"},
+		{signal: "SIGBUS", expected: defaultGDBPreset},
+		{signal: "", expected: defaultGDBPreset},
+	}
+
+	for _, tt := range tests {
+		if got := selectGDBPreset(tt.signal); got != tt.expected {
+			t.Errorf("selectGDBPreset(%q) = %q, expected %q", tt.signal, got, tt.expected)
+		}
+	}
+
+	// --gdb-preset replaces the default for unmapped signals
+	gdbPreset = "detailed"
+	defer func() { gdbPreset = "" }()
+	if got := selectGDBPreset("SIGBUS"); got != "detailed" {
+		t.Errorf("expected --gdb-preset fallback, got %q", got)
+	}
+}
+
+// TestValidateGDBPreset validates that only embedded presets are accepted.
+func TestValidateGDBPreset(t *testing.T) {
+	defer func() { gdbPreset = "" }()
+	for preset, expectErr := range map[string]bool{"": false, "basic": false, "detailed": false, "abort": true} {
+		gdbPreset = preset
+		if err := validateGDBPreset(); (err != nil) != expectErr {
+			t.Errorf("preset %q: expected error: %v, got: %v", preset, expectErr, err)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package coreinfo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// signatureFrames is the number of crashed-thread frames in a signature.
+// Deeper frames mostly reflect the caller's query and would split
+// otherwise identical crashes into separate buckets.
+const signatureFrames = 10
+
+// printSignature enables --print-signature, which prints only the crash
+// signature of each core.
+var printSignature bool
+
+// crashSignature returns the normalized crash signature of an analysis:
+// the signal name followed by the function names of the top frames of the
+// crashed thread, e.g. "SIGSEGV:ExecProcNode>ExecutePlan>standard_ExecutorRun".
+// Addresses, arguments, files and line numbers are left out, so crashes of
+// the same code path in different builds share a signature.
+// Returns ErrNoSignature if the crashed thread's backtrace is unavailable.
+func crashSignature(analysis *CoreAnalysis) (string, error) {
+	if len(analysis.CrashedThread) == 0 {
+		return "", fmt.Errorf("%w: no backtrace for crashed thread in %s", ErrNoSignature, analysis.CoreFile)
+	}
+
+	signal := "UNKNOWN"
+	if fields := strings.Fields(analysis.Signal); len(fields) > 0 && strings.HasPrefix(fields[0], "SIG") {
+		signal = fields[0]
+	}
+
+	var functions []string
+	for _, frame := range analysis.CrashedThread {
+		if len(functions) == signatureFrames {
+			break
+		}
+		functions = append(functions, frame.Function)
+	}
+	return signal + ":" + strings.Join(functions, ">"), nil
+}
+
+// signatureHash returns a short, stable hash of a signature for use as a
+// dedupe key.
+func signatureHash(signature string) string {
+	sum := sha256.Sum256([]byte(signature))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// runPrintSignature analyzes each core and writes one tab-separated line
+// per core: the signature hash, the signature and the core file.
+func runPrintSignature(w io.Writer, coreFiles []string, analyze func(coreFile string) (*CoreAnalysis, error)) error {
+	for _, coreFile := range coreFiles {
+		analysis, err := analyze(coreFile)
+		if err != nil {
+			return err
+		}
+		signature, err := crashSignature(analysis)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", signatureHash(signature), signature, coreFile)
+	}
+	return nil
+}
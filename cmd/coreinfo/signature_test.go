@@ -0,0 +1,74 @@
+package coreinfo
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestCrashSignature validates signature normalization: frames that differ
+// only by address, arguments or line number share a signature.
+func TestCrashSignature(t *testing.T) {
+	frames := func(address string, line int) []StackFrame {
+		return []StackFrame{
+			{Index: 0, Address: address, Function: "ExecProcNode", File: "execProcnode.c", Line: line},
+			{Index: 1, Address: address, Function: "ExecutePlan", File: "execMain.c", Line: line + 10},
+		}
+	}
+
+	a := &CoreAnalysis{CoreFile: "core.1", Signal: "SIGSEGV (Segmentation fault)", CrashedThread: frames("0x0000000000400123", 412)}
+	b := &CoreAnalysis{CoreFile: "core.2", Signal: "SIGSEGV (Segmentation fault)", CrashedThread: frames("0x00000000004fffff", 418)}
+
+	sigA, err := crashSignature(a)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sigA != "SIGSEGV:ExecProcNode>ExecutePlan" {
+		t.Errorf("unexpected signature %q", sigA)
+	}
+	sigB, _ := crashSignature(b)
+	if sigA != sigB || signatureHash(sigA) != signatureHash(sigB) {
+		t.Errorf("expected frames differing by address and line to share a signature: %q vs %q", sigA, sigB)
+	}
+
+	b.Signal = "SIGABRT (Aborted)"
+	if sigB, _ = crashSignature(b); sigB == sigA {
+		t.Error("expected different signals to produce different signatures")
+	}
+
+	deep := &CoreAnalysis{Signal: "Unknown signal"}
+	for i := 0; i < signatureFrames+5; i++ {
+		deep.CrashedThread = append(deep.CrashedThread, StackFrame{Index: i, Function: "f"})
+	}
+	sig, _ := crashSignature(deep)
+	if !strings.HasPrefix(sig, "UNKNOWN:") || strings.Count(sig, "f") != signatureFrames {
+		t.Errorf("expected %d frames with unknown signal, got %q", signatureFrames, sig)
+	}
+
+	if _, err := crashSignature(&CoreAnalysis{CoreFile: "core.3"}); !errors.Is(err, ErrNoSignature) {
+		t.Errorf("expected ErrNoSignature without a backtrace, got: %v", err)
+	}
+}
+
+// TestRunPrintSignature validates the tab-separated signature output.
+func TestRunPrintSignature(t *testing.T) {
+	var buf bytes.Buffer
+	err := runPrintSignature(&buf, []string{"core.1"}, func(coreFile string) (*CoreAnalysis, error) {
+		return parseCoreAnalysis(sampleGDBOutput, nil, coreFile)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fields := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\t")
+	if len(fields) != 3 {
+		t.Fatalf("expected hash, signature and core file, got %q", buf.String())
+	}
+	if len(fields[0]) != 16 || fields[0] != signatureHash(fields[1]) {
+		t.Errorf("unexpected hash %q for signature %q", fields[0], fields[1])
+	}
+	if !strings.HasPrefix(fields[1], "SIGSEGV:") || fields[2] != "core.1" {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+}
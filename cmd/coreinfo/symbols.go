@@ -0,0 +1,84 @@
+package coreinfo
+
+import (
+	"debug/elf"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Symbol sources recorded in CoreAnalysis.SymbolSource.
+const (
+	symbolSourceInline     = "inline"
+	symbolSourceCompanion  = "companion"
+	symbolSourceDebuginfod = "debuginfod"
+	symbolSourceNone       = "none"
+)
+
+// debugFilePath is the separate debug file set with --debug-file.
+var debugFilePath string
+
+// debugFileRoot is the system directory holding separate debug files,
+// mirroring the paths of the binaries they belong to.
+var debugFileRoot = "/usr/lib/debug"
+
+// validateDebugFile checks that --debug-file points at an existing file.
+func validateDebugFile() error {
+	if debugFilePath == "" {
+		return nil
+	}
+	info, err := os.Stat(debugFilePath)
+	if err != nil {
+		return fmt.Errorf("debug file not found: %s", debugFilePath)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("debug file is a directory: %s", debugFilePath)
+	}
+	return nil
+}
+
+// findCompanionDebugFile returns the separate debug file for binary: the
+// --debug-file flag if set, otherwise <binary>.debug next to the binary or
+// under /usr/lib/debug. Returns "" if there is none.
+func findCompanionDebugFile(binary string) string {
+	if debugFilePath != "" {
+		return debugFilePath
+	}
+	candidates := []string{binary + ".debug"}
+	if abs, err := filepath.Abs(binary); err == nil {
+		candidates = append(candidates, filepath.Join(debugFileRoot, abs+".debug"))
+	}
+	for _, candidate := range candidates {
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// hasInlineDebugInfo reports whether binary carries its own DWARF debug info.
+func hasInlineDebugInfo(binary string) bool {
+	f, err := elf.Open(binary)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	return f.Section(".debug_info") != nil || f.Section(".zdebug_info") != nil
+}
+
+// resolveSymbols determines where gdb gets symbols for binary from and
+// returns the gdb arguments needed to use them, along with the symbol source.
+// A companion debug file takes precedence over inline debug info; debuginfod
+// is used when DEBUGINFOD_URLS is set and no local symbols are available.
+func resolveSymbols(binary string) ([]string, string) {
+	if debugFile := findCompanionDebugFile(binary); debugFile != "" {
+		return []string{"-iex", "set confirm off", "-ex", "symbol-file " + debugFile}, symbolSourceCompanion
+	}
+	if hasInlineDebugInfo(binary) {
+		return nil, symbolSourceInline
+	}
+	if os.Getenv("DEBUGINFOD_URLS") != "" {
+		return []string{"-iex", "set debuginfod enabled on"}, symbolSourceDebuginfod
+	}
+	return nil, symbolSourceNone
+}
@@ -0,0 +1,103 @@
+package coreinfo
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestResolveSymbols validates symbol source detection and the gdb arguments
+// for each source.
+func TestResolveSymbols(t *testing.T) {
+	originalRoot := debugFileRoot
+	defer func() { debugFileRoot, debugFilePath = originalRoot, "" }()
+
+	binDir := t.TempDir()
+	binary := filepath.Join(binDir, "postgres")
+	if err := os.WriteFile(binary, []byte("stripped"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	debugFileRoot = t.TempDir()
+	explicit := filepath.Join(t.TempDir(), "archived.debug")
+	if err := os.WriteFile(explicit, []byte("symbols"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		setup      func()
+		debugFile  string
+		debuginfod string
+		source     string
+		symbolFile string
+	}{
+		{name: "no symbols", source: symbolSourceNone},
+		{name: "debuginfod", debuginfod: "https://debuginfod.example.com", source: symbolSourceDebuginfod},
+		{name: "explicit debug file", debugFile: explicit, source: symbolSourceCompanion, symbolFile: explicit},
+		{
+			name: "system debug directory",
+			setup: func() {
+				path := filepath.Join(debugFileRoot, binary+".debug")
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte("symbols"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			},
+			source:     symbolSourceCompanion,
+			symbolFile: filepath.Join(debugFileRoot, binary+".debug"),
+		},
+		{
+			name: "companion next to binary",
+			setup: func() {
+				if err := os.WriteFile(binary+".debug", []byte("symbols"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			},
+			source:     symbolSourceCompanion,
+			symbolFile: binary + ".debug",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.setup != nil {
+				tt.setup()
+			}
+			debugFilePath = tt.debugFile
+			t.Setenv("DEBUGINFOD_URLS", tt.debuginfod)
+
+			args, source := resolveSymbols(binary)
+			if source != tt.source {
+				t.Errorf("expected source %q, got %q", tt.source, source)
+			}
+			joined := strings.Join(args, " ")
+			if tt.symbolFile != "" && !strings.Contains(joined, "symbol-file "+tt.symbolFile) {
+				t.Errorf("expected symbol-file %s in args, got %q", tt.symbolFile, args)
+			}
+			if tt.source == symbolSourceNone && len(args) != 0 {
+				t.Errorf("expected no args without symbols, got %q", args)
+			}
+		})
+	}
+}
+
+// TestValidateDebugFile validates the --debug-file existence check.
+func TestValidateDebugFile(t *testing.T) {
+	defer func() { debugFilePath = "" }()
+
+	debugFilePath = filepath.Join(t.TempDir(), "missing.debug")
+	if err := validateDebugFile(); err == nil {
+		t.Error("expected error for missing debug file")
+	}
+	debugFilePath = t.TempDir()
+	if err := validateDebugFile(); err == nil {
+		t.Error("expected error for directory")
+	}
+	debugFilePath = ""
+	if err := validateDebugFile(); err != nil {
+		t.Errorf("unexpected error without --debug-file: %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package coreinfo
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// lockWaitDepth is how many frames from the top of a backtrace are searched
+// for a lock wait. The wait itself sits a few frames below the function
+// blocking on the lock, under the futex and semaphore calls.
+const lockWaitDepth = 8
+
+// lockWaitRegex matches the functions a thread blocks in while waiting on a
+// Cloudberry lock, lightweight lock or spinlock, or on a pthread lock.
+var lockWaitRegex = regexp.MustCompile(`^(LWLockAcquire\w*|LWLockWaitForVar|LockAcquire\w*|WaitOnLock|ProcSleep|PGSemaphoreLock|s_lock|perform_spin_delay|__lll_lock_wait\w*|(__)?pthread_mutex_(timed)?lock|(__)?pthread_rwlock_(timed)?(rd|wr)lock)$`)
+
+// ThreadSummary counts the threads of a core by what they were doing when
+// the process crashed. Threads that are neither the crashed thread nor
+// waiting on a lock are counted as running or idle.
+type ThreadSummary struct {
+	Total   int `json:"total" yaml:"total"`
+	Crashed int `json:"crashed" yaml:"crashed"`
+	Waiting int `json:"waiting" yaml:"waiting"`
+	Idle    int `json:"idle" yaml:"idle"`
+}
+
+// String formats the summary for reports, e.g.
+// "42 threads: 1 crashed, 3 waiting on locks, 38 running or idle".
+func (s *ThreadSummary) String() string {
+	return fmt.Sprintf("%d threads: %d crashed, %d waiting on locks, %d running or idle", s.Total, s.Crashed, s.Waiting, s.Idle)
+}
+
+// summarizeThreads classifies the backtraces of all threads, as parsed by
+// parseBacktraces, against the crashed thread's ID. The unlabeled backtrace
+// gdb prints when loading the core is only counted as a thread when the
+// transcript has no per-thread backtraces.
+// Returns nil if the transcript has no backtraces.
+func summarizeThreads(threads map[string][]StackFrame, crashedID string) *ThreadSummary {
+	if len(threads) == 0 {
+		return nil
+	}
+	summary := &ThreadSummary{}
+	for id, frames := range threads {
+		if id == "" && len(threads) > 1 {
+			continue
+		}
+		summary.Total++
+		switch {
+		case id == crashedID:
+			summary.Crashed++
+		case waitingOnLock(frames):
+			summary.Waiting++
+		default:
+			summary.Idle++
+		}
+	}
+	return summary
+}
+
+// waitingOnLock reports whether a backtrace is blocked waiting on a lock.
+func waitingOnLock(frames []StackFrame) bool {
+	for i, frame := range frames {
+		if i == lockWaitDepth {
+			break
+		}
+		if lockWaitRegex.MatchString(frame.Function) {
+			return true
+		}
+	}
+	return false
+}
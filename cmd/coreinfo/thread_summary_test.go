@@ -0,0 +1,67 @@
+package coreinfo
+
+import (
+	"reflect"
+	"testing"
+)
+
+// lockWaitBacktrace is a backtrace of a backend blocked on a lightweight lock.
+const lockWaitBacktrace = `
+Thread 3 (Thread 0x7f2a19000700 (LWP 4244)):
+#0  0x00007f2a19e3a9cd in do_futex_wait () from /lib64/libpthread.so.0
+#1  0x00007f2a19e3aa1f in __new_sem_wait_slow () from /lib64/libpthread.so.0
+#2  0x0000000000a2b3c4 in PGSemaphoreLock (sema=0x7f2a10) at pg_sema.c:327
+#3  0x0000000000a3c4d5 in LWLockAcquire (lock=0x7f2a20, mode=LW_EXCLUSIVE) at lwlock.c:1338
+`
+
+// TestSummarizeThreads validates counting threads by state.
+func TestSummarizeThreads(t *testing.T) {
+	tests := []struct {
+		name      string
+		gdbOutput string
+		crashedID string
+		expected  *ThreadSummary
+	}{
+		{
+			name:      "crashed and idle",
+			gdbOutput: sampleGDBOutput,
+			crashedID: "1",
+			expected:  &ThreadSummary{Total: 2, Crashed: 1, Idle: 1},
+		},
+		{
+			name:      "waiting on lock",
+			gdbOutput: sampleGDBOutput + lockWaitBacktrace,
+			crashedID: "1",
+			expected:  &ThreadSummary{Total: 3, Crashed: 1, Waiting: 1, Idle: 1},
+		},
+		{
+			name:      "plain backtrace",
+			gdbOutput: "#0  0x00000000004005a4 in ExecProcNode (node=0x0) at execProcnode.c:412\n",
+			crashedID: "",
+			expected:  &ThreadSummary{Total: 1, Crashed: 1},
+		},
+		{
+			name:      "no backtraces",
+			gdbOutput: "No stack.\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			threads, _ := parseBacktraces(tt.gdbOutput)
+			got := summarizeThreads(threads, tt.crashedID)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("summarizeThreads() = %+v, expected %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestThreadSummaryString validates the report line of a thread summary.
+func TestThreadSummaryString(t *testing.T) {
+	summary := &ThreadSummary{Total: 42, Crashed: 1, Waiting: 3, Idle: 38}
+	expected := "42 threads: 1 crashed, 3 waiting on locks, 38 running or idle"
+	if got := summary.String(); got != expected {
+		t.Errorf("String() = %q, expected %q", got, expected)
+	}
+}
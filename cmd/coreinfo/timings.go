@@ -0,0 +1,73 @@
+package coreinfo
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// showTimings enables --timings, which reports the duration of each gdb
+// invocation and binary version check on stderr.
+var showTimings bool
+
+// stepTiming is the duration of one timed step.
+type stepTiming struct {
+	step     string
+	duration time.Duration
+}
+
+// stepTimer records the wall-clock duration of analysis steps in the order
+// they finish. It is safe for concurrent use. A nil *stepTimer records
+// nothing, so steps can be timed unconditionally.
+type stepTimer struct {
+	mu    sync.Mutex
+	steps []stepTiming
+}
+
+// timings records the steps of the current invocation; nil unless --timings.
+var timings *stepTimer
+
+// track starts timing step and returns a function that records its
+// duration, for use as 'defer timings.track("step")()'.
+func (t *stepTimer) track(step string) func() {
+	if t == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		elapsed := time.Since(start)
+		t.mu.Lock()
+		t.steps = append(t.steps, stepTiming{step: step, duration: elapsed})
+		t.mu.Unlock()
+	}
+}
+
+// print writes the recorded steps as an aligned table.
+func (t *stepTimer) print(w io.Writer) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STEP\tDURATION")
+	for _, s := range t.steps {
+		fmt.Fprintf(tw, "%s\t%s\n", s.step, s.duration.Round(time.Millisecond))
+	}
+	tw.Flush()
+}
+
+// startTimings enables step timing for one invocation when --timings is
+// set. The returned function prints the table to w and disables timing.
+func startTimings(w io.Writer) func() {
+	if !showTimings {
+		return func() {}
+	}
+	timings = &stepTimer{}
+	return func() {
+		timings.print(w)
+		timings = nil
+	}
+}
@@ -0,0 +1,52 @@
+package coreinfo
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestStartTimings validates that steps are only recorded with --timings
+// and are printed in the order they finished.
+func TestStartTimings(t *testing.T) {
+	tests := []struct {
+		name     string
+		enabled  bool
+		expected []string
+	}{
+		{name: "disabled"},
+		{name: "enabled", enabled: true, expected: []string{"STEP", "gdb load probe core.1", "gdb analysis core.1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			showTimings = tt.enabled
+			defer func() { showTimings = false }()
+
+			var out bytes.Buffer
+			done := startTimings(&out)
+			timings.track("gdb load probe core.1")()
+			timings.track("gdb analysis core.1")()
+			done()
+
+			if timings != nil {
+				t.Error("timings still enabled after the invocation")
+			}
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			if len(tt.expected) == 0 {
+				if out.Len() != 0 {
+					t.Errorf("expected no output, got %q", out.String())
+				}
+				return
+			}
+			if len(lines) != len(tt.expected) {
+				t.Fatalf("expected %d lines, got %q", len(tt.expected), out.String())
+			}
+			for i, prefix := range tt.expected {
+				if !strings.HasPrefix(lines[i], prefix) {
+					t.Errorf("line %d = %q, expected prefix %q", i, lines[i], prefix)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package coreinfo
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// unresolvedOnly is the --unresolved-only flag: probe each core's crashed
+// thread backtrace and report only the cores whose symbols do not resolve.
+var unresolvedOnly bool
+
+// SymbolProbe is the result of the quick symbol-resolution probe of a core.
+type SymbolProbe struct {
+	Binary       string
+	SymbolSource string
+	Frames       []StackFrame
+}
+
+// probeSymbols runs gdb in batch mode with only 'bt' to check whether the
+// crashed thread's frames resolve to functions and source lines. It uses
+// the same binary and symbols as the full analysis, and doubles as the load
+// probe, so unusable cores fail with ErrCoreLoadFailed. A core without a
+// stack is returned with no frames.
+func probeSymbols(coreFile string, fileInfo *FileInfo) (*SymbolProbe, error) {
+	postgresPath, mismatchArgs, _, err := gdbTarget(coreFile, fileInfo)
+	if err != nil {
+		return nil, err
+	}
+	symbolArgs, symbolSource := resolveSymbols(postgresPath)
+
+	args := append([]string{"-q", "-batch"}, mismatchArgs...)
+	args = append(args, symbolArgs...)
+	args = append(args, "-ex", `echo `+loadProbeMarker+`\n`, "-ex", "print $pc", "-ex", "bt", postgresPath, coreFile)
+	stop := timings.track("gdb symbol probe " + coreFile)
+	output, err := exec.Command("gdb", args...).CombinedOutput()
+	stop()
+	// With -batch, gdb exits non-zero when the last command fails, as 'bt'
+	// does on a core without a stack. Such a core has no frames to resolve.
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return nil, fmt.Errorf("failed to probe symbols of core %s: %v", coreFile, err)
+	}
+	if err := loadFailure(coreFile, string(output)); err != nil {
+		return nil, err
+	}
+
+	threads, _ := parseBacktraces(string(output))
+	return &SymbolProbe{Binary: postgresPath, SymbolSource: symbolSource, Frames: threads[""]}, nil
+}
+
+// unresolvedFrames counts the key frames of a backtrace, those in the
+// binary itself rather than in a shared library, and how many of them lack
+// symbols: gdb printed ?? for the function or no source file and line.
+// Arguments shown as <optimized out> are not counted, since optimized
+// builds print them even with full debug symbols.
+func unresolvedFrames(frames []StackFrame) (unresolved, key int) {
+	for _, frame := range frames {
+		if frame.Library != "" {
+			continue
+		}
+		key++
+		if frame.Function == "??" || frame.File == "" {
+			unresolved++
+		}
+	}
+	return unresolved, key
+}
+
+// runUnresolvedOnly probes each core and writes one tab-separated line per
+// core whose symbols do not resolve: the host that generated it, the core
+// file, the binary, the symbol source and how many key frames are
+// unresolved. A core is unresolved when any key frame lacks symbols, or
+// when its backtrace has no key frames at all. Cores gdb cannot load are
+// skipped and reported to stderr.
+// Returns an error wrapping ErrUnresolvedSymbols if any core is unresolved.
+func runUnresolvedOnly(w io.Writer, coreFiles []string, probe func(coreFile string) (*SymbolProbe, error)) error {
+	count := 0
+	for _, coreFile := range coreFiles {
+		result, err := probe(coreFile)
+		if errors.Is(err, ErrCoreLoadFailed) || errors.Is(err, ErrPermissionDenied) {
+			fmt.Fprintf(os.Stderr, "Skipping core: %v\n", err)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		unresolved, key := unresolvedFrames(result.Frames)
+		if key > 0 && unresolved == 0 {
+			continue
+		}
+		count++
+		detail := fmt.Sprintf("%d of %d frames unresolved", unresolved, key)
+		if key == 0 {
+			detail = "no frames in the binary"
+		}
+		fmt.Fprintln(w, strings.Join([]string{valueOrNA(coreHostname(coreFile)), coreFile, result.Binary, result.SymbolSource, detail}, "\t"))
+	}
+
+	if count > 0 {
+		return fmt.Errorf("%w: %d of %d cores", ErrUnresolvedSymbols, count, len(coreFiles))
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package coreinfo
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestUnresolvedFrames validates which backtrace frames count as key frames
+// and which of them lack symbols.
+func TestUnresolvedFrames(t *testing.T) {
+	tests := []struct {
+		name       string
+		backtrace  string
+		unresolved int
+		key        int
+	}{
+		{
+			name: "resolved",
+			backtrace: `#0  0x00007f2b3c8a2e0f in raise () from /lib64/libc.so.6
+#1  0x0000000000b5e7a9 in ExecProcNode (node=<optimized out>) at execProcnode.c:463
+#2  0x0000000000b5e7b0 in ExecutorRun (queryDesc=0x2a4e0f8) at execMain.c:312`,
+			key: 2,
+		},
+		{
+			name: "stripped binary",
+			backtrace: `#0  0x00007f2b3c8a2e0f in raise () from /lib64/libc.so.6
+#1  0x0000000000b5e7a9 in ExecProcNode ()
+#2  0x0000000000b5e7b0 in ?? ()`,
+			unresolved: 2,
+			key:        2,
+		},
+		{
+			name: "partially resolved",
+			backtrace: `#0  0x0000000000b5e7a9 in ExecProcNode (node=0x2a4e0f8) at execProcnode.c:463
+#1  0x0000000000b5e7b0 in ?? ()`,
+			unresolved: 1,
+			key:        2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			threads, _ := parseBacktraces(tt.backtrace)
+			unresolved, key := unresolvedFrames(threads[""])
+			if unresolved != tt.unresolved || key != tt.key {
+				t.Errorf("unresolvedFrames() = %d, %d, expected %d, %d", unresolved, key, tt.unresolved, tt.key)
+			}
+		})
+	}
+}
+
+// TestRunUnresolvedOnly validates that only cores whose symbols do not
+// resolve are printed, and that any such core fails.
+func TestRunUnresolvedOnly(t *testing.T) {
+	resolved := &SymbolProbe{Binary: "/usr/local/cloudberry/bin/postgres", SymbolSource: symbolSourceInline, Frames: []StackFrame{{Function: "ExecProcNode", File: "execProcnode.c", Line: 463}}}
+	stripped := &SymbolProbe{Binary: "/usr/local/cloudberry/bin/postgres", SymbolSource: symbolSourceNone, Frames: []StackFrame{{Function: "??"}}}
+	empty := &SymbolProbe{Binary: "/usr/local/cloudberry/bin/postgres", SymbolSource: symbolSourceNone}
+	probes := map[string]*SymbolProbe{"core.1": resolved, "core.2": stripped, "core.3": empty}
+	probe := func(coreFile string) (*SymbolProbe, error) { return probes[coreFile], nil }
+
+	tests := []struct {
+		name      string
+		coreFiles []string
+		output    []string
+	}{
+		{name: "all resolved", coreFiles: []string{"core.1"}},
+		{name: "stripped core", coreFiles: []string{"core.1", "core.2"}, output: []string{"N/A\tcore.2\t/usr/local/cloudberry/bin/postgres\tnone\t1 of 1 frames unresolved"}},
+		{name: "no backtrace", coreFiles: []string{"core.3"}, output: []string{"N/A\tcore.3\t/usr/local/cloudberry/bin/postgres\tnone\tno frames in the binary"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := runUnresolvedOnly(&buf, tt.coreFiles, probe)
+			if (err != nil) != (len(tt.output) > 0) {
+				t.Fatalf("runUnresolvedOnly() error = %v", err)
+			}
+			if err != nil && !errors.Is(err, ErrUnresolvedSymbols) {
+				t.Errorf("expected ErrUnresolvedSymbols, got %v", err)
+			}
+			if got := strings.TrimSuffix(buf.String(), "\n"); got != strings.Join(tt.output, "\n") {
+				t.Errorf("unexpected output %q", buf.String())
+			}
+		})
+	}
+
+	failing := func(string) (*SymbolProbe, error) { return nil, errors.New("gdb not found") }
+	if err := runUnresolvedOnly(&bytes.Buffer{}, []string{"core.1"}, failing); err == nil || err.Error() != "gdb not found" {
+		t.Errorf("expected probe errors to be returned, got %v", err)
+	}
+
+	unloadable := func(string) (*SymbolProbe, error) { return nil, ErrCoreLoadFailed }
+	if err := runUnresolvedOnly(&bytes.Buffer{}, []string{"core.1"}, unloadable); err != nil {
+		t.Errorf("expected cores gdb cannot load to be skipped, got %v", err)
+	}
+}
+
+// TestProbeSymbols validates that probeSymbols classifies the transcript
+// before gdb's exit status, which is non-zero whenever 'bt' fails.
+func TestProbeSymbols(t *testing.T) {
+	dir := t.TempDir()
+	coreFile := filepath.Join(dir, "core.1")
+	binary := filepath.Join(dir, "postgres")
+	for _, path := range []string{coreFile, binary} {
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer func() { binaryPath = "" }()
+	binaryPath = binary
+
+	loaded := "Core was generated by `postgres: 7000, gpadmin postgres [local] con12 cmd5 SELECT'.\n" +
+		"Program terminated with signal SIGSEGV, Segmentation fault.\n" + loadProbeMarker + "\n"
+	tests := []struct {
+		name       string
+		output     string
+		status     int
+		frames     int
+		loadFailed bool
+	}{
+		{name: "backtrace", output: loaded + "$1 = (void (*)()) 0x4005a4 <ExecProcNode+20>\n#0  0x00000000004005a4 in ExecProcNode () at execProcnode.c:463\n", frames: 1},
+		{name: "no stack", output: loaded + "$1 = (void (*)()) 0x0\nNo stack.\n", status: 1},
+		{name: "no registers", output: loaded + "No registers.\n", status: 1, loadFailed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeGDB(t, tt.output, tt.status)
+			result, err := probeSymbols(coreFile, nil)
+			if errors.Is(err, ErrCoreLoadFailed) != tt.loadFailed {
+				t.Fatalf("expected ErrCoreLoadFailed %v, got %v", tt.loadFailed, err)
+			}
+			if tt.loadFailed {
+				return
+			}
+			if err != nil {
+				t.Fatalf("probeSymbols() error = %v", err)
+			}
+			if len(result.Frames) != tt.frames {
+				t.Errorf("expected %d frames, got %d", tt.frames, len(result.Frames))
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package coreinfo
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// versionMarker prefixes the output of the version probe so the probe's
+// result can be found in the gdb transcript.
+const versionMarker = "cbtoolbox-detected-version:"
+
+var detectedVersionRegex = regexp.MustCompile(regexp.QuoteMeta(versionMarker) + `\s*\$\d+ = (?:0x[0-9a-fA-F]+ )?"([^"]*)"`)
+
+// versionProbeArgs returns gdb arguments that print the Apache Cloudberry
+// version of the crashed process. The gp_server_version_string GUC variable
+// holds the build's GP_VERSION and lives in the core's memory, so it reflects
+// the binary that dumped core rather than the binary gdb was given.
+func versionProbeArgs() []string {
+	// gdb's echo drops an unescaped trailing space
+	return []string{"-ex", "echo " + versionMarker + `\ `, "-ex", "print gp_server_version_string"}
+}
+
+// extractDetectedVersion returns the version printed by the version probe,
+// or "" if the symbol was unavailable (e.g. no debug symbols).
+func extractDetectedVersion(gdbOutput string) string {
+	if match := detectedVersionRegex.FindStringSubmatch(gdbOutput); len(match) > 1 {
+		return strings.TrimSpace(match[1])
+	}
+	return ""
+}
+
+// getBinaryVersion returns the --gp-version output of the binary used for
+// analysis, or "" if it cannot be executed (e.g. an archived binary for a
+// different platform).
+func getBinaryVersion(binary string) string {
+	output, err := exec.Command(binary, "--gp-version").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// versionsMatch reports whether the version detected in the core is the
+// version of the binary used for analysis. Unknown versions are treated as
+// matching since nothing can be concluded from them.
+func versionsMatch(detected, binaryVersion string) bool {
+	if detected == "" || binaryVersion == "" {
+		return true
+	}
+	return strings.Contains(binaryVersion, detected)
+}
+
+// versionMismatchWarning returns a warning when the core was generated by
+// a different build than the binary used to analyze it, or "" otherwise.
+func versionMismatchWarning(analysis *CoreAnalysis, binary string) string {
+	if versionsMatch(analysis.DetectedVersion, analysis.BinaryVersion) {
+		return ""
+	}
+	return fmt.Sprintf("core %s was generated by version %q but is analyzed with %s (%s); use --binary to select the matching build",
+		analysis.CoreFile, analysis.DetectedVersion, binary, analysis.BinaryVersion)
+}
@@ -0,0 +1,97 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// env.go
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// envFlagBindings maps environment variables to the flags they provide
+// defaults for. A binding applies to every command that defines the flag,
+// so containers and systemd units can configure cbtoolbox without flags.
+// value converts the variable to the flag value for a command, returning
+// "" to leave the flag alone; bindings without one use the variable as is.
+var envFlagBindings = []struct {
+	env, flag string
+	value     func(cmd *cobra.Command, value string) (string, error)
+}{
+	{"CBTOOLBOX_FORMAT", "format", envFormat},
+	{"CBTOOLBOX_OUTPUT_DIR", "output-dir", nil},
+	{"CBTOOLBOX_FILE", "file-path", nil},
+	{"CBTOOLBOX_LOG_LEVEL", "verbose", envLogLevel},
+}
+
+// errInvalidLogLevel reports an unknown CBTOOLBOX_LOG_LEVEL.
+var errInvalidLogLevel = errors.New("supported log levels: debug, info, warn, error")
+
+// envFormat applies CBTOOLBOX_FORMAT only to commands that support the
+// format, since the formats differ between commands: a yaml default meant
+// for sysinfo leaves coreinfo at its own default instead of failing it.
+func envFormat(cmd *cobra.Command, value string) (string, error) {
+	if spec, ok := commandFormats[cmd]; ok {
+		if _, unsupported := spec.normalize(value); unsupported != "" {
+			return "", nil
+		}
+	}
+	return value, nil
+}
+
+// envLogLevel maps CBTOOLBOX_LOG_LEVEL to --verbose: debug enables it, and
+// the quieter levels leave it at its default.
+func envLogLevel(_ *cobra.Command, value string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "debug":
+		return "true", nil
+	case "info", "warn", "error":
+		return "", nil
+	default:
+		return "", errInvalidLogLevel
+	}
+}
+
+// applyEnvDefaults sets flags of cmd that were not given on the command
+// line from their bound environment variables. Explicit flags always win
+// over the environment, which wins over the flag defaults.
+func applyEnvDefaults(cmd *cobra.Command) error {
+	for _, binding := range envFlagBindings {
+		value, ok := os.LookupEnv(binding.env)
+		if !ok || value == "" {
+			continue
+		}
+		flag := cmd.Flags().Lookup(binding.flag)
+		if flag == nil || flag.Changed {
+			continue
+		}
+		if binding.value != nil {
+			converted, err := binding.value(cmd, value)
+			if err != nil {
+				return fmt.Errorf("invalid %s=%q: %w", binding.env, value, err)
+			}
+			if converted == "" {
+				continue
+			}
+			value = converted
+		}
+		if err := cmd.Flags().Set(binding.flag, value); err != nil {
+			return fmt.Errorf("invalid %s=%q: %w", binding.env, value, err)
+		}
+	}
+	return nil
+}
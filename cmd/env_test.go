@@ -0,0 +1,162 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// env_test.go
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// TestApplyEnvDefaults validates env-driven flag defaults and that explicit flags win.
+func TestApplyEnvDefaults(t *testing.T) {
+	tests := []struct {
+		name      string
+		env       map[string]string
+		args      []string
+		format    string
+		outputDir string
+	}{
+		{name: "defaults", format: "text"},
+		{
+			name:      "environment",
+			env:       map[string]string{"CBTOOLBOX_FORMAT": "json", "CBTOOLBOX_OUTPUT_DIR": "/tmp/out"},
+			format:    "json",
+			outputDir: "/tmp/out",
+		},
+		{
+			name:      "flag overrides environment",
+			env:       map[string]string{"CBTOOLBOX_FORMAT": "json", "CBTOOLBOX_OUTPUT_DIR": "/tmp/out"},
+			args:      []string{"--format", "markdown"},
+			format:    "markdown",
+			outputDir: "/tmp/out",
+		},
+		{
+			name:   "empty variable ignored",
+			env:    map[string]string{"CBTOOLBOX_FORMAT": ""},
+			format: "text",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("CBTOOLBOX_FORMAT", "")
+			t.Setenv("CBTOOLBOX_OUTPUT_DIR", "")
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			testCmd := &cobra.Command{Use: "test"}
+			testCmd.Flags().String("format", "text", "")
+			testCmd.Flags().String("output-dir", "", "")
+			if err := testCmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("Failed to parse flags: %v", err)
+			}
+
+			if err := applyEnvDefaults(testCmd); err != nil {
+				t.Fatalf("applyEnvDefaults() error = %v", err)
+			}
+			if format, _ := testCmd.Flags().GetString("format"); format != tt.format {
+				t.Errorf("format = %q, expected %q", format, tt.format)
+			}
+			if outputDir, _ := testCmd.Flags().GetString("output-dir"); outputDir != tt.outputDir {
+				t.Errorf("output-dir = %q, expected %q", outputDir, tt.outputDir)
+			}
+		})
+	}
+}
+
+// TestApplyEnvDefaultsUnboundFlags validates that variables for flags a command lacks are ignored.
+func TestApplyEnvDefaultsUnboundFlags(t *testing.T) {
+	t.Setenv("CBTOOLBOX_FORMAT", "")
+	t.Setenv("CBTOOLBOX_OUTPUT_DIR", "/tmp/out")
+
+	testCmd := &cobra.Command{Use: "test"}
+	testCmd.Flags().String("format", "yaml", "")
+	if err := applyEnvDefaults(testCmd); err != nil {
+		t.Errorf("applyEnvDefaults() error = %v", err)
+	}
+	if format, _ := testCmd.Flags().GetString("format"); format != "yaml" {
+		t.Errorf("format = %q, expected the default yaml", format)
+	}
+}
+
+// TestApplyEnvDefaultsUnsupportedFormat validates that a CBTOOLBOX_FORMAT a
+// command does not support leaves the command at its default format.
+func TestApplyEnvDefaultsUnsupportedFormat(t *testing.T) {
+	testCmd := &cobra.Command{Use: "test"}
+	testCmd.Flags().String("format", "text", "")
+	commandFormats[testCmd] = formatSpec{formats: []string{"text", "json"}, multiple: true, err: errors.New("invalid format")}
+	defer delete(commandFormats, testCmd)
+
+	t.Setenv("CBTOOLBOX_FORMAT", "yaml")
+	if err := applyEnvDefaults(testCmd); err != nil {
+		t.Fatalf("applyEnvDefaults() error = %v", err)
+	}
+	if format, _ := testCmd.Flags().GetString("format"); format != "text" {
+		t.Errorf("format = %q, expected the default text", format)
+	}
+
+	t.Setenv("CBTOOLBOX_FORMAT", "JSON")
+	if err := applyEnvDefaults(testCmd); err != nil {
+		t.Fatalf("applyEnvDefaults() error = %v", err)
+	}
+	if format, _ := testCmd.Flags().GetString("format"); format != "JSON" {
+		t.Errorf("format = %q, expected JSON to be applied and left to normalizeFormat", format)
+	}
+}
+
+// TestApplyEnvDefaultsLogLevel validates the mapping of CBTOOLBOX_LOG_LEVEL to --verbose.
+func TestApplyEnvDefaultsLogLevel(t *testing.T) {
+	tests := []struct {
+		level   string
+		args    []string
+		verbose bool
+		wantErr bool
+	}{
+		{level: "debug", verbose: true},
+		{level: "DEBUG", verbose: true},
+		{level: "info"},
+		{level: "error"},
+		{level: "info", args: []string{"--verbose"}, verbose: true},
+		{level: "trace", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			t.Setenv("CBTOOLBOX_LOG_LEVEL", tt.level)
+
+			testCmd := &cobra.Command{Use: "test"}
+			testCmd.Flags().Bool("verbose", false, "")
+			if err := testCmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("Failed to parse flags: %v", err)
+			}
+
+			err := applyEnvDefaults(testCmd)
+			if tt.wantErr {
+				if !errors.Is(err, errInvalidLogLevel) {
+					t.Errorf("expected errInvalidLogLevel, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyEnvDefaults() error = %v", err)
+			}
+			if verbose, _ := testCmd.Flags().GetBool("verbose"); verbose != tt.verbose {
+				t.Errorf("verbose = %v, expected %v", verbose, tt.verbose)
+			}
+		})
+	}
+}
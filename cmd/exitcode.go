@@ -0,0 +1,45 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+// ExitError wraps an error with the process exit code cbtoolbox should
+// terminate with. main looks for an ExitError in the error chain, so
+// subcommands can carry distinct exit codes to the top.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+// WithExitCode wraps err so that cbtoolbox exits with code.
+// Returns nil if err is nil.
+func WithExitCode(err error, code int) error {
+	if err == nil {
+		return nil
+	}
+	return &ExitError{Code: code, Err: err}
+}
+
+// Error returns the message of the wrapped error.
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error, for use with errors.Is and errors.As.
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the exit code carried by the error.
+func (e *ExitError) ExitCode() int {
+	return e.Code
+}
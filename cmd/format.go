@@ -0,0 +1,82 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// format.go
+
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// formatSpec describes the --format values a command accepts.
+type formatSpec struct {
+	// formats lists the supported formats
+	formats []string
+
+	// multiple accepts a comma-separated list of formats
+	multiple bool
+
+	// err is the command's sentinel error for an unsupported format
+	err error
+}
+
+// commandFormats maps each command with a --format flag to the formats it
+// supports. Commands register here as they are added to rootCmd.
+var commandFormats = map[*cobra.Command]formatSpec{}
+
+// normalizeFormat validates the --format value of cmd against its
+// registered formats, so an invalid format fails before the command runs
+// with the same message for every command. Values are lowercased and
+// trimmed in place; the flag's default is left to each command.
+// Commands without a --format flag or registration are not checked.
+func normalizeFormat(cmd *cobra.Command) error {
+	spec, ok := commandFormats[cmd]
+	flag := cmd.Flags().Lookup("format")
+	if !ok || flag == nil {
+		return nil
+	}
+
+	values, unsupported := spec.normalize(flag.Value.String())
+	if unsupported != "" {
+		return fmt.Errorf("%w: %q for %s (supported formats: %s)", spec.err, unsupported, cmd.CommandPath(), strings.Join(spec.formats, ", "))
+	}
+
+	if normalized := strings.Join(values, ","); normalized != flag.Value.String() {
+		// Setting the value directly keeps the flag's Changed state
+		if err := flag.Value.Set(normalized); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalize lowercases and trims each format of a --format value and
+// returns them, along with the first unsupported format ("" if all are
+// supported).
+func (spec formatSpec) normalize(value string) ([]string, string) {
+	values := []string{value}
+	if spec.multiple {
+		values = strings.Split(value, ",")
+	}
+	for i, value := range values {
+		values[i] = strings.ToLower(strings.TrimSpace(value))
+		if !slices.Contains(spec.formats, values[i]) {
+			return nil, values[i]
+		}
+	}
+	return values, ""
+}
@@ -0,0 +1,103 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// format_test.go
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// TestNormalizeFormat validates shared --format validation and normalization.
+func TestNormalizeFormat(t *testing.T) {
+	errTestFormat := errors.New("invalid format")
+
+	tests := []struct {
+		name      string
+		multiple  bool
+		args      []string
+		expected  string
+		expectErr bool
+	}{
+		{name: "default", expected: "text"},
+		{name: "valid", args: []string{"--format", "json"}, expected: "json"},
+		{name: "normalized case and spaces", args: []string{"--format", " JSON "}, expected: "json"},
+		{name: "invalid", args: []string{"--format", "xml"}, expectErr: true},
+		{name: "list on single-format command", args: []string{"--format", "text,json"}, expectErr: true},
+		{name: "list", multiple: true, args: []string{"--format", "Text, json"}, expected: "text,json"},
+		{name: "invalid list entry", multiple: true, args: []string{"--format", "text,xml"}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testCmd := &cobra.Command{Use: "test"}
+			testCmd.Flags().String("format", "text", "")
+			commandFormats[testCmd] = formatSpec{formats: []string{"text", "json"}, multiple: tt.multiple, err: errTestFormat}
+			defer delete(commandFormats, testCmd)
+
+			if err := testCmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("Failed to parse flags: %v", err)
+			}
+
+			err := normalizeFormat(testCmd)
+			if tt.expectErr {
+				if !errors.Is(err, errTestFormat) {
+					t.Errorf("expected the command's format error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("normalizeFormat() error = %v", err)
+			}
+			if format, _ := testCmd.Flags().GetString("format"); format != tt.expected {
+				t.Errorf("format = %q, expected %q", format, tt.expected)
+			}
+		})
+	}
+}
+
+// TestNormalizeFormatUnregistered validates that commands without a
+// registration or --format flag are not checked.
+func TestNormalizeFormatUnregistered(t *testing.T) {
+	testCmd := &cobra.Command{Use: "test"}
+	testCmd.Flags().String("format", "anything", "")
+	if err := normalizeFormat(testCmd); err != nil {
+		t.Errorf("unregistered command: %v", err)
+	}
+
+	bare := &cobra.Command{Use: "bare"}
+	commandFormats[bare] = formatSpec{formats: []string{"text"}}
+	defer delete(commandFormats, bare)
+	if err := normalizeFormat(bare); err != nil {
+		t.Errorf("command without --format: %v", err)
+	}
+}
+
+// TestFormatCommandsRegistered validates that every command with a --format
+// flag is registered, so its value is validated and lowercased.
+func TestFormatCommandsRegistered(t *testing.T) {
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		if cmd.Flags().Lookup("format") != nil {
+			if _, ok := commandFormats[cmd]; !ok {
+				t.Errorf("%s has a --format flag but is not in commandFormats", cmd.CommandPath())
+			}
+		}
+		for _, sub := range cmd.Commands() {
+			walk(sub)
+		}
+	}
+	walk(rootCmd)
+}
@@ -0,0 +1,147 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// partial.go
+
+// Package partial marshals structs field by field when marshaling them
+// whole fails, so a diagnostic command still prints the fields it can
+// instead of nothing.
+package partial
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"gopkg.in/yaml.v2"
+)
+
+// errorsKey is the document key listing the fields that were left out.
+const errorsKey = "marshal_errors"
+
+// fields returns the exported fields of the struct v points to or holds,
+// each wrapped in a single-field struct with the same tags, so marshaling
+// it honors the field's name and omitempty option.
+// Returns an error if v is not a struct.
+func fields(v any) ([]reflect.StructField, []any, error) {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Pointer && !value.IsNil() {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("cannot marshal %T field by field", v)
+	}
+
+	var structFields []reflect.StructField
+	var wrapped []any
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		field.Anonymous = false
+		field.Index, field.Offset = nil, 0
+		one := reflect.New(reflect.StructOf([]reflect.StructField{field})).Elem()
+		one.Field(0).Set(value.Field(i))
+		structFields = append(structFields, field)
+		wrapped = append(wrapped, one.Interface())
+	}
+	return structFields, wrapped, nil
+}
+
+// JSON marshals v as indented JSON like json.MarshalIndent. If that
+// fails, the fields of struct v are marshaled one by one: the document
+// holds those that succeed, and a marshal_errors list names the others.
+// Returns the document and the failures; err is only set when v cannot be
+// marshaled at all.
+func JSON(v any, indent string) (data []byte, failures []string, err error) {
+	data, err = json.MarshalIndent(v, "", indent)
+	if err == nil {
+		return data, nil, nil
+	}
+	structFields, wrapped, fieldsErr := fields(v)
+	if fieldsErr != nil {
+		return nil, nil, err
+	}
+
+	var compact bytes.Buffer
+	compact.WriteString("{")
+	for i, one := range wrapped {
+		fieldData, fieldErr := json.Marshal(one)
+		if fieldErr != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", structFields[i].Name, fieldErr))
+			continue
+		}
+		// Strip the braces of the single-field object; omitted fields leave nothing
+		if member := fieldData[1 : len(fieldData)-1]; len(member) > 0 {
+			if compact.Len() > 1 {
+				compact.WriteString(",")
+			}
+			compact.Write(member)
+		}
+	}
+	if compact.Len() > 1 {
+		compact.WriteString(",")
+	}
+	errorsData, _ := json.Marshal(failures)
+	fmt.Fprintf(&compact, "%q:%s}", errorsKey, errorsData)
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, compact.Bytes(), "", indent); err != nil {
+		return nil, nil, err
+	}
+	return indented.Bytes(), failures, nil
+}
+
+// marshalYAML is yaml.Marshal, returning an error where yaml.v2 panics on
+// types it cannot marshal, such as functions and channels.
+func marshalYAML(v any) (data []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return yaml.Marshal(v)
+}
+
+// YAML marshals v like yaml.Marshal, falling back to marshaling the
+// fields of struct v one by one like JSON.
+func YAML(v any) (data []byte, failures []string, err error) {
+	data, err = marshalYAML(v)
+	if err == nil {
+		return data, nil, nil
+	}
+	structFields, wrapped, fieldsErr := fields(v)
+	if fieldsErr != nil {
+		return nil, nil, err
+	}
+
+	var document bytes.Buffer
+	for i, one := range wrapped {
+		fieldData, fieldErr := marshalYAML(one)
+		if fieldErr != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", structFields[i].Name, fieldErr))
+			continue
+		}
+		// Omitted fields marshal as an empty mapping
+		if string(fieldData) != "{}\n" {
+			document.Write(fieldData)
+		}
+	}
+	errorsData, err := yaml.Marshal(map[string][]string{errorsKey: failures})
+	if err != nil {
+		return nil, nil, err
+	}
+	document.Write(errorsData)
+	return document.Bytes(), failures, nil
+}
@@ -0,0 +1,104 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// partial_test.go
+package partial
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+// report is a document with a field that cannot be marshaled.
+type report struct {
+	Name     string            `json:"name" yaml:"name"`
+	Callback func()            `json:"callback" yaml:"callback"`
+	Ratio    float64           `json:"ratio" yaml:"ratio"`
+	Labels   map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Count    int               `json:"count" yaml:"count"`
+	internal string
+}
+
+// TestJSON validates that the marshalable fields of a document are kept
+// when one field cannot be marshaled.
+func TestJSON(t *testing.T) {
+	data, failures, err := JSON(report{Name: "cdw", Callback: func() {}, Ratio: math.NaN(), Count: 3}, "  ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(failures) != 2 || !strings.HasPrefix(failures[0], "Callback: ") || !strings.HasPrefix(failures[1], "Ratio: ") {
+		t.Errorf("unexpected failures %q", failures)
+	}
+
+	var document map[string]any
+	if err := json.Unmarshal(data, &document); err != nil {
+		t.Fatalf("expected valid JSON, got %s: %v", data, err)
+	}
+	if document["name"] != "cdw" || document["count"] != float64(3) {
+		t.Errorf("expected the marshalable fields, got %s", data)
+	}
+	if _, ok := document["labels"]; ok {
+		t.Errorf("expected omitempty to be honored, got %s", data)
+	}
+	if errors, ok := document[errorsKey].([]any); !ok || len(errors) != 2 {
+		t.Errorf("expected %s to list the failures, got %s", errorsKey, data)
+	}
+	if !strings.Contains(string(data), "\n  \"name\": \"cdw\"") {
+		t.Errorf("expected indented JSON, got %s", data)
+	}
+}
+
+// TestYAML validates the YAML fallback.
+func TestYAML(t *testing.T) {
+	data, failures, err := YAML(&report{Name: "cdw", Callback: func() {}, Count: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(failures) != 1 || !strings.HasPrefix(failures[0], "Callback: ") {
+		t.Errorf("unexpected failures %q", failures)
+	}
+
+	var document map[string]any
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		t.Fatalf("expected valid YAML, got %s: %v", data, err)
+	}
+	if _, ok := document["ratio"]; !ok || document["name"] != "cdw" || document["count"] != 3 {
+		t.Errorf("expected the marshalable fields, got %s", data)
+	}
+}
+
+// TestMarshalable validates that marshalable documents are unchanged and
+// that non-struct values fail as before.
+func TestMarshalable(t *testing.T) {
+	value := struct {
+		Name   string            `json:"name" yaml:"name"`
+		Labels map[string]string `json:"labels" yaml:"labels"`
+	}{Name: "cdw", Labels: map[string]string{"role": "coordinator"}}
+	data, failures, err := JSON(value, "  ")
+	expected, _ := json.MarshalIndent(value, "", "  ")
+	if err != nil || failures != nil || string(data) != string(expected) {
+		t.Errorf("JSON() = %s, %q, %v; expected %s", data, failures, err, expected)
+	}
+	data, failures, err = YAML(value)
+	expected, _ = yaml.Marshal(value)
+	if err != nil || failures != nil || string(data) != string(expected) {
+		t.Errorf("YAML() = %s, %q, %v; expected %s", data, failures, err, expected)
+	}
+
+	if _, _, err := JSON(map[string]any{"callback": func() {}}, "  "); err == nil {
+		t.Error("expected an error for a non-struct value")
+	}
+}
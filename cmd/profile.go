@@ -0,0 +1,85 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// profile.go
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+)
+
+var (
+	// cpuProfilePath and memProfilePath are set by the hidden
+	// --cpuprofile and --memprofile flags
+	cpuProfilePath string
+	memProfilePath string
+
+	// cpuProfileFile is the open CPU profile while profiling is active
+	cpuProfileFile *os.File
+)
+
+// startProfiling begins CPU profiling when --cpuprofile is set.
+// The profile is written by stopProfiling.
+func startProfiling() error {
+	if cpuProfilePath == "" || cpuProfileFile != nil {
+		return nil
+	}
+
+	f, err := os.Create(cpuProfilePath)
+	if err != nil {
+		return fmt.Errorf("cpuprofile: failed to create file: %w", err)
+	}
+	if err := pprof.StartCPUProfile(f); err != nil {
+		f.Close()
+		return fmt.Errorf("cpuprofile: failed to start: %w", err)
+	}
+	cpuProfileFile = f
+	return nil
+}
+
+// stopProfiling flushes the CPU profile started by startProfiling and
+// writes a heap profile when --memprofile is set. It is called after the
+// command returns, including when the command fails.
+func stopProfiling() error {
+	var firstErr error
+
+	if cpuProfileFile != nil {
+		pprof.StopCPUProfile()
+		if err := cpuProfileFile.Close(); err != nil {
+			firstErr = fmt.Errorf("cpuprofile: failed to close file: %w", err)
+		}
+		cpuProfileFile = nil
+	}
+
+	if memProfilePath != "" {
+		f, err := os.Create(memProfilePath)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("memprofile: failed to create file: %w", err)
+			}
+			return firstErr
+		}
+		defer f.Close()
+
+		// Collect garbage so the profile reflects live allocations
+		runtime.GC()
+		if err := pprof.WriteHeapProfile(f); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("memprofile: failed to write: %w", err)
+		}
+	}
+
+	return firstErr
+}
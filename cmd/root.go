@@ -46,8 +46,20 @@ var rootCmd = &cobra.Command{
         Short: "An Apache Cloudberry (Incubator) toolbox",
         Long:  "An Apache Cloudberry (Incubator) toolbox",
         PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-                // Skip GPHOME check for help and version commands
-                if cmd.Name() == "help" || cmd.Name() == "version" {
+                if err := startProfiling(); err != nil {
+                        return err
+                }
+                if err := applyEnvDefaults(cmd); err != nil {
+                        return err
+                }
+                if err := normalizeFormat(cmd); err != nil {
+                        return err
+                }
+
+                // Skip GPHOME check for help and version commands, for prereqs,
+                // which reports a missing GPHOME as a failed check, and for
+                // cluster, which collects on remote hosts
+                if cmd.Name() == "help" || cmd.Name() == "version" || cmd.Name() == "prereqs" || cmd.Name() == "cluster" {
                         return nil
                 }
 
@@ -59,12 +71,12 @@ var rootCmd = &cobra.Command{
                 // Check GPHOME environment variable
                 gphome := os.Getenv("GPHOME")
                 if gphome == "" {
-                        return fmt.Errorf("GPHOME environment variable is not set")
+                        return sysinfo.ErrGPHOMENotSet
                 }
 
                 // Verify GPHOME points to a valid directory
                 if _, err := os.Stat(gphome); os.IsNotExist(err) {
-                        return fmt.Errorf("GPHOME directory does not exist: %s", gphome)
+                        return fmt.Errorf("%w: %s", sysinfo.ErrGPHOMENotFound, gphome)
                 }
 
                 return nil
@@ -74,8 +86,25 @@ var rootCmd = &cobra.Command{
 func init() {
         rootCmd.AddCommand(sysinfo.Cmd)
         rootCmd.AddCommand(coreinfo.CoreinfoCmd)
+
+        commandFormats[sysinfo.Cmd] = formatSpec{formats: sysinfo.Formats, err: sysinfo.ErrInvalidFormat}
+        commandFormats[coreinfo.CoreinfoCmd] = formatSpec{formats: coreinfo.Formats, multiple: true, err: coreinfo.ErrInvalidFormat}
+        commandFormats[coreinfo.DiffCmd] = formatSpec{formats: coreinfo.Formats, err: coreinfo.ErrInvalidFormat}
+        commandFormats[coreinfo.ShowCmd] = formatSpec{formats: coreinfo.Formats, multiple: true, err: coreinfo.ErrInvalidFormat}
+
+        // Profiling flags are hidden; they are intended for contributors
+        rootCmd.PersistentFlags().StringVar(&cpuProfilePath, "cpuprofile", "", "Write a CPU profile to this file")
+        rootCmd.PersistentFlags().StringVar(&memProfilePath, "memprofile", "", "Write a memory profile to this file")
+        _ = rootCmd.PersistentFlags().MarkHidden("cpuprofile")
+        _ = rootCmd.PersistentFlags().MarkHidden("memprofile")
 }
 
+// Execute runs the root command. Any profiles requested with --cpuprofile
+// or --memprofile are flushed even when the command returns an error.
 func Execute() error {
-        return rootCmd.Execute()
+        err := rootCmd.Execute()
+        if profErr := stopProfiling(); profErr != nil && err == nil {
+                err = profErr
+        }
+        return err
 }
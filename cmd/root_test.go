@@ -14,7 +14,9 @@
 package cmd
 
 import (
+	"errors"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/spf13/cobra"
@@ -114,3 +116,45 @@ func TestGPHOMESkipForHelp(t *testing.T) {
 		t.Errorf("PersistentPreRunE() should not check GPHOME for help command, got error: %v", err)
 	}
 }
+
+func TestProfilingFlags(t *testing.T) {
+	originalGPHOME := os.Getenv("GPHOME")
+	defer os.Setenv("GPHOME", originalGPHOME)
+	os.Setenv("GPHOME", t.TempDir())
+
+	defer func() { cpuProfilePath, memProfilePath = "", "" }()
+	defer rootCmd.SetArgs(nil)
+
+	failing := errors.New("command failed")
+	testCmd := &cobra.Command{
+		Use: "profiletest",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return failing
+		},
+	}
+	rootCmd.AddCommand(testCmd)
+	defer rootCmd.RemoveCommand(testCmd)
+
+	dir := t.TempDir()
+	cpuProfile := filepath.Join(dir, "cpu.pprof")
+	memProfile := filepath.Join(dir, "mem.pprof")
+	rootCmd.SetArgs([]string{"profiletest", "--cpuprofile", cpuProfile, "--memprofile", memProfile})
+
+	// Profiles must be flushed even though the command fails
+	if err := Execute(); !errors.Is(err, failing) {
+		t.Fatalf("Expected command error to be returned, got: %v", err)
+	}
+
+	for _, path := range []string{cpuProfile, memProfile} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Errorf("Expected profile %s to be written: %v", path, err)
+		} else if info.Size() == 0 {
+			t.Errorf("Expected profile %s to be non-empty", path)
+		}
+	}
+
+	if flag := rootCmd.PersistentFlags().Lookup("cpuprofile"); flag == nil || !flag.Hidden {
+		t.Error("Expected --cpuprofile to be a hidden persistent flag")
+	}
+}
@@ -0,0 +1,168 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysinfo
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Fixture layout written by --capture-fixtures. Files read through readFile
+// are mirrored under filesFixtureDir at their absolute path, and the
+// standard output of each command run through runCommand is written to
+// commandsFixtureDir under its escaped command line (see fixtureCommandName).
+// A command that exited non-zero also gets a .exit file with its status.
+const (
+	filesFixtureDir    = "files"
+	commandsFixtureDir = "commands"
+)
+
+// redactedHost replaces the hostname in captured fixtures.
+const redactedHost = "redacted-host"
+
+// fixtureRecorder writes the raw inputs sysinfo reads into a fixture
+// directory. Collectors run concurrently, so writes are serialized.
+type fixtureRecorder struct {
+	dir  string
+	host *regexp.Regexp
+
+	mu  sync.Mutex
+	err error
+}
+
+// captureFixtures wraps readFile and runCommand so that every file and
+// command output they return is also written below dir. When redact is
+// set, the hostname is replaced with redactedHost in file contents and
+// command outputs. Command lines are kept as they are, since they name the
+// fixture a replay looks up. The returned stop function restores the hooks and reports
+// the first fixture that could not be written.
+func captureFixtures(dir string, redact bool) (stop func() error, err error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("fixtures: failed to create directory: %w", err)
+	}
+	rec := &fixtureRecorder{dir: dir}
+	if redact {
+		rec.host = hostnamePattern()
+	}
+
+	originalReadFile, originalRunCommand := readFile, runCommand
+	readFile = func(path string) ([]byte, error) {
+		content, err := originalReadFile(path)
+		if err == nil {
+			rec.file(path, content)
+		}
+		return content, err
+	}
+	runCommand = func(name string, args ...string) ([]byte, error) {
+		output, err := originalRunCommand(name, args...)
+		var exitErr *exec.ExitError
+		switch {
+		case err == nil:
+			rec.command(name, args, output, 0)
+		case errors.As(err, &exitErr):
+			rec.command(name, args, output, exitErr.ExitCode())
+		}
+		return output, err
+	}
+
+	// MemoryStats streams /proc/meminfo instead of going through readFile.
+	if _, err := readFile(procMeminfo); err != nil {
+		rec.fail(fmt.Errorf("fixtures: failed to read %s: %w", procMeminfo, err))
+	}
+
+	return func() error {
+		readFile, runCommand = originalReadFile, originalRunCommand
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		return rec.err
+	}, nil
+}
+
+// hostnamePattern matches the hostname and its short form as whole words.
+// It returns nil when the hostname cannot be determined.
+func hostnamePattern() *regexp.Regexp {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		return nil
+	}
+	names := []string{regexp.QuoteMeta(hostname)}
+	if short, _, found := strings.Cut(hostname, "."); found && short != "" {
+		names = append(names, regexp.QuoteMeta(short))
+	}
+	return regexp.MustCompile(`\b(?:` + strings.Join(names, "|") + `)\b`)
+}
+
+// redact replaces the hostname in s, if redaction is enabled.
+func (r *fixtureRecorder) redact(s string) string {
+	if r.host == nil {
+		return s
+	}
+	return r.host.ReplaceAllLiteralString(s, redactedHost)
+}
+
+// file records the content of a file read from path.
+func (r *fixtureRecorder) file(path string, content []byte) {
+	r.write(fixtureFilePath(r.dir, path), []byte(r.redact(string(content))))
+}
+
+// command records the output and exit status of a command.
+func (r *fixtureRecorder) command(name string, args []string, output []byte, exitCode int) {
+	base := filepath.Join(r.dir, commandsFixtureDir, fixtureCommandName(strings.Join(append([]string{name}, args...), " ")))
+	r.write(base+".out", []byte(r.redact(string(output))))
+	if exitCode != 0 {
+		r.write(base+".exit", []byte(strconv.Itoa(exitCode)+"\n"))
+	}
+}
+
+// write stores a single fixture, keeping the first error.
+func (r *fixtureRecorder) write(path string, content []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err == nil {
+		err = os.WriteFile(path, content, 0o644)
+	}
+	if err != nil && r.err == nil {
+		r.err = fmt.Errorf("fixtures: failed to write %s: %w", path, err)
+	}
+}
+
+// fail records err unless an earlier error was already recorded.
+func (r *fixtureRecorder) fail(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+// fixtureFilePath returns where the content of path is stored in a fixture
+// directory: /proc/meminfo becomes <dir>/files/proc/meminfo. Relative paths
+// are treated as if they were absolute, so they cannot escape dir.
+func fixtureFilePath(dir, path string) string {
+	return filepath.Join(dir, filesFixtureDir, filepath.Clean("/"+path))
+}
+
+// fixtureCommandName returns the file name, without extension, under which
+// the output of a command line is stored, e.g. "uname%20-r".
+func fixtureCommandName(commandLine string) string {
+	return url.PathEscape(commandLine)
+}
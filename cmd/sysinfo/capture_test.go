@@ -0,0 +1,143 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysinfo
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// replayFixtures points readFile and runCommand at a directory written by
+// --capture-fixtures. Commands without a fixture fail as if not installed.
+func replayFixtures(t *testing.T, dir string) {
+	t.Helper()
+	originalReadFile, originalRunCommand := readFile, runCommand
+	t.Cleanup(func() { readFile, runCommand = originalReadFile, originalRunCommand })
+	readFile = func(path string) ([]byte, error) {
+		return os.ReadFile(fixtureFilePath(dir, path))
+	}
+	runCommand = func(name string, args ...string) ([]byte, error) {
+		base := filepath.Join(dir, commandsFixtureDir, fixtureCommandName(strings.Join(append([]string{name}, args...), " ")))
+		output, err := os.ReadFile(base + ".out")
+		if err != nil {
+			return nil, errors.New("executable file not found in $PATH")
+		}
+		if status, err := os.ReadFile(base + ".exit"); err == nil {
+			code, _ := strconv.Atoi(strings.TrimSpace(string(status)))
+			return output, exec.Command("sh", "-c", "exit "+strconv.Itoa(code)).Run()
+		}
+		return output, nil
+	}
+}
+
+// mockCapturedCommands mocks runCommand like mockCommands, except that
+// systemd-detect-virt --vm prints "none" and exits 1 as it does on bare metal.
+func mockCapturedCommands(t *testing.T, outputs map[string]string) {
+	t.Helper()
+	mockCommands(t, outputs)
+	mocked := runCommand
+	runCommand = func(name string, args ...string) ([]byte, error) {
+		if name == "systemd-detect-virt" {
+			return []byte("none\n"), exec.Command("sh", "-c", "exit 1").Run()
+		}
+		return mocked(name, args...)
+	}
+}
+
+// TestCaptureFixtures validates that captured fixtures replay to the same
+// results, and that the hostname is redacted unless disabled.
+func TestCaptureFixtures(t *testing.T) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		t.Skip("hostname not available")
+	}
+	originalOSRelease, originalMeminfo := osReleasePath, procMeminfo
+	t.Cleanup(func() { osReleasePath, procMeminfo = originalOSRelease, originalMeminfo })
+	src := t.TempDir()
+	osReleasePath = filepath.Join(src, "os-release")
+	procMeminfo = filepath.Join(src, "meminfo")
+	if err := os.WriteFile(osReleasePath, []byte("PRETTY_NAME=\"Rocky Linux 9.3\"\nBUILD_HOST="+hostname+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(procMeminfo, []byte("MemTotal:       16384000 kB\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mockCapturedCommands(t, map[string]string{"uname -r": "5.14.0-362.el9.x86_64\n"})
+
+	for _, redact := range []bool{true, false} {
+		t.Run("redact="+strconv.FormatBool(redact), func(t *testing.T) {
+			dir := t.TempDir()
+			stop, err := captureFixtures(dir, redact)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			osVersion, _ := getOSVersion()
+			kernel, _ := getKernelVersion()
+			virt, _ := detectVirt("--vm")
+			if err := stop(); err != nil {
+				t.Fatalf("Unexpected error writing fixtures: %v", err)
+			}
+
+			if _, err := os.Stat(fixtureFilePath(dir, procMeminfo)); err != nil {
+				t.Errorf("Expected meminfo fixture: %v", err)
+			}
+			captured, err := os.ReadFile(fixtureFilePath(dir, osReleasePath))
+			if err != nil {
+				t.Fatalf("Expected os-release fixture: %v", err)
+			}
+			if strings.Contains(string(captured), "BUILD_HOST="+hostname) == redact {
+				t.Errorf("Unexpected hostname redaction (redact=%v):\n%s", redact, captured)
+			}
+			if redact && !strings.Contains(string(captured), "BUILD_HOST="+redactedHost) {
+				t.Errorf("Expected redacted hostname, got:\n%s", captured)
+			}
+
+			replayFixtures(t, dir)
+			if got, _ := getOSVersion(); got != osVersion {
+				t.Errorf("Replayed OS version %q, captured %q", got, osVersion)
+			}
+			if got, _ := getKernelVersion(); got != kernel {
+				t.Errorf("Replayed kernel %q, captured %q", got, kernel)
+			}
+			if got, err := detectVirt("--vm"); err != nil || got != virt {
+				t.Errorf("Replayed systemd-detect-virt %q, %v, captured %q", got, err, virt)
+			}
+		})
+	}
+}
+
+// TestFixtureFilePath validates that fixture paths stay inside the directory.
+func TestFixtureFilePath(t *testing.T) {
+	for path, expected := range map[string]string{
+		"/proc/meminfo":    "/fixtures/files/proc/meminfo",
+		"../../etc/passwd": "/fixtures/files/etc/passwd",
+	} {
+		if got := fixtureFilePath("/fixtures", path); got != expected {
+			t.Errorf("fixtureFilePath(%q) = %q, expected %q", path, got, expected)
+		}
+	}
+}
+
+// TestNoRedactRequiresCaptureFixtures validates the --no-redact check.
+func TestNoRedactRequiresCaptureFixtures(t *testing.T) {
+	opts := defaultOptions()
+	opts.noRedact = true
+	if err := runSysInfo(opts); err == nil || !strings.Contains(err.Error(), "--capture-fixtures") {
+		t.Errorf("Expected --no-redact error, got %v", err)
+	}
+}
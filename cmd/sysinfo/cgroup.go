@@ -0,0 +1,170 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysinfo
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// cgroupRoot specifies the mount point of the cgroup hierarchy. Inside a
+// container it is the container's own cgroup.
+var cgroupRoot = "/sys/fs/cgroup"
+
+// cgroupLimitThreshold is the fraction of a host resource below which an
+// effective cgroup limit is flagged as differing significantly.
+const cgroupLimitThreshold = 0.9
+
+// CGroupLimits reports the host CPU and memory totals next to the effective
+// limits imposed by the cgroup the process runs in. runtime.NumCPU() and
+// /proc/meminfo report host totals even inside a container, so tuning based
+// on them alone can be misleading.
+type CGroupLimits struct {
+	Version     string   `json:"version" yaml:"version"`
+	HostCPUs    int      `json:"host_cpus" yaml:"host_cpus"`
+	CPULimit    string   `json:"cpu_limit" yaml:"cpu_limit"`
+	HostMemory  string   `json:"host_memory,omitempty" yaml:"host_memory,omitempty"`
+	MemoryLimit string   `json:"memory_limit" yaml:"memory_limit"`
+	Warnings    []string `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+}
+
+// readCGroupFile returns the trimmed content of a file under cgroupRoot.
+func readCGroupFile(parts ...string) (string, error) {
+	content, err := readFile(filepath.Join(append([]string{cgroupRoot}, parts...)...))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(content)), nil
+}
+
+// getCGroupCPULimit returns the effective CPU limit in CPUs and the cgroup
+// version it was read from. A limit of 0 means unlimited.
+// Returns an error if neither cgroup v2 nor v1 CPU controls are readable.
+func getCGroupCPULimit() (float64, string, error) {
+	// cgroup v2: "<quota> <period>" or "max <period>"
+	if cpuMax, err := readCGroupFile("cpu.max"); err == nil {
+		fields := strings.Fields(cpuMax)
+		if len(fields) != 2 || fields[0] == "max" {
+			return 0, "v2", nil
+		}
+		quota, errQ := strconv.ParseFloat(fields[0], 64)
+		period, errP := strconv.ParseFloat(fields[1], 64)
+		if errQ != nil || errP != nil || period <= 0 {
+			return 0, "v2", fmt.Errorf("cgroup: invalid cpu.max %q", cpuMax)
+		}
+		return quota / period, "v2", nil
+	}
+
+	// cgroup v1: a quota of -1 means unlimited
+	quotaStr, err := readCGroupFile("cpu", "cpu.cfs_quota_us")
+	if err != nil {
+		return 0, "", fmt.Errorf("cgroup: failed to read CPU limit: %w", err)
+	}
+	periodStr, err := readCGroupFile("cpu", "cpu.cfs_period_us")
+	if err != nil {
+		return 0, "v1", fmt.Errorf("cgroup: failed to read CPU period: %w", err)
+	}
+	quota, errQ := strconv.ParseFloat(quotaStr, 64)
+	period, errP := strconv.ParseFloat(periodStr, 64)
+	if errQ != nil || errP != nil || period <= 0 {
+		return 0, "v1", fmt.Errorf("cgroup: invalid CPU quota %q or period %q", quotaStr, periodStr)
+	}
+	if quota <= 0 {
+		return 0, "v1", nil
+	}
+	return quota / period, "v1", nil
+}
+
+// getCGroupMemoryLimit returns the effective memory limit in kilobytes.
+// A limit of 0 means unlimited. Returns an error if neither cgroup v2
+// nor v1 memory controls are readable.
+func getCGroupMemoryLimit() (int64, error) {
+	limit, err := readCGroupFile("memory.max")
+	if err != nil {
+		limit, err = readCGroupFile("memory", "memory.limit_in_bytes")
+		if err != nil {
+			return 0, fmt.Errorf("cgroup: failed to read memory limit: %w", err)
+		}
+	}
+	if limit == "max" {
+		return 0, nil
+	}
+	bytes, err := strconv.ParseInt(limit, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("cgroup: invalid memory limit %q", limit)
+	}
+	return bytes / 1024, nil
+}
+
+// getHostMemoryKB returns MemTotal from /proc/meminfo in kilobytes.
+func getHostMemoryKB() (int64, error) {
+	content, err := readFile(procMeminfo)
+	if err != nil {
+		return 0, fmt.Errorf("meminfo: failed to read file: %w", err)
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "MemTotal:" {
+			return strconv.ParseInt(fields[1], 10, 64)
+		}
+	}
+	return 0, fmt.Errorf("meminfo: MemTotal not found")
+}
+
+// getCGroupLimits collects the effective cgroup CPU and memory limits and
+// the host totals they apply to, rendering memory in the given unit system.
+// Limits well below the host totals are flagged with a warning.
+// Returns nil if no cgroup controls are readable.
+func getCGroupLimits(units string) *CGroupLimits {
+	cpuLimit, version, cpuErr := getCGroupCPULimit()
+	memLimitKB, memErr := getCGroupMemoryLimit()
+	if cpuErr != nil && memErr != nil {
+		return nil
+	}
+
+	limits := &CGroupLimits{
+		Version:     version,
+		HostCPUs:    getCPUCount(),
+		CPULimit:    "unlimited",
+		MemoryLimit: "unlimited",
+	}
+	if limits.Version == "" {
+		limits.Version = "v1"
+	}
+
+	if cpuErr == nil && cpuLimit > 0 {
+		limits.CPULimit = strconv.FormatFloat(cpuLimit, 'f', -1, 64)
+		if cpuLimit < float64(limits.HostCPUs)*cgroupLimitThreshold {
+			limits.Warnings = append(limits.Warnings, fmt.Sprintf(
+				"CPU limit of %s CPUs is below the %d host CPUs; size settings from the container limit",
+				limits.CPULimit, limits.HostCPUs))
+		}
+	}
+
+	hostMemKB, hostErr := getHostMemoryKB()
+	if hostErr == nil {
+		limits.HostMemory = formatSize(strconv.FormatInt(hostMemKB, 10), units)
+	}
+	// cgroup v1 reports "unlimited" as a very large number rather than a keyword
+	if memErr == nil && memLimitKB > 0 && (hostErr != nil || memLimitKB < hostMemKB) {
+		limits.MemoryLimit = formatSize(strconv.FormatInt(memLimitKB, 10), units)
+		if hostErr == nil && float64(memLimitKB) < float64(hostMemKB)*cgroupLimitThreshold {
+			limits.Warnings = append(limits.Warnings, fmt.Sprintf(
+				"memory limit of %s is below the %s host memory; size settings from the container limit",
+				limits.MemoryLimit, limits.HostMemory))
+		}
+	}
+	return limits
+}
@@ -0,0 +1,158 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysinfo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// writeCGroupFiles creates a mock cgroup hierarchy and meminfo file from
+// paths relative to the cgroup root.
+func writeCGroupFiles(t *testing.T, files map[string]string, memTotalKB int) {
+	t.Helper()
+	originalRoot, originalMeminfo := cgroupRoot, procMeminfo
+	t.Cleanup(func() { cgroupRoot, procMeminfo = originalRoot, originalMeminfo })
+
+	cgroupRoot = t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(cgroupRoot, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content+"\n"), 0644); err != nil {
+			t.Fatalf("Failed to write mock %s: %v", name, err)
+		}
+	}
+
+	procMeminfo = filepath.Join(t.TempDir(), "meminfo")
+	meminfo := fmt.Sprintf("MemTotal:       %d kB\nMemFree:        1024 kB\n", memTotalKB)
+	if err := os.WriteFile(procMeminfo, []byte(meminfo), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestGetCGroupLimits validates cgroup v1 and v2 limit detection and the
+// warnings for limits well below the host totals.
+func TestGetCGroupLimits(t *testing.T) {
+	hostCPUs := runtime.NumCPU()
+	const hostMemKB = 16 * 1024 * 1024 // 16 GiB
+
+	tests := []struct {
+		name        string
+		files       map[string]string
+		version     string
+		cpuLimit    string
+		memoryLimit string
+		warnings    int
+	}{
+		{
+			name:        "v2 unlimited",
+			files:       map[string]string{"cpu.max": "max 100000", "memory.max": "max"},
+			version:     "v2",
+			cpuLimit:    "unlimited",
+			memoryLimit: "unlimited",
+		},
+		{
+			name:        "v2 memory limited",
+			files:       map[string]string{"cpu.max": "max 100000", "memory.max": "4294967296"},
+			version:     "v2",
+			cpuLimit:    "unlimited",
+			memoryLimit: "4.0 GiB",
+			warnings:    1,
+		},
+		{
+			name: "v1 unlimited",
+			files: map[string]string{
+				"cpu/cpu.cfs_quota_us":         "-1",
+				"cpu/cpu.cfs_period_us":        "100000",
+				"memory/memory.limit_in_bytes": "9223372036854771712",
+			},
+			version:     "v1",
+			cpuLimit:    "unlimited",
+			memoryLimit: "unlimited",
+		},
+		{
+			name: "v1 memory limited",
+			files: map[string]string{
+				"cpu/cpu.cfs_quota_us":         "-1",
+				"cpu/cpu.cfs_period_us":        "100000",
+				"memory/memory.limit_in_bytes": "2147483648",
+			},
+			version:     "v1",
+			cpuLimit:    "unlimited",
+			memoryLimit: "2.0 GiB",
+			warnings:    1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeCGroupFiles(t, tt.files, hostMemKB)
+
+			limits := getCGroupLimits(unitsBinary)
+			if limits == nil {
+				t.Fatal("Expected cgroup limits, got nil")
+			}
+			if limits.Version != tt.version {
+				t.Errorf("Expected version %q, got %q", tt.version, limits.Version)
+			}
+			if limits.HostCPUs != hostCPUs {
+				t.Errorf("Expected %d host CPUs, got %d", hostCPUs, limits.HostCPUs)
+			}
+			if limits.HostMemory != "16.0 GiB" {
+				t.Errorf("Expected host memory 16.0 GiB, got %q", limits.HostMemory)
+			}
+			if limits.CPULimit != tt.cpuLimit {
+				t.Errorf("Expected CPU limit %q, got %q", tt.cpuLimit, limits.CPULimit)
+			}
+			if limits.MemoryLimit != tt.memoryLimit {
+				t.Errorf("Expected memory limit %q, got %q", tt.memoryLimit, limits.MemoryLimit)
+			}
+			if len(limits.Warnings) != tt.warnings {
+				t.Errorf("Expected %d warnings, got %v", tt.warnings, limits.Warnings)
+			}
+		})
+	}
+}
+
+// TestGetCGroupCPULimit validates the CPU quota conversion for both cgroup versions.
+func TestGetCGroupCPULimit(t *testing.T) {
+	writeCGroupFiles(t, map[string]string{"cpu.max": "150000 100000"}, 1024)
+	if limit, version, err := getCGroupCPULimit(); err != nil || limit != 1.5 || version != "v2" {
+		t.Errorf("Expected 1.5 CPUs from v2, got %v %q %v", limit, version, err)
+	}
+
+	writeCGroupFiles(t, map[string]string{"cpu/cpu.cfs_quota_us": "200000", "cpu/cpu.cfs_period_us": "100000"}, 1024)
+	if limit, version, err := getCGroupCPULimit(); err != nil || limit != 2 || version != "v1" {
+		t.Errorf("Expected 2 CPUs from v1, got %v %q %v", limit, version, err)
+	}
+
+	// A quota below the host CPU count is flagged
+	if runtime.NumCPU() > 2 {
+		if limits := getCGroupLimits(unitsBinary); limits == nil || limits.CPULimit != "2" || len(limits.Warnings) != 1 {
+			t.Errorf("Expected a CPU limit warning, got %+v", limits)
+		}
+	}
+}
+
+// TestGetCGroupLimitsUnavailable validates that no limits are reported without cgroup controls.
+func TestGetCGroupLimitsUnavailable(t *testing.T) {
+	writeCGroupFiles(t, map[string]string{}, 1024)
+	if limits := getCGroupLimits(unitsBinary); limits != nil {
+		t.Errorf("Expected nil without cgroup files, got %+v", limits)
+	}
+}
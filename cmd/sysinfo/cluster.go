@@ -0,0 +1,260 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysinfo
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultRemoteCommand collects sysinfo on each host of a cluster check.
+const defaultRemoteCommand = "cbtoolbox sysinfo --format json"
+
+// defaultClusterParallel bounds the number of concurrent SSH sessions.
+const defaultClusterParallel = 8
+
+// clusterComparedFields are the flattened fields compared across hosts,
+// as exact keys or, ending in ".", key prefixes. Fields that differ by
+// nature, such as the hostname, free memory and timings, are left out.
+var clusterComparedFields = []string{
+	"os", "os_version", "architecture", "kernel", "kernel_parameters.", "cpus",
+	"memory_stats.MemTotal", "postgres_version", "gp_version", "gphome_filesystem",
+	"security_modules.", "kernel_tuning.", "resource_limits.", "time_sync.synchronized",
+	"cgroup_limits.version", "cgroup_limits.cpu_limit", "cgroup_limits.memory_limit",
+	"libraries.glibc", "services.",
+}
+
+// ClusterCmd collects sysinfo on a list of hosts over SSH and reports the
+// fields that differ between them.
+var ClusterCmd = &cobra.Command{
+	Use:   "cluster --hosts <file>",
+	Short: "Compare system information across hosts",
+	Long: `Collect system information on each host listed in the hosts file over SSH
+and print a matrix of the fields that differ between hosts, such as the kernel,
+CPU count, total memory, Cloudberry version and kernel tuning.`,
+	Args: cobra.NoArgs,
+	RunE: RunCluster,
+}
+
+// HostInfo is the sysinfo collected on one host of a cluster check.
+// Err is set when the host could not be reached or its output parsed.
+type HostInfo struct {
+	Host string
+	Info SysInfo
+	Err  error
+}
+
+// readHostsFile returns the hosts listed in a hosts file, one per line, as
+// in gpssh host files. Blank lines and lines starting with # are skipped.
+// Returns an error if the file cannot be read or lists no hosts.
+func readHostsFile(path string) ([]string, error) {
+	content, err := readFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cluster: failed to read hosts file: %w", err)
+	}
+	var hosts []string
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		host := strings.TrimSpace(scanner.Text())
+		if host == "" || strings.HasPrefix(host, "#") || seen[host] {
+			continue
+		}
+		seen[host] = true
+		hosts = append(hosts, host)
+	}
+	if len(hosts) == 0 {
+		return nil, fmt.Errorf("cluster: no hosts in %s", path)
+	}
+	return hosts, nil
+}
+
+// collectHost runs the remote command on host over SSH and parses its
+// JSON output. sysinfo exits non-zero when GPHOME is not set but still
+// prints the document, so any output that parses is used.
+func collectHost(host, remoteCommand string) HostInfo {
+	output, err := runCommand("ssh", "-o", "BatchMode=yes", "-o", "ConnectTimeout=10", host, remoteCommand)
+	result := HostInfo{Host: host}
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		result.Err = fmt.Errorf("cluster: ssh %s failed: %w", host, err)
+		return result
+	}
+	if jsonErr := json.Unmarshal(output, &result.Info); jsonErr != nil {
+		if err != nil {
+			result.Err = fmt.Errorf("cluster: %s: %w: %s", host, err, bytes.TrimSpace(exitErr.Stderr))
+		} else {
+			result.Err = fmt.Errorf("cluster: %s: invalid sysinfo output: %w", host, jsonErr)
+		}
+	}
+	return result
+}
+
+// collectCluster collects sysinfo on each host with at most parallel
+// sessions at a time. The results are in the order of hosts.
+func collectCluster(hosts []string, remoteCommand string, parallel int) []HostInfo {
+	results := make([]HostInfo, len(hosts))
+	slots := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i, host := range hosts {
+		wg.Add(1)
+		go func(i int, host string) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			results[i] = collectHost(host, remoteCommand)
+		}(i, host)
+	}
+	wg.Wait()
+	return results
+}
+
+// isClusterCompared reports whether a flattened field is compared across hosts.
+func isClusterCompared(key string) bool {
+	for _, field := range clusterComparedFields {
+		if key == field || (strings.HasSuffix(field, ".") && strings.HasPrefix(key, field)) {
+			return true
+		}
+	}
+	return false
+}
+
+// clusterDifferences returns the compared fields whose values differ
+// between the hosts, sorted, with each host's value; a host without the
+// field has an empty value. Hosts with errors are left out.
+func clusterDifferences(results []HostInfo) ([]string, map[string]map[string]string, error) {
+	values := make(map[string]map[string]string)
+	for _, result := range results {
+		if result.Err != nil {
+			continue
+		}
+		flat, _, err := flatten(result.Info)
+		if err != nil {
+			return nil, nil, err
+		}
+		for key, value := range flat {
+			if !isClusterCompared(key) {
+				continue
+			}
+			if values[key] == nil {
+				values[key] = make(map[string]string)
+			}
+			values[key][result.Host] = fmt.Sprint(value)
+		}
+	}
+
+	var differing []string
+	for key, byHost := range values {
+		distinct := make(map[string]bool)
+		for _, result := range results {
+			if result.Err == nil {
+				distinct[byHost[result.Host]] = true
+			}
+		}
+		if len(distinct) > 1 {
+			differing = append(differing, key)
+		}
+	}
+	sort.Strings(differing)
+	return differing, values, nil
+}
+
+// printClusterMatrix writes a table with a row per differing field and a
+// column per reachable host.
+func printClusterMatrix(w io.Writer, results []HostInfo) error {
+	differing, values, err := clusterDifferences(results)
+	if err != nil {
+		return err
+	}
+	var hosts []string
+	for _, result := range results {
+		if result.Err == nil {
+			hosts = append(hosts, result.Host)
+		}
+	}
+	if len(differing) == 0 {
+		fmt.Fprintf(w, "All %d hosts match on the compared fields\n", len(hosts))
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "FIELD\t%s\n", strings.Join(hosts, "\t"))
+	for _, key := range differing {
+		row := []string{key}
+		for _, host := range hosts {
+			value := values[key][host]
+			if value == "" {
+				value = "-"
+			}
+			row = append(row, value)
+		}
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// RunCluster implements the sysinfo cluster command.
+func RunCluster(cmd *cobra.Command, args []string) error {
+	hostsFile, _ := cmd.Flags().GetString("hosts")
+	remoteCommand, _ := cmd.Flags().GetString("remote-command")
+	parallel, _ := cmd.Flags().GetInt("parallel")
+	if hostsFile == "" {
+		return fmt.Errorf("cluster: --hosts is required")
+	}
+	if parallel < 1 {
+		return fmt.Errorf("cluster: --parallel must be at least 1")
+	}
+
+	hosts, err := readHostsFile(hostsFile)
+	if err != nil {
+		return err
+	}
+	results := collectCluster(hosts, remoteCommand, parallel)
+	if err := printClusterMatrix(os.Stdout, results); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			if failed == 0 {
+				fmt.Fprintln(os.Stderr, "\nUnreachable hosts:")
+			}
+			fmt.Fprintln(os.Stderr, "-", result.Err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%w: %d of %d hosts", ErrClusterCollectionFailed, failed, len(hosts))
+	}
+	return nil
+}
+
+func init() {
+	ClusterCmd.Flags().String("hosts", "", "File listing the hosts to compare, one per line")
+	ClusterCmd.Flags().String("remote-command", defaultRemoteCommand, "Command run on each host over SSH; it must print sysinfo JSON")
+	ClusterCmd.Flags().Int("parallel", defaultClusterParallel, "Maximum number of hosts collected concurrently")
+	Cmd.AddCommand(ClusterCmd)
+}
@@ -0,0 +1,83 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysinfo
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestReadHostsFile validates hosts file parsing.
+func TestReadHostsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hosts.txt")
+	if err := os.WriteFile(path, []byte("# segment hosts\nsdw1\n\n  sdw2  \nsdw1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	hosts, err := readHostsFile(path)
+	if err != nil || strings.Join(hosts, ",") != "sdw1,sdw2" {
+		t.Errorf("readHostsFile() = %q, %v; expected [sdw1 sdw2]", hosts, err)
+	}
+
+	empty := filepath.Join(t.TempDir(), "empty.txt")
+	if err := os.WriteFile(empty, []byte("# none\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readHostsFile(empty); err == nil {
+		t.Error("expected an error for a hosts file without hosts")
+	}
+}
+
+// TestClusterMatrix validates collection over SSH and that only the
+// compared fields that differ are reported.
+func TestClusterMatrix(t *testing.T) {
+	ssh := "ssh -o BatchMode=yes -o ConnectTimeout=10 "
+	mockCommands(t, map[string]string{
+		ssh + "sdw1 " + defaultRemoteCommand: `{"hostname":"sdw1","kernel":"5.14.0","cpus":16,"memory_stats":{"MemTotal":"62.0 GiB","MemFree":"10.0 GiB"},"gp_version":"1.6.0"}`,
+		ssh + "sdw2 " + defaultRemoteCommand: `{"hostname":"sdw2","kernel":"5.14.0","cpus":8,"memory_stats":{"MemTotal":"62.0 GiB","MemFree":"20.0 GiB"},"gp_version":"1.6.0","kernel_tuning":{"swap":{"vm.swappiness":"60"}}}`,
+		ssh + "sdw3 " + defaultRemoteCommand: `not json`,
+	})
+
+	results := collectCluster([]string{"sdw1", "sdw2", "sdw3", "sdw4"}, defaultRemoteCommand, 2)
+	if results[0].Err != nil || results[1].Err != nil {
+		t.Fatalf("unexpected errors: %v, %v", results[0].Err, results[1].Err)
+	}
+	if results[2].Err == nil || results[3].Err == nil {
+		t.Errorf("expected errors for invalid output and an unreachable host")
+	}
+
+	var buf bytes.Buffer
+	if err := printClusterMatrix(&buf, results); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected a header and two differing fields, got:\n%s", buf.String())
+	}
+	if fields := strings.Fields(lines[0]); strings.Join(fields, " ") != "FIELD sdw1 sdw2" {
+		t.Errorf("unexpected header %q", lines[0])
+	}
+	if fields := strings.Fields(lines[1]); strings.Join(fields, " ") != "cpus 16 8" {
+		t.Errorf("unexpected row %q", lines[1])
+	}
+	if fields := strings.Fields(lines[2]); strings.Join(fields, " ") != "kernel_tuning.swap.vm.swappiness - 60" {
+		t.Errorf("unexpected row %q", lines[2])
+	}
+
+	buf.Reset()
+	if err := printClusterMatrix(&buf, results[:1]); err != nil || !strings.Contains(buf.String(), "All 1 hosts match") {
+		t.Errorf("expected matching hosts to be reported, got %q, %v", buf.String(), err)
+	}
+}
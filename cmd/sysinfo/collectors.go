@@ -0,0 +1,117 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysinfo
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Names of the collectors that --skip-collectors can disable. Each reads
+// /proc, /sys or runs commands that locked-down hosts may disallow. The
+// basic host identity (os, architecture, hostname, kernel, os_version,
+// cpus) and the GPHOME checks are always collected.
+const (
+	collectorCmdline   = "cmdline"   // kernel_cmdline, kernel_parameters, kernel_notes
+	collectorMem       = "mem"       // memory_stats, memory_stats_full
+	collectorSecurity  = "security"  // security_modules
+	collectorTuning    = "tuning"    // kernel_tuning
+	collectorLimits    = "limits"    // resource_limits
+	collectorTime      = "time"      // time_sync
+	collectorCGroup    = "cgroup"    // cgroup_limits
+	collectorMounts    = "mounts"    // mount_options, mount_warnings, gphome_filesystem
+	collectorBackends  = "backends"  // running_backends
+	collectorLibraries = "libraries" // libraries
+	collectorServices  = "services"  // services
+	collectorMachine   = "machine"   // machine_id, virtualization
+)
+
+// Collectors lists the collectors that --skip-collectors can disable.
+var Collectors = []string{
+	collectorCmdline, collectorMem, collectorSecurity, collectorTuning,
+	collectorLimits, collectorTime, collectorCGroup, collectorMounts,
+	collectorBackends, collectorLibraries, collectorServices, collectorMachine,
+}
+
+// validateCollectors checks that every skipped collector exists, so a
+// typo does not silently leave a collector enabled.
+func validateCollectors(names []string) error {
+	for _, name := range names {
+		if !slices.Contains(Collectors, name) {
+			return fmt.Errorf("%w: %s (supported collectors: %s)", ErrInvalidCollector, name, strings.Join(Collectors, ", "))
+		}
+	}
+	return nil
+}
+
+// collects reports whether the collector name is enabled. Skipped
+// collectors are not attempted, so their fields are omitted from the
+// output without warnings.
+func (o options) collects(name string) bool {
+	return !slices.Contains(o.skipCollectors, name)
+}
+
+// maxParallelCollectors bounds how many collectors run at once. Most of
+// them wait on /proc reads or short commands, so they overlap well, but a
+// loaded host should not be hit with every command at the same time.
+const maxParallelCollectors = 8
+
+// collectorGroup runs collection steps concurrently, at most
+// maxParallelCollectors at a time, and aggregates the errors they return.
+// Each step is timed with the group's stepTimer. A failing step does not
+// stop the others: sysinfo reports collection errors as warnings.
+type collectorGroup struct {
+	group errgroup.Group
+	timer *stepTimer
+
+	mu   sync.Mutex
+	errs []error
+}
+
+// newCollectorGroup returns an empty group timing its steps with timer.
+func newCollectorGroup(timer *stepTimer) *collectorGroup {
+	c := &collectorGroup{timer: timer}
+	c.group.SetLimit(maxParallelCollectors)
+	return c
+}
+
+// run starts collect as the collection step named step, blocking while
+// maxParallelCollectors steps are running. Errors joined with errors.Join
+// are recorded one by one.
+func (c *collectorGroup) run(step string, collect func() error) {
+	c.group.Go(func() error {
+		defer c.timer.track(step)()
+		if err := collect(); err != nil {
+			c.mu.Lock()
+			if joined, ok := err.(interface{ Unwrap() []error }); ok {
+				c.errs = append(c.errs, joined.Unwrap()...)
+			} else {
+				c.errs = append(c.errs, err)
+			}
+			c.mu.Unlock()
+		}
+		return nil
+	})
+}
+
+// wait waits for all started steps and returns their errors.
+func (c *collectorGroup) wait() []error {
+	_ = c.group.Wait()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.errs
+}
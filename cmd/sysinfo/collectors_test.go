@@ -0,0 +1,120 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysinfo
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestValidateCollectors validates that only known collectors can be skipped.
+func TestValidateCollectors(t *testing.T) {
+	if err := validateCollectors([]string{"mem", "tuning"}); err != nil {
+		t.Errorf("Unexpected error for known collectors: %v", err)
+	}
+	if err := validateCollectors(nil); err != nil {
+		t.Errorf("Unexpected error without skipped collectors: %v", err)
+	}
+	if err := validateCollectors([]string{"mem", "memory"}); !errors.Is(err, ErrInvalidCollector) {
+		t.Errorf("Expected ErrInvalidCollector for an unknown collector, got %v", err)
+	}
+}
+
+// TestSkipCollectors validates that skipped collectors are neither attempted
+// nor reported, while the host identity is still collected.
+func TestSkipCollectors(t *testing.T) {
+	t.Setenv("GPHOME", "")
+	originalMeminfo := procMeminfo
+	t.Cleanup(func() { procMeminfo = originalMeminfo })
+	// An unreadable meminfo would be reported if the collector ran
+	procMeminfo = "/nonexistent/meminfo"
+	mockCommands(t, map[string]string{})
+
+	opts := defaultOptions()
+	opts.format = "json"
+	opts.skipCollectors = Collectors
+
+	var err error
+	output := captureOutput(func() { err = runSysInfo(opts) })
+	if !errors.Is(err, ErrGPHOMENotSet) {
+		t.Fatalf("Expected ErrGPHOMENotSet, got %v", err)
+	}
+
+	var document map[string]any
+	if err := json.Unmarshal([]byte(output), &document); err != nil {
+		t.Fatalf("Output is not JSON: %v\n%s", err, output)
+	}
+	for _, field := range []string{"kernel_cmdline", "memory_stats", "security_modules", "kernel_tuning", "resource_limits", "time_sync", "cgroup_limits", "libraries", "services", "machine_id", "virtualization"} {
+		if _, ok := document[field]; ok {
+			t.Errorf("Expected %s to be omitted, got %v", field, document[field])
+		}
+	}
+	if warnings, _ := json.Marshal(document["warnings"]); strings.Contains(string(warnings), "meminfo") {
+		t.Errorf("Expected no warning from the skipped mem collector, got %s", warnings)
+	}
+	if hostname, _ := os.Hostname(); document["hostname"] != hostname {
+		t.Errorf("Expected hostname to be collected, got %v", document["hostname"])
+	}
+
+	opts.skipCollectors = []string{"network"}
+	if err := runSysInfo(opts); !errors.Is(err, ErrInvalidCollector) {
+		t.Errorf("Expected ErrInvalidCollector, got %v", err)
+	}
+}
+
+// TestCollectorGroup validates that the group runs every step, records all
+// errors including joined ones, times each step and bounds concurrency.
+func TestCollectorGroup(t *testing.T) {
+	timer := newStepTimer(true)
+	collectors := newCollectorGroup(timer)
+
+	var running, peak atomic.Int32
+	for i := 0; i < 3*maxParallelCollectors; i++ {
+		collectors.run("step"+strings.Repeat("x", i), func() error {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			return nil
+		})
+	}
+	collectors.run("failing", func() error { return errors.New("first") })
+	collectors.run("joined", func() error { return errors.Join(errors.New("second"), nil, errors.New("third")) })
+
+	errs := collectors.wait()
+	var messages []string
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	sort.Strings(messages)
+	if strings.Join(messages, ",") != "first,second,third" {
+		t.Errorf("Expected errors first, second and third, got %q", messages)
+	}
+	if p := peak.Load(); p > maxParallelCollectors {
+		t.Errorf("Expected at most %d concurrent steps, got %d", maxParallelCollectors, p)
+	}
+	if report := timer.report(); len(report) != 3*maxParallelCollectors+2 {
+		t.Errorf("Expected every step to be timed, got %d steps", len(report))
+	}
+}
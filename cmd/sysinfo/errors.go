@@ -0,0 +1,51 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysinfo
+
+import "errors"
+
+// Sentinel errors returned (usually wrapped) by the sysinfo command.
+// Callers should match them with errors.Is rather than comparing messages.
+var (
+	// ErrGPHOMENotSet indicates the GPHOME environment variable is not set.
+	ErrGPHOMENotSet = errors.New("GPHOME environment variable is not set")
+
+	// ErrGPHOMENotFound indicates GPHOME points to a directory that does not exist.
+	ErrGPHOMENotFound = errors.New("GPHOME directory does not exist")
+
+	// ErrInvalidFormat indicates an unsupported output format was requested.
+	ErrInvalidFormat = errors.New("invalid format")
+
+	// ErrInvalidUnits indicates an unsupported unit system was requested.
+	ErrInvalidUnits = errors.New("invalid units")
+
+	// ErrInvalidCollector indicates an unknown collector was given to --skip-collectors.
+	ErrInvalidCollector = errors.New("invalid collector")
+
+	// ErrCollectionFailed indicates one or more collectors failed.
+	ErrCollectionFailed = errors.New("errors occurred during system info collection")
+
+	// ErrClusterCollectionFailed indicates sysinfo could not be collected on some hosts.
+	ErrClusterCollectionFailed = errors.New("failed to collect system info on some hosts")
+)
+
+// messageError reads as msg while still matching err with errors.Is, for
+// errors whose established text differs from the sentinel's.
+type messageError struct {
+	msg string
+	err error
+}
+
+func (e *messageError) Error() string { return e.msg }
+
+func (e *messageError) Unwrap() error { return e.err }
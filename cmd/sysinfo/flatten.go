@@ -0,0 +1,67 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysinfo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/edespino/cbtoolbox/cmd/internal/partial"
+)
+
+// flatten converts v to a single-level map keyed by dotted JSON field
+// paths, e.g. "memory_stats.MemTotal". List elements are keyed by their
+// index, e.g. "warnings.0". Empty lists and maps have no values and are
+// left out. Numbers are kept as json.Number so they print unchanged.
+// Fields that cannot be marshaled are left out and returned as failures.
+func flatten(v any) (map[string]any, []string, error) {
+	data, failures, err := partial.JSON(v, "")
+	if err != nil {
+		return nil, nil, fmt.Errorf("flatten: failed to marshal: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var tree any
+	if err := decoder.Decode(&tree); err != nil {
+		return nil, nil, fmt.Errorf("flatten: failed to decode: %w", err)
+	}
+
+	flat := make(map[string]any)
+	flattenInto(flat, "", tree)
+	return flat, failures, nil
+}
+
+// flattenInto adds the leaves of value to flat under prefix.
+func flattenInto(flat map[string]any, prefix string, value any) {
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + "." + key
+	}
+
+	switch value := value.(type) {
+	case map[string]any:
+		for key, child := range value {
+			flattenInto(flat, join(key), child)
+		}
+	case []any:
+		for i, child := range value {
+			flattenInto(flat, join(strconv.Itoa(i)), child)
+		}
+	default:
+		flat[prefix] = value
+	}
+}
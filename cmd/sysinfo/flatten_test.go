@@ -0,0 +1,88 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysinfo
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// TestFlatten validates dotted keys for nested maps and structs, indexed
+// keys for lists, and that empty containers are left out.
+func TestFlatten(t *testing.T) {
+	info := SysInfo{
+		OS:          "linux",
+		CPUs:        16,
+		MemoryStats: map[string]string{"MemTotal": "61.6 GiB"},
+		CGroupLimits: &CGroupLimits{
+			Version:  "v2",
+			Warnings: []string{"cpu limit below host"},
+		},
+		KernelParameters: map[string]string{},
+		Warnings:         []string{"first", "second"},
+	}
+
+	flat, _, err := flatten(info)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]any{
+		"os":                       "linux",
+		"cpus":                     json.Number("16"),
+		"memory_stats.MemTotal":    "61.6 GiB",
+		"cgroup_limits.version":    "v2",
+		"cgroup_limits.warnings.0": "cpu limit below host",
+		"warnings.0":               "first",
+		"warnings.1":               "second",
+	}
+	for key, value := range expected {
+		if !reflect.DeepEqual(flat[key], value) {
+			t.Errorf("flat[%q] = %#v, expected %#v", key, flat[key], value)
+		}
+	}
+	for key, value := range flat {
+		if _, isMap := value.(map[string]any); isMap {
+			t.Errorf("expected only leaf values, got a map at %q", key)
+		}
+		if _, isList := value.([]any); isList {
+			t.Errorf("expected only leaf values, got a list at %q", key)
+		}
+	}
+	if _, ok := flat["kernel_parameters"]; ok {
+		t.Error("expected empty maps to be left out")
+	}
+}
+
+// TestMarshalOutputJSONFlat validates that json-flat is a single-line
+// object with dotted keys.
+func TestMarshalOutputJSONFlat(t *testing.T) {
+	output, err := marshalOutput(SysInfo{OS: "linux", MemoryStats: map[string]string{"MemTotal": "1.0 GiB"}}, "json-flat", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var flat map[string]any
+	if err := json.Unmarshal(output, &flat); err != nil {
+		t.Fatalf("expected a JSON object, got %q: %v", output, err)
+	}
+	if flat["memory_stats.MemTotal"] != "1.0 GiB" || flat["os"] != "linux" {
+		t.Errorf("unexpected output %s", output)
+	}
+	for _, b := range output {
+		if b == '\n' {
+			t.Fatalf("expected a single line, got %q", output)
+		}
+	}
+}
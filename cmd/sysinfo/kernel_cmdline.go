@@ -0,0 +1,91 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysinfo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// procCmdline specifies the path to the kernel command line
+var procCmdline = "/proc/cmdline"
+
+// splitKernelCmdline splits a kernel command line into its parameters.
+// Like the kernel, it allows double-quoted values containing spaces, e.g.
+// dyndbg="file foo.c +p", and strips the quotes.
+func splitKernelCmdline(cmdline string) []string {
+	var params []string
+	var current strings.Builder
+	inQuotes := false
+	for _, r := range cmdline {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+		case (r == ' ' || r == '\t' || r == '\n') && !inQuotes:
+			if current.Len() > 0 {
+				params = append(params, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		params = append(params, current.String())
+	}
+	return params
+}
+
+// parseKernelCmdline parses a kernel command line into a map of parameter
+// names to values. Flags without a value, such as "quiet", map to "".
+// When a parameter is repeated, the last value wins as it does for most
+// kernel parameters.
+func parseKernelCmdline(cmdline string) map[string]string {
+	params := make(map[string]string)
+	for _, param := range splitKernelCmdline(cmdline) {
+		name, value, _ := strings.Cut(param, "=")
+		params[name] = value
+	}
+	return params
+}
+
+// kernelCmdlineNotes flags boot parameters that affect Apache Cloudberry:
+// transparent huge pages should be disabled, isolated CPUs are not used by
+// postgres processes unless pinned, and boot-time huge page reservations
+// take memory away from the rest of the system.
+func kernelCmdlineNotes(params map[string]string) []string {
+	var notes []string
+	if thp, ok := params["transparent_hugepage"]; ok && thp != "never" {
+		notes = append(notes, fmt.Sprintf("transparent_hugepage=%s; Cloudberry recommends transparent_hugepage=never", thp))
+	}
+	if cpus, ok := params["isolcpus"]; ok {
+		notes = append(notes, fmt.Sprintf("isolcpus=%s removes CPUs from the scheduler; postgres processes do not run on them unless pinned", cpus))
+	}
+	if pages, ok := params["hugepages"]; ok {
+		notes = append(notes, fmt.Sprintf("hugepages=%s reserves huge pages at boot; this memory is unavailable to processes not using huge pages", pages))
+	}
+	return notes
+}
+
+// getKernelCmdline returns the kernel command line, its parsed parameters
+// and notes on parameters relevant to Apache Cloudberry.
+// Returns an error if /proc/cmdline cannot be read.
+func getKernelCmdline() (string, map[string]string, []string, error) {
+	content, err := readFile(procCmdline)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("kernel cmdline: failed to read %s: %w", procCmdline, err)
+	}
+	cmdline := strings.TrimSpace(string(content))
+	params := parseKernelCmdline(cmdline)
+	return cmdline, params, kernelCmdlineNotes(params), nil
+}
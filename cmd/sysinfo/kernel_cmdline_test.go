@@ -0,0 +1,93 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysinfo
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestParseKernelCmdline validates parameter splitting, quoted values and flags.
+func TestParseKernelCmdline(t *testing.T) {
+	cmdline := `BOOT_IMAGE=(hd0,gpt2)/vmlinuz-4.18.0 root=/dev/mapper/rl-root ro quiet dyndbg="file foo.c +p" isolcpus=2,3 root=/dev/sda1`
+	expected := map[string]string{
+		"BOOT_IMAGE": "(hd0,gpt2)/vmlinuz-4.18.0",
+		"root":       "/dev/sda1",
+		"ro":         "",
+		"quiet":      "",
+		"dyndbg":     "file foo.c +p",
+		"isolcpus":   "2,3",
+	}
+
+	if params := parseKernelCmdline(cmdline); !reflect.DeepEqual(params, expected) {
+		t.Errorf("parseKernelCmdline() = %v, expected %v", params, expected)
+	}
+}
+
+// TestKernelCmdlineNotes validates flagging of Cloudberry-related boot parameters.
+func TestKernelCmdlineNotes(t *testing.T) {
+	tests := []struct {
+		name     string
+		cmdline  string
+		expected []string
+	}{
+		{name: "no relevant parameters", cmdline: "ro quiet"},
+		{name: "THP disabled", cmdline: "ro transparent_hugepage=never"},
+		{name: "THP enabled", cmdline: "transparent_hugepage=always", expected: []string{"transparent_hugepage=always"}},
+		{name: "isolated CPUs and huge pages", cmdline: "isolcpus=1-3 hugepages=1024", expected: []string{"isolcpus=1-3", "hugepages=1024"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notes := kernelCmdlineNotes(parseKernelCmdline(tt.cmdline))
+			if len(notes) != len(tt.expected) {
+				t.Fatalf("Expected %d notes, got %v", len(tt.expected), notes)
+			}
+			for i, want := range tt.expected {
+				if !strings.HasPrefix(notes[i], want) {
+					t.Errorf("Expected note %d to start with %q, got %q", i, want, notes[i])
+				}
+			}
+		})
+	}
+}
+
+// TestGetKernelCmdline validates reading the kernel command line from a mock path.
+func TestGetKernelCmdline(t *testing.T) {
+	originalPath := procCmdline
+	defer func() { procCmdline = originalPath }()
+
+	procCmdline = filepath.Join(t.TempDir(), "cmdline")
+	if err := os.WriteFile(procCmdline, []byte("ro transparent_hugepage=madvise\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmdline, params, notes, err := getKernelCmdline()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cmdline != "ro transparent_hugepage=madvise" {
+		t.Errorf("Expected trimmed command line, got %q", cmdline)
+	}
+	if params["transparent_hugepage"] != "madvise" || len(notes) != 1 {
+		t.Errorf("Unexpected parameters %v or notes %v", params, notes)
+	}
+
+	procCmdline = filepath.Join(t.TempDir(), "missing")
+	if _, _, _, err := getKernelCmdline(); err == nil || !strings.HasPrefix(err.Error(), "kernel cmdline:") {
+		t.Errorf("Expected a kernel cmdline error for a missing file, got: %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysinfo
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// procSysVM specifies the directory of the vm.* sysctls.
+var procSysVM = "/proc/sys/vm"
+
+// KernelTuning reports the vm.* sysctls that affect database performance,
+// grouped by the subsystem they tune and keyed by sysctl name. Values
+// outside the ranges Cloudberry recommends are flagged with a warning.
+type KernelTuning struct {
+	Overcommit map[string]string `json:"overcommit,omitempty" yaml:"overcommit,omitempty"`
+	Swap       map[string]string `json:"swap,omitempty" yaml:"swap,omitempty"`
+	Writeback  map[string]string `json:"writeback,omitempty" yaml:"writeback,omitempty"`
+	Warnings   []string          `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+}
+
+// sysctlRange is the recommended range of a numeric sysctl.
+type sysctlRange struct {
+	min, max int64
+	advice   string
+}
+
+// kernelTuningRanges are the recommended ranges of the reported sysctls,
+// following the Cloudberry sysctl.conf recommendations. Sysctls without
+// an entry, such as vm.overcommit_ratio which depends on the memory size,
+// are reported but not checked.
+var kernelTuningRanges = map[string]sysctlRange{
+	"vm.overcommit_memory":      {2, 2, "set 2 so the kernel refuses allocations instead of invoking the OOM killer"},
+	"vm.swappiness":             {0, 10, "set 10 or lower to keep shared buffers in memory"},
+	"vm.dirty_ratio":            {1, 10, "set 10 or lower to avoid long writeback stalls"},
+	"vm.dirty_background_ratio": {1, 3, "set 3 or lower to start background writeback early"},
+	"vm.dirty_bytes":            {1, 4 << 30, "set 4 GiB or lower to avoid long writeback stalls"},
+	"vm.dirty_background_bytes": {1, 1610612736, "set 1.5 GiB or lower to start background writeback early"},
+}
+
+// readSysctl returns the trimmed value of a vm.* sysctl.
+func readSysctl(name string) (string, error) {
+	content, err := readFile(filepath.Join(procSysVM, strings.TrimPrefix(name, "vm.")))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(content)), nil
+}
+
+// getKernelTuning collects the overcommit, swap and writeback sysctls.
+// The *_bytes writeback sysctls replace their *_ratio counterparts when
+// set, so only the pair in effect is reported and checked.
+// Returns nil if none of the sysctls are readable.
+func getKernelTuning() *KernelTuning {
+	tuning := &KernelTuning{}
+	readable := false
+	read := func(group *map[string]string, name string) string {
+		value, err := readSysctl(name)
+		if err != nil {
+			return ""
+		}
+		readable = true
+		if *group == nil {
+			*group = make(map[string]string)
+		}
+		(*group)[name] = value
+		tuning.check(name, value)
+		return value
+	}
+
+	read(&tuning.Overcommit, "vm.overcommit_memory")
+	read(&tuning.Overcommit, "vm.overcommit_ratio")
+	read(&tuning.Swap, "vm.swappiness")
+	for _, pair := range [][2]string{
+		{"vm.dirty_ratio", "vm.dirty_bytes"},
+		{"vm.dirty_background_ratio", "vm.dirty_background_bytes"},
+	} {
+		if bytes, err := readSysctl(pair[1]); err == nil && bytes != "0" {
+			read(&tuning.Writeback, pair[1])
+		} else {
+			read(&tuning.Writeback, pair[0])
+		}
+	}
+
+	if !readable {
+		return nil
+	}
+	return tuning
+}
+
+// check flags value if it lies outside the recommended range of name.
+func (t *KernelTuning) check(name, value string) {
+	limits, ok := kernelTuningRanges[name]
+	if !ok {
+		return
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		t.Warnings = append(t.Warnings, fmt.Sprintf("%s: unexpected value %q", name, value))
+		return
+	}
+	if n < limits.min || n > limits.max {
+		t.Warnings = append(t.Warnings, fmt.Sprintf("%s = %d is outside the recommended range; %s", name, n, limits.advice))
+	}
+}
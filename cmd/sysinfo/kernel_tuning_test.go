@@ -0,0 +1,127 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysinfo
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeSysctls creates a mock /proc/sys/vm directory with the given vm.*
+// sysctls.
+func writeSysctls(t *testing.T, sysctls map[string]string) {
+	t.Helper()
+	original := procSysVM
+	t.Cleanup(func() { procSysVM = original })
+
+	procSysVM = t.TempDir()
+	for name, value := range sysctls {
+		path := filepath.Join(procSysVM, strings.TrimPrefix(name, "vm."))
+		if err := os.WriteFile(path, []byte(value+"\n"), 0644); err != nil {
+			t.Fatalf("Failed to write mock %s: %v", name, err)
+		}
+	}
+}
+
+// TestGetKernelTuning validates sysctl grouping, the choice between the
+// ratio and bytes writeback sysctls, and range warnings.
+func TestGetKernelTuning(t *testing.T) {
+	recommended := map[string]string{
+		"vm.overcommit_memory":      "2",
+		"vm.overcommit_ratio":       "95",
+		"vm.swappiness":             "10",
+		"vm.dirty_ratio":            "10",
+		"vm.dirty_background_ratio": "3",
+		"vm.dirty_bytes":            "0",
+		"vm.dirty_background_bytes": "0",
+	}
+	with := func(overrides map[string]string) map[string]string {
+		sysctls := make(map[string]string)
+		for name, value := range recommended {
+			sysctls[name] = value
+		}
+		for name, value := range overrides {
+			sysctls[name] = value
+		}
+		return sysctls
+	}
+
+	tests := []struct {
+		name      string
+		sysctls   map[string]string
+		writeback map[string]string
+		warnings  []string
+		expectNil bool
+	}{
+		{
+			name:      "recommended",
+			sysctls:   recommended,
+			writeback: map[string]string{"vm.dirty_ratio": "10", "vm.dirty_background_ratio": "3"},
+		},
+		{
+			name:      "kernel defaults",
+			sysctls:   with(map[string]string{"vm.overcommit_memory": "0", "vm.swappiness": "60", "vm.dirty_ratio": "20", "vm.dirty_background_ratio": "10"}),
+			writeback: map[string]string{"vm.dirty_ratio": "20", "vm.dirty_background_ratio": "10"},
+			warnings:  []string{"vm.overcommit_memory = 0", "vm.swappiness = 60", "vm.dirty_ratio = 20", "vm.dirty_background_ratio = 10"},
+		},
+		{
+			name:      "bytes replace ratios",
+			sysctls:   with(map[string]string{"vm.dirty_ratio": "0", "vm.dirty_background_ratio": "0", "vm.dirty_bytes": "4294967296", "vm.dirty_background_bytes": "1610612736"}),
+			writeback: map[string]string{"vm.dirty_bytes": "4294967296", "vm.dirty_background_bytes": "1610612736"},
+		},
+		{
+			name:      "missing sysctls are omitted",
+			sysctls:   map[string]string{"vm.swappiness": "1"},
+			writeback: nil,
+		},
+		{
+			name:      "unreadable",
+			sysctls:   nil,
+			expectNil: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeSysctls(t, tt.sysctls)
+
+			tuning := getKernelTuning()
+			if tt.expectNil {
+				if tuning != nil {
+					t.Fatalf("expected nil, got %+v", tuning)
+				}
+				return
+			}
+			if tuning == nil {
+				t.Fatal("expected kernel tuning, got nil")
+			}
+			if tuning.Swap["vm.swappiness"] != tt.sysctls["vm.swappiness"] {
+				t.Errorf("unexpected swap group %v", tuning.Swap)
+			}
+			if !reflect.DeepEqual(tuning.Writeback, tt.writeback) {
+				t.Errorf("Writeback = %v, expected %v", tuning.Writeback, tt.writeback)
+			}
+			if len(tuning.Warnings) != len(tt.warnings) {
+				t.Fatalf("Warnings = %q, expected %d warnings", tuning.Warnings, len(tt.warnings))
+			}
+			for i, prefix := range tt.warnings {
+				if !strings.HasPrefix(tuning.Warnings[i], prefix) {
+					t.Errorf("warning %d = %q, expected prefix %q", i, tuning.Warnings[i], prefix)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysinfo
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Libraries reports the glibc version of the host and, with
+// --linked-libraries, the shared libraries the GPHOME postgres binary
+// resolves to. Cloudberry binaries are sensitive to library versions, so
+// differences between hosts explain crashes seen on only some of them.
+//
+// Linked maps each library soname to the file it resolves to after
+// following symlinks, which usually carries the full version, or to
+// "not found". Notes lists libraries that could not be resolved.
+type Libraries struct {
+	Glibc  string            `json:"glibc,omitempty" yaml:"glibc,omitempty"`
+	Linked map[string]string `json:"linked,omitempty" yaml:"linked,omitempty"`
+	Notes  []string          `json:"notes,omitempty" yaml:"notes,omitempty"`
+}
+
+// getGlibcVersion returns the glibc version, e.g. "2.28", from
+// 'getconf GNU_LIBC_VERSION', falling back to 'ldd --version'.
+func getGlibcVersion() (string, error) {
+	if output, err := runCommand("getconf", "GNU_LIBC_VERSION"); err == nil {
+		// e.g. "glibc 2.28"
+		if fields := strings.Fields(string(output)); len(fields) == 2 && fields[0] == "glibc" {
+			return fields[1], nil
+		}
+	}
+	output, err := runCommand("ldd", "--version")
+	if err != nil {
+		return "", fmt.Errorf("libraries: failed to determine glibc version: %w", err)
+	}
+	// e.g. "ldd (GNU libc) 2.28" or "ldd (Ubuntu GLIBC 2.35-0ubuntu3) 2.35";
+	// other C libraries such as musl are not reported
+	firstLine, _, _ := strings.Cut(string(output), "\n")
+	fields := strings.Fields(firstLine)
+	if len(fields) == 0 || !(strings.Contains(firstLine, "GNU libc") || strings.Contains(firstLine, "GLIBC")) {
+		return "", fmt.Errorf("libraries: unrecognized ldd --version output: %q", firstLine)
+	}
+	return fields[len(fields)-1], nil
+}
+
+// parseLdd parses 'ldd <binary>' output into a map of library soname to
+// resolved path, or "not found". Entries without a soname, such as the
+// vDSO and the dynamic loader, are skipped.
+func parseLdd(output string) map[string]string {
+	libraries := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		name, target, found := strings.Cut(strings.TrimSpace(line), "=>")
+		if !found {
+			continue
+		}
+		name, target = strings.TrimSpace(name), strings.TrimSpace(target)
+		if target == "not found" {
+			libraries[name] = target
+			continue
+		}
+		// Drop the load address, e.g. "/lib64/libssl.so.1.1 (0x00007f...)"
+		path, _, _ := strings.Cut(target, " (")
+		if path == "" {
+			continue
+		}
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			path = resolved
+		}
+		libraries[name] = path
+	}
+	return libraries
+}
+
+// getLibraries collects the glibc version and, when linked is set and
+// gphome is not empty, the libraries the postgres binary under gphome links
+// against. Failures are returned alongside the information that could be
+// collected.
+func getLibraries(gphome string, linked bool) (*Libraries, []error) {
+	libs := &Libraries{}
+	var errs []error
+
+	if version, err := getGlibcVersion(); err == nil {
+		libs.Glibc = version
+	} else {
+		errs = append(errs, err)
+	}
+
+	if linked && gphome != "" {
+		postgresPath := filepath.Join(gphome, "bin", "postgres")
+		if output, err := runCommand("ldd", postgresPath); err == nil {
+			libs.Linked = parseLdd(string(output))
+			var missing []string
+			for name, path := range libs.Linked {
+				if path == "not found" {
+					missing = append(missing, name)
+				}
+			}
+			sort.Strings(missing)
+			for _, name := range missing {
+				libs.Notes = append(libs.Notes, fmt.Sprintf("%s required by %s was not found; postgres will fail to start", name, postgresPath))
+			}
+		} else {
+			errs = append(errs, fmt.Errorf("libraries: ldd %s failed: %w", postgresPath, err))
+		}
+	}
+	return libs, errs
+}
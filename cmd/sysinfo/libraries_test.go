@@ -0,0 +1,112 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysinfo
+
+import (
+	"reflect"
+	"testing"
+)
+
+// sampleLdd is 'ldd' output for a postgres binary with a missing library.
+const sampleLdd = `	linux-vdso.so.1 (0x00007ffd5b3f2000)
+	libssl.so.1.1 => /lib64/libssl.so.1.1 (0x00007f2a1b000000)
+	libz.so.1 => /lib64/libz.so.1 (0x00007f2a1a000000)
+	libxerces-c-3.2.so => not found
+	libc.so.6 => /lib64/libc.so.6 (0x00007f2a19000000)
+	/lib64/ld-linux-x86-64.so.2 (0x00007f2a1c000000)
+`
+
+// TestGetGlibcVersion validates the glibc version sources and fallback.
+func TestGetGlibcVersion(t *testing.T) {
+	tests := []struct {
+		name      string
+		outputs   map[string]string
+		expected  string
+		expectErr bool
+	}{
+		{
+			name:     "getconf",
+			outputs:  map[string]string{"getconf GNU_LIBC_VERSION": "glibc 2.28\n"},
+			expected: "2.28",
+		},
+		{
+			name:     "ldd fallback",
+			outputs:  map[string]string{"ldd --version": "ldd (GNU libc) 2.34\nCopyright (C) 2021 Free Software Foundation, Inc.\n"},
+			expected: "2.34",
+		},
+		{
+			name:      "musl",
+			outputs:   map[string]string{"ldd --version": "musl libc (x86_64)\nVersion 1.2.4\n"},
+			expectErr: true,
+		},
+		{
+			name:     "ubuntu ldd",
+			outputs:  map[string]string{"ldd --version": "ldd (Ubuntu GLIBC 2.35-0ubuntu3.8) 2.35\n"},
+			expected: "2.35",
+		},
+		{
+			name:      "unavailable",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCommands(t, tt.outputs)
+			got, err := getGlibcVersion()
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("getGlibcVersion() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if got != tt.expected {
+				t.Errorf("getGlibcVersion() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestGetLibraries validates linked library collection and that ldd only
+// runs when requested.
+func TestGetLibraries(t *testing.T) {
+	mockCommands(t, map[string]string{
+		"getconf GNU_LIBC_VERSION":   "glibc 2.28\n",
+		"ldd /opt/cbdb/bin/postgres": sampleLdd,
+	})
+
+	libs, errs := getLibraries("/opt/cbdb", false)
+	if len(errs) > 0 || libs.Glibc != "2.28" || libs.Linked != nil {
+		t.Errorf("without linked libraries: %+v, %v", libs, errs)
+	}
+
+	libs, errs = getLibraries("/opt/cbdb", true)
+	if len(errs) > 0 {
+		t.Fatalf("getLibraries() errors = %v", errs)
+	}
+	expected := map[string]string{
+		"libssl.so.1.1":      "/lib64/libssl.so.1.1",
+		"libz.so.1":          "/lib64/libz.so.1",
+		"libxerces-c-3.2.so": "not found",
+		"libc.so.6":          "/lib64/libc.so.6",
+	}
+	if !reflect.DeepEqual(libs.Linked, expected) {
+		t.Errorf("Linked = %v, expected %v", libs.Linked, expected)
+	}
+	if len(libs.Notes) != 1 {
+		t.Errorf("expected a note for the missing library, got %v", libs.Notes)
+	}
+
+	// A failed ldd is reported without losing the glibc version
+	libs, errs = getLibraries("/missing", true)
+	if len(errs) != 1 || libs.Glibc != "2.28" {
+		t.Errorf("failed ldd: %+v, %v", libs, errs)
+	}
+}
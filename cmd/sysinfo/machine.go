@@ -0,0 +1,106 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysinfo
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Paths of the machine identity sources, variables so tests can mock them.
+var (
+	machineIDPath      = "/etc/machine-id"
+	dmiProductNamePath = "/sys/class/dmi/id/product_name"
+	dmiSysVendorPath   = "/sys/class/dmi/id/sys_vendor"
+)
+
+// dmiHypervisors maps substrings of the DMI product name or vendor to the
+// hypervisor names systemd-detect-virt uses, checked in order.
+var dmiHypervisors = []struct {
+	marker     string
+	hypervisor string
+}{
+	{"KVM", "kvm"},
+	{"QEMU", "qemu"},
+	{"VMware", "vmware"},
+	{"VirtualBox", "oracle"},
+	{"Virtual Machine", "microsoft"},
+	{"HVM domU", "xen"},
+	{"Xen", "xen"},
+	{"Google Compute Engine", "google"},
+	{"Amazon EC2", "amazon"},
+	{"OpenStack", "kvm"},
+}
+
+// getMachineID returns the systemd machine ID of the host.
+// Returns an error if /etc/machine-id is missing or empty.
+func getMachineID() (string, error) {
+	content, err := readFile(machineIDPath)
+	if err != nil {
+		return "", fmt.Errorf("machine-id: failed to read file: %w", err)
+	}
+	id := strings.TrimSpace(string(content))
+	if id == "" {
+		return "", fmt.Errorf("machine-id: %s is empty", machineIDPath)
+	}
+	return id, nil
+}
+
+// detectVirt returns what systemd-detect-virt prints for mode (--vm or
+// --container). It exits non-zero and prints "none" when nothing is
+// detected, so only a failure to run it is an error.
+func detectVirt(mode string) (string, error) {
+	output, err := runCommand("systemd-detect-virt", mode)
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// getVirtualization reports the deployment environment of the host as
+// "bare metal", "vm (<hypervisor>)" or "container (<runtime>)", e.g.
+// "vm (kvm)". It asks systemd-detect-virt, and without it matches the DMI
+// product name and vendor against known hypervisors; hardware DMI data
+// means bare metal. Containers are only detected by systemd-detect-virt.
+// Returns an error if neither source is available.
+func getVirtualization() (string, error) {
+	if container, err := detectVirt("--container"); err == nil {
+		if container != "" && container != "none" {
+			return fmt.Sprintf("container (%s)", container), nil
+		}
+		if vm, err := detectVirt("--vm"); err == nil {
+			if vm != "" && vm != "none" {
+				return fmt.Sprintf("vm (%s)", vm), nil
+			}
+			return "bare metal", nil
+		}
+	}
+
+	product, err := readFile(dmiProductNamePath)
+	if err != nil {
+		return "", fmt.Errorf("virtualization: systemd-detect-virt not available and failed to read DMI: %w", err)
+	}
+	dmi := strings.TrimSpace(string(product))
+	if vendor, err := readFile(dmiSysVendorPath); err == nil {
+		dmi += " " + strings.TrimSpace(string(vendor))
+	}
+	for _, h := range dmiHypervisors {
+		if strings.Contains(dmi, h.marker) {
+			return fmt.Sprintf("vm (%s)", h.hypervisor), nil
+		}
+	}
+	return "bare metal", nil
+}
@@ -0,0 +1,124 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysinfo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestGetMachineID validates reading the machine ID and its failure modes.
+func TestGetMachineID(t *testing.T) {
+	original := machineIDPath
+	t.Cleanup(func() { machineIDPath = original })
+	dir := t.TempDir()
+
+	machineIDPath = filepath.Join(dir, "machine-id")
+	if err := os.WriteFile(machineIDPath, []byte("4f2a9c1e8b7d4e6fa1c3b5d7e9f01234\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if id, err := getMachineID(); err != nil || id != "4f2a9c1e8b7d4e6fa1c3b5d7e9f01234" {
+		t.Errorf("getMachineID() = %q, %v", id, err)
+	}
+
+	if err := os.WriteFile(machineIDPath, []byte("\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := getMachineID(); err == nil {
+		t.Errorf("Expected error for empty machine-id")
+	}
+
+	machineIDPath = filepath.Join(dir, "missing")
+	if _, err := getMachineID(); err == nil {
+		t.Errorf("Expected error for missing machine-id")
+	}
+}
+
+// TestGetVirtualization validates detection via systemd-detect-virt and the
+// DMI fallback.
+func TestGetVirtualization(t *testing.T) {
+	tests := []struct {
+		name      string
+		outputs   map[string]string
+		product   string
+		vendor    string
+		expected  string
+		expectErr bool
+	}{
+		{
+			name:     "bare metal",
+			outputs:  map[string]string{"systemd-detect-virt --container": "none\n", "systemd-detect-virt --vm": "none\n"},
+			expected: "bare metal",
+		},
+		{
+			name:     "virtual machine",
+			outputs:  map[string]string{"systemd-detect-virt --container": "none\n", "systemd-detect-virt --vm": "kvm\n"},
+			expected: "vm (kvm)",
+		},
+		{
+			name:     "container",
+			outputs:  map[string]string{"systemd-detect-virt --container": "docker\n"},
+			expected: "container (docker)",
+		},
+		{
+			name:     "DMI hypervisor",
+			product:  "VMware Virtual Platform",
+			vendor:   "VMware, Inc.",
+			expected: "vm (vmware)",
+		},
+		{
+			name:     "DMI vendor",
+			product:  "m5.2xlarge",
+			vendor:   "Amazon EC2",
+			expected: "vm (amazon)",
+		},
+		{
+			name:     "DMI hardware",
+			product:  "PowerEdge R750",
+			vendor:   "Dell Inc.",
+			expected: "bare metal",
+		},
+		{
+			name:      "no source",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			originalProduct, originalVendor := dmiProductNamePath, dmiSysVendorPath
+			t.Cleanup(func() { dmiProductNamePath, dmiSysVendorPath = originalProduct, originalVendor })
+			dir := t.TempDir()
+			dmiProductNamePath = filepath.Join(dir, "product_name")
+			dmiSysVendorPath = filepath.Join(dir, "sys_vendor")
+			if tt.product != "" {
+				if err := os.WriteFile(dmiProductNamePath, []byte(tt.product+"\n"), 0644); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(dmiSysVendorPath, []byte(tt.vendor+"\n"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			mockCommands(t, tt.outputs)
+
+			virtualization, err := getVirtualization()
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("getVirtualization() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if virtualization != tt.expected {
+				t.Errorf("getVirtualization() = %q, expected %q", virtualization, tt.expected)
+			}
+		})
+	}
+}
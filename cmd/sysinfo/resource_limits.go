@@ -0,0 +1,116 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysinfo
+
+import (
+	"fmt"
+	"strconv"
+	"syscall"
+)
+
+// The syscall package does not define RLIMIT_NPROC and RLIMIT_MEMLOCK;
+// these are their values on Linux.
+const (
+	rlimitNproc   = 6
+	rlimitMemlock = 8
+)
+
+// rlimitInfinity is RLIM_INFINITY, reported as "unlimited".
+const rlimitInfinity = ^uint64(0)
+
+// getrlimit reads a resource limit of the current process; tests replace it.
+var getrlimit = syscall.Getrlimit
+
+// ResourceLimit is the soft and hard value of a resource limit. Byte
+// valued limits are rendered in the selected units.
+type ResourceLimit struct {
+	Soft string `json:"soft" yaml:"soft"`
+	Hard string `json:"hard" yaml:"hard"`
+}
+
+// ResourceLimits reports the resource limits (ulimits) of the sysinfo
+// session, which a database started from the same session inherits.
+// Soft limits below the Cloudberry recommendations are flagged.
+type ResourceLimits struct {
+	Core     *ResourceLimit `json:"core,omitempty" yaml:"core,omitempty"`
+	NoFile   *ResourceLimit `json:"nofile,omitempty" yaml:"nofile,omitempty"`
+	NProc    *ResourceLimit `json:"nproc,omitempty" yaml:"nproc,omitempty"`
+	MemLock  *ResourceLimit `json:"memlock,omitempty" yaml:"memlock,omitempty"`
+	Stack    *ResourceLimit `json:"stack,omitempty" yaml:"stack,omitempty"`
+	Warnings []string       `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+}
+
+// resourceLimitSpec describes how a resource limit is read and checked.
+type resourceLimitSpec struct {
+	name     string
+	resource int
+	bytes    bool
+	field    func(*ResourceLimits) **ResourceLimit
+	// minimum is the recommended minimum soft limit; 0 means unchecked
+	// and rlimitInfinity requires "unlimited"
+	minimum uint64
+	advice  string
+}
+
+// resourceLimitSpecs are the reported limits, with the minimums from the
+// Cloudberry /etc/security/limits.conf recommendations.
+var resourceLimitSpecs = []resourceLimitSpec{
+	{"core", syscall.RLIMIT_CORE, true, func(l *ResourceLimits) **ResourceLimit { return &l.Core }, rlimitInfinity,
+		"crash cores may be truncated or not written; set 'core unlimited'"},
+	{"nofile", syscall.RLIMIT_NOFILE, false, func(l *ResourceLimits) **ResourceLimit { return &l.NoFile }, 524288,
+		"set 'nofile 524288' to avoid running out of file descriptors"},
+	{"nproc", rlimitNproc, false, func(l *ResourceLimits) **ResourceLimit { return &l.NProc }, 131072,
+		"set 'nproc 131072' so segments can start their backends"},
+	{"memlock", rlimitMemlock, true, func(l *ResourceLimits) **ResourceLimit { return &l.MemLock }, 0, ""},
+	{"stack", syscall.RLIMIT_STACK, true, func(l *ResourceLimits) **ResourceLimit { return &l.Stack }, 8 << 20,
+		"set 'stack 8192' or more to support the default max_stack_depth"},
+}
+
+// formatRlimit renders a limit value, in the given units for byte limits.
+func formatRlimit(value uint64, bytes bool, units string) string {
+	switch {
+	case value == rlimitInfinity:
+		return "unlimited"
+	case bytes:
+		return formatSize(strconv.FormatUint(value/1024, 10), units)
+	default:
+		return strconv.FormatUint(value, 10)
+	}
+}
+
+// getResourceLimits collects the core, nofile, nproc, memlock and stack
+// limits of the current process, rendering byte limits in the given units.
+// Returns nil if none of the limits are readable.
+func getResourceLimits(units string) *ResourceLimits {
+	limits := &ResourceLimits{}
+	readable := false
+	for _, spec := range resourceLimitSpecs {
+		var rlimit syscall.Rlimit
+		if err := getrlimit(spec.resource, &rlimit); err != nil {
+			continue
+		}
+		readable = true
+		*spec.field(limits) = &ResourceLimit{
+			Soft: formatRlimit(rlimit.Cur, spec.bytes, units),
+			Hard: formatRlimit(rlimit.Max, spec.bytes, units),
+		}
+		if spec.minimum > 0 && rlimit.Cur != rlimitInfinity && rlimit.Cur < spec.minimum {
+			limits.Warnings = append(limits.Warnings, fmt.Sprintf("%s soft limit of %s is below the recommended %s; %s",
+				spec.name, formatRlimit(rlimit.Cur, spec.bytes, units), formatRlimit(spec.minimum, spec.bytes, units), spec.advice))
+		}
+	}
+	if !readable {
+		return nil
+	}
+	return limits
+}
@@ -0,0 +1,106 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysinfo
+
+import (
+	"errors"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+// mockRlimits replaces getrlimit with one returning the given limits;
+// resources without an entry fail.
+func mockRlimits(t *testing.T, rlimits map[int]syscall.Rlimit) {
+	t.Helper()
+	original := getrlimit
+	t.Cleanup(func() { getrlimit = original })
+	getrlimit = func(resource int, rlimit *syscall.Rlimit) error {
+		value, ok := rlimits[resource]
+		if !ok {
+			return errors.New("operation not permitted")
+		}
+		*rlimit = value
+		return nil
+	}
+}
+
+// TestGetResourceLimits validates limit rendering and the warnings for
+// limits below the recommendations.
+func TestGetResourceLimits(t *testing.T) {
+	recommended := map[int]syscall.Rlimit{
+		syscall.RLIMIT_CORE:   {Cur: rlimitInfinity, Max: rlimitInfinity},
+		syscall.RLIMIT_NOFILE: {Cur: 524288, Max: 524288},
+		rlimitNproc:           {Cur: 131072, Max: 131072},
+		rlimitMemlock:         {Cur: 64 << 10, Max: 64 << 10},
+		syscall.RLIMIT_STACK:  {Cur: 8 << 20, Max: rlimitInfinity},
+	}
+
+	t.Run("recommended", func(t *testing.T) {
+		mockRlimits(t, recommended)
+		limits := getResourceLimits(unitsBinary)
+		if limits == nil {
+			t.Fatal("expected resource limits, got nil")
+		}
+		if *limits.Core != (ResourceLimit{Soft: "unlimited", Hard: "unlimited"}) {
+			t.Errorf("unexpected core limit %+v", limits.Core)
+		}
+		if *limits.NoFile != (ResourceLimit{Soft: "524288", Hard: "524288"}) {
+			t.Errorf("unexpected nofile limit %+v", limits.NoFile)
+		}
+		if *limits.MemLock != (ResourceLimit{Soft: "64 KiB", Hard: "64 KiB"}) {
+			t.Errorf("unexpected memlock limit %+v", limits.MemLock)
+		}
+		if *limits.Stack != (ResourceLimit{Soft: "8.0 MiB", Hard: "unlimited"}) {
+			t.Errorf("unexpected stack limit %+v", limits.Stack)
+		}
+		if len(limits.Warnings) != 0 {
+			t.Errorf("expected no warnings, got %q", limits.Warnings)
+		}
+	})
+
+	t.Run("distribution defaults", func(t *testing.T) {
+		mockRlimits(t, map[int]syscall.Rlimit{
+			syscall.RLIMIT_CORE:   {Cur: 0, Max: rlimitInfinity},
+			syscall.RLIMIT_NOFILE: {Cur: 1024, Max: 4096},
+			rlimitNproc:           {Cur: 4096, Max: 4096},
+			syscall.RLIMIT_STACK:  {Cur: 8 << 20, Max: rlimitInfinity},
+		})
+		limits := getResourceLimits(unitsRaw)
+		if limits == nil {
+			t.Fatal("expected resource limits, got nil")
+		}
+		if limits.MemLock != nil {
+			t.Errorf("expected an unreadable limit to be omitted, got %+v", limits.MemLock)
+		}
+		if limits.Stack.Soft != "8192 kB" {
+			t.Errorf("expected raw units, got %q", limits.Stack.Soft)
+		}
+		expected := []string{"core soft limit of 0 kB", "nofile soft limit of 1024", "nproc soft limit of 4096"}
+		if len(limits.Warnings) != len(expected) {
+			t.Fatalf("Warnings = %q, expected %d warnings", limits.Warnings, len(expected))
+		}
+		for i, prefix := range expected {
+			if !strings.HasPrefix(limits.Warnings[i], prefix) {
+				t.Errorf("warning %d = %q, expected prefix %q", i, limits.Warnings[i], prefix)
+			}
+		}
+	})
+
+	t.Run("unreadable", func(t *testing.T) {
+		mockRlimits(t, nil)
+		if limits := getResourceLimits(unitsBinary); limits != nil {
+			t.Errorf("expected nil, got %+v", limits)
+		}
+	})
+}
@@ -0,0 +1,49 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysinfo
+
+import (
+	"errors"
+	"syscall"
+	"time"
+)
+
+// defaultVersionRetries is how often a transiently failing postgres
+// version command is retried unless --version-retries says otherwise.
+const defaultVersionRetries = 2
+
+// retryDelay is the pause before the first retry; it doubles with each
+// further retry. It is a variable so tests do not have to wait.
+var retryDelay = 200 * time.Millisecond
+
+// isTransient reports whether err is a failure to start a command that
+// can succeed when tried again, such as fork failing with EAGAIN or
+// ENOMEM on a coordinator under heavy load. Missing or non-executable
+// binaries and commands that ran and exited non-zero are permanent.
+func isTransient(err error) bool {
+	return errors.Is(err, syscall.EAGAIN) || errors.Is(err, syscall.ENOMEM) || errors.Is(err, syscall.EINTR)
+}
+
+// retryTransient calls fn until it succeeds, fails permanently, or has
+// been retried retries times, and returns its last result.
+func retryTransient(retries int, fn func() (string, error)) (string, error) {
+	delay := retryDelay
+	for attempt := 0; ; attempt++ {
+		result, err := fn()
+		if err == nil || attempt >= retries || !isTransient(err) {
+			return result, err
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
@@ -0,0 +1,104 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysinfo
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+// flakyCommand mocks runCommand to fail with err the first failures
+// calls and succeed afterwards, and returns the call counter.
+func flakyCommand(t *testing.T, failures int, err error) *int {
+	t.Helper()
+	original, originalDelay := runCommand, retryDelay
+	t.Cleanup(func() { runCommand, retryDelay = original, originalDelay })
+	retryDelay = 0
+
+	calls := 0
+	runCommand = func(name string, args ...string) ([]byte, error) {
+		calls++
+		if calls <= failures {
+			return nil, &os.PathError{Op: "fork/exec", Path: name, Err: err}
+		}
+		return []byte("postgres (Cloudberry Database) 14.4\n"), nil
+	}
+	return &calls
+}
+
+// TestIsTransient validates the classification of command failures.
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&os.PathError{Op: "fork/exec", Path: "postgres", Err: syscall.EAGAIN}, true},
+		{&os.PathError{Op: "fork/exec", Path: "postgres", Err: syscall.ENOMEM}, true},
+		{&os.PathError{Op: "fork/exec", Path: "postgres", Err: syscall.ENOENT}, false},
+		{&os.PathError{Op: "fork/exec", Path: "postgres", Err: syscall.EACCES}, false},
+		{&exec.ExitError{}, false},
+		{errors.New("postgres: executable not found"), false},
+	}
+	for _, tt := range tests {
+		if got := isTransient(tt.err); got != tt.want {
+			t.Errorf("isTransient(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+// TestRetryTransientVersion validates that postgres version commands are
+// retried on transient failures only, and at most the configured times.
+func TestRetryTransientVersion(t *testing.T) {
+	gphome := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(gphome, "bin"), 0755); err != nil {
+		t.Fatalf("Failed to create bin directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(gphome, "bin", "postgres"), nil, 0755); err != nil {
+		t.Fatalf("Failed to create postgres: %v", err)
+	}
+	getVersion := func() (string, error) { return getPostgresVersion(gphome) }
+
+	tests := []struct {
+		name      string
+		failures  int
+		err       error
+		retries   int
+		wantCalls int
+		wantErr   bool
+	}{
+		{"succeeds after transient failures", 2, syscall.EAGAIN, 2, 3, false},
+		{"gives up after the retries", 3, syscall.EAGAIN, 2, 3, true},
+		{"no retries", 1, syscall.EAGAIN, 0, 1, true},
+		{"permanent failure is not retried", 1, syscall.ENOENT, 2, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := flakyCommand(t, tt.failures, tt.err)
+			version, err := retryTransient(tt.retries, getVersion)
+			if *calls != tt.wantCalls {
+				t.Errorf("Expected %d calls, got %d", tt.wantCalls, *calls)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, tt.err) {
+					t.Errorf("Expected error wrapping %v, got %v", tt.err, err)
+				}
+			} else if err != nil || version != "postgres (Cloudberry Database) 14.4" {
+				t.Errorf("Unexpected result %q, %v", version, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysinfo
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// procDir specifies the mount point of the proc filesystem, which is
+// scanned for running postgres processes.
+var procDir = "/proc"
+
+// connectionRegex matches the connection number in the process title of a
+// Cloudberry backend, e.g. "postgres:  7000, gpadmin db [local] con12 cmd3 idle".
+var connectionRegex = regexp.MustCompile(`\bcon\d+\b`)
+
+// RunningBackends counts the running processes of the GPHOME postgres
+// binary, broken down by role: "postmaster", "backend" for client
+// connections, or the auxiliary process name from the process title, such
+// as "checkpointer". Processes whose executable could not be inspected,
+// typically those of other users when not run as root, are counted
+// separately.
+type RunningBackends struct {
+	Count       int            `json:"count" yaml:"count"`
+	Roles       map[string]int `json:"roles,omitempty" yaml:"roles,omitempty"`
+	Uninspected int            `json:"uninspected,omitempty" yaml:"uninspected,omitempty"`
+}
+
+// processRole derives the role of a postgres process from its command line.
+// Child processes replace their command line with a title of the form
+// "postgres:  <port>, <role>"; the postmaster keeps its original arguments.
+func processRole(cmdline string) string {
+	title, found := strings.CutPrefix(strings.TrimSpace(cmdline), "postgres:")
+	if !found {
+		return "postmaster"
+	}
+	title = strings.TrimSpace(title)
+	// Cloudberry prefixes the title with the port
+	if port, rest, found := strings.Cut(title, ","); found {
+		if _, err := strconv.Atoi(strings.TrimSpace(port)); err == nil {
+			title = strings.TrimSpace(rest)
+		}
+	}
+	if connectionRegex.MatchString(title) {
+		return "backend"
+	}
+	if fields := strings.Fields(title); len(fields) > 0 && (fields[0] == "walsender" || fields[0] == "walreceiver") {
+		return fields[0]
+	}
+	title = strings.TrimSuffix(title, " process")
+	if title == "" {
+		return "unknown"
+	}
+	return title
+}
+
+// getRunningBackends scans procDir for processes whose executable is the
+// postgres binary under gphome and counts them by role.
+// Returns an error if procDir cannot be read, e.g. outside Linux.
+func getRunningBackends(gphome string) (*RunningBackends, error) {
+	entries, err := os.ReadDir(procDir)
+	if err != nil {
+		return nil, fmt.Errorf("running backends: %s not available: %w", procDir, err)
+	}
+
+	postgresPath := filepath.Join(gphome, "bin", "postgres")
+	if resolved, err := filepath.EvalSymlinks(postgresPath); err == nil {
+		postgresPath = resolved
+	}
+
+	backends := &RunningBackends{Roles: make(map[string]int)}
+	for _, entry := range entries {
+		if _, err := strconv.Atoi(entry.Name()); err != nil {
+			continue
+		}
+		exe, err := os.Readlink(filepath.Join(procDir, entry.Name(), "exe"))
+		if err != nil {
+			// Processes of other users cannot be inspected without root
+			if errors.Is(err, fs.ErrPermission) {
+				backends.Uninspected++
+			}
+			continue
+		}
+		// The link is marked when the binary was replaced by an upgrade
+		if strings.TrimSuffix(exe, " (deleted)") != postgresPath {
+			continue
+		}
+		cmdline, err := readFile(filepath.Join(procDir, entry.Name(), "cmdline"))
+		if err != nil {
+			// The process exited while scanning
+			continue
+		}
+		backends.Count++
+		backends.Roles[processRole(strings.ReplaceAll(string(cmdline), "\x00", " "))]++
+	}
+	return backends, nil
+}
@@ -0,0 +1,111 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysinfo
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestProcessRole validates deriving process roles from command lines.
+func TestProcessRole(t *testing.T) {
+	tests := []struct {
+		cmdline  string
+		expected string
+	}{
+		{cmdline: "/usr/local/cloudberry-db/bin/postgres -D /data/coordinator/gpseg-1 -p 7000 ", expected: "postmaster"},
+		{cmdline: "postgres:  7000, gpadmin postgres [local] con12 cmd3 idle", expected: "backend"},
+		{cmdline: "postgres:  7000, checkpointer   ", expected: "checkpointer"},
+		{cmdline: "postgres:  7000, background writer", expected: "background writer"},
+		{cmdline: "postgres: checkpointer process", expected: "checkpointer"},
+		{cmdline: "postgres:  6000, walsender gpadmin 10.0.0.2(41234) streaming 0/3000148", expected: "walsender"},
+		{cmdline: "postgres:", expected: "unknown"},
+	}
+
+	for _, tt := range tests {
+		if got := processRole(tt.cmdline); got != tt.expected {
+			t.Errorf("processRole(%q) = %q, expected %q", tt.cmdline, got, tt.expected)
+		}
+	}
+}
+
+// TestGetRunningBackends validates counting postgres processes in a mock
+// proc filesystem.
+func TestGetRunningBackends(t *testing.T) {
+	gphome := t.TempDir()
+	postgres := filepath.Join(gphome, "bin", "postgres")
+	if err := os.MkdirAll(filepath.Dir(postgres), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(postgres, nil, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	originalProcDir := procDir
+	t.Cleanup(func() { procDir = originalProcDir })
+	procDir = t.TempDir()
+
+	processes := []struct {
+		pid     string
+		exe     string
+		cmdline string
+	}{
+		{pid: "100", exe: postgres, cmdline: postgres + "\x00-D\x00/data/gpseg-1\x00"},
+		{pid: "101", exe: postgres, cmdline: "postgres:  7000, checkpointer   "},
+		{pid: "102", exe: postgres + " (deleted)", cmdline: "postgres:  7000, gpadmin db [local] con5 cmd1 idle"},
+		{pid: "103", exe: postgres, cmdline: "postgres:  7000, gpadmin db 10.0.0.2(5432) con6 cmd2 SELECT"},
+		{pid: "200", exe: "/usr/bin/bash", cmdline: "bash\x00"},
+	}
+	for _, p := range processes {
+		dir := filepath.Join(procDir, p.pid)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(p.exe, filepath.Join(dir, "exe")); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "cmdline"), []byte(p.cmdline), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Non-process entries are ignored
+	if err := os.WriteFile(filepath.Join(procDir, "meminfo"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	backends, err := getRunningBackends(gphome)
+	if err != nil {
+		t.Fatalf("getRunningBackends() error = %v", err)
+	}
+	expected := &RunningBackends{
+		Count: 4,
+		Roles: map[string]int{"postmaster": 1, "checkpointer": 1, "backend": 2},
+	}
+	if !reflect.DeepEqual(backends, expected) {
+		t.Errorf("getRunningBackends() = %+v, expected %+v", backends, expected)
+	}
+}
+
+// TestGetRunningBackendsNoProc validates that a missing proc filesystem is
+// reported as an error rather than as zero processes.
+func TestGetRunningBackendsNoProc(t *testing.T) {
+	originalProcDir := procDir
+	t.Cleanup(func() { procDir = originalProcDir })
+	procDir = filepath.Join(t.TempDir(), "missing")
+
+	if backends, err := getRunningBackends(t.TempDir()); err == nil {
+		t.Errorf("expected error, got %+v", backends)
+	}
+}
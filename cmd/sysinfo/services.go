@@ -0,0 +1,67 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysinfo
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// systemdRuntimeDir exists only when the host was booted with systemd.
+var systemdRuntimeDir = "/run/systemd/system"
+
+// defaultServices are the systemd units checked when --services is not given.
+var defaultServices = []string{"cloudberry", "cloudberrydb", "greenplum"}
+
+// systemctlState returns the state systemctl prints for a query such as
+// is-active. systemctl exits non-zero for inactive or disabled units but
+// still prints their state, so only a failure to run it is an error.
+func systemctlState(query, unit string) (string, error) {
+	output, err := runCommand("systemctl", query, unit)
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// getServices reports the state of the given systemd units as
+// "<active state> (<enablement>)", e.g. "active (enabled)". Units that are
+// not installed are omitted.
+// Returns an error if the host does not run systemd or systemctl fails.
+func getServices(units []string) (map[string]string, error) {
+	if _, err := os.Stat(systemdRuntimeDir); err != nil {
+		return nil, fmt.Errorf("services: systemd not available: %w", err)
+	}
+
+	services := make(map[string]string)
+	for _, unit := range units {
+		enabled, err := systemctlState("is-enabled", unit)
+		if err != nil {
+			return nil, fmt.Errorf("services: systemctl is-enabled %s failed: %w", unit, err)
+		}
+		// is-enabled prints nothing to stdout for units without a unit file
+		if enabled == "" || enabled == "not-found" {
+			continue
+		}
+		active, err := systemctlState("is-active", unit)
+		if err != nil {
+			return nil, fmt.Errorf("services: systemctl is-active %s failed: %w", unit, err)
+		}
+		services[unit] = fmt.Sprintf("%s (%s)", active, enabled)
+	}
+	return services, nil
+}
@@ -0,0 +1,80 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysinfo
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestGetServices validates systemd unit state collection and its
+// degradation on hosts without systemd.
+func TestGetServices(t *testing.T) {
+	tests := []struct {
+		name      string
+		systemd   bool
+		outputs   map[string]string
+		expected  map[string]string
+		expectErr bool
+	}{
+		{
+			name:    "active and inactive units",
+			systemd: true,
+			outputs: map[string]string{
+				"systemctl is-enabled cloudberry": "enabled\n",
+				"systemctl is-active cloudberry":  "active\n",
+				"systemctl is-enabled greenplum":  "disabled\n",
+				"systemctl is-active greenplum":   "inactive\n",
+			},
+			expected: map[string]string{"cloudberry": "active (enabled)", "greenplum": "inactive (disabled)"},
+		},
+		{
+			name:     "units not installed are omitted",
+			systemd:  true,
+			outputs:  map[string]string{"systemctl is-enabled cloudberry": "", "systemctl is-enabled greenplum": "not-found\n"},
+			expected: map[string]string{},
+		},
+		{
+			name:      "systemctl missing",
+			systemd:   true,
+			outputs:   map[string]string{},
+			expectErr: true,
+		},
+		{
+			name:      "host without systemd",
+			systemd:   false,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := systemdRuntimeDir
+			t.Cleanup(func() { systemdRuntimeDir = original })
+			systemdRuntimeDir = t.TempDir()
+			if !tt.systemd {
+				systemdRuntimeDir = filepath.Join(systemdRuntimeDir, "missing")
+			}
+			mockCommands(t, tt.outputs)
+
+			services, err := getServices([]string{"cloudberry", "greenplum"})
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("getServices() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if !tt.expectErr && !reflect.DeepEqual(services, tt.expected) {
+				t.Errorf("getServices() = %v, expected %v", services, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysinfo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/edespino/cbtoolbox/cmd/internal/partial"
+	"gopkg.in/yaml.v2"
+)
+
+// orderedObject is a JSON object whose members keep their order when
+// marshaled as JSON or YAML. Struct fields otherwise follow declaration
+// order while map keys are sorted, so a field added or moved in a new
+// version shifts the rest of the document.
+type orderedObject []orderedMember
+
+// orderedMember is a single key and value of an orderedObject.
+type orderedMember struct {
+	Key   string
+	Value any
+}
+
+// MarshalJSON writes the members in order.
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	for i, member := range o {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		key, err := json.Marshal(member.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(member.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteString(":")
+		buf.Write(value)
+	}
+	buf.WriteString("}")
+	return buf.Bytes(), nil
+}
+
+// MarshalYAML returns the members as a yaml.MapSlice, which yaml.v2 keeps
+// in order.
+func (o orderedObject) MarshalYAML() (interface{}, error) {
+	slice := make(yaml.MapSlice, 0, len(o))
+	for _, member := range o {
+		slice = append(slice, yaml.MapItem{Key: member.Key, Value: yamlValue(member.Value)})
+	}
+	return slice, nil
+}
+
+// yamlValue converts the json.Number scalars of the ordered tree to
+// numbers, which yaml.v2 would otherwise quote as strings. Integers beyond
+// int64, such as unlimited resource limits, stay exact as uint64.
+func yamlValue(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if u, err := strconv.ParseUint(v.String(), 10, 64); err == nil {
+			return u
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	case []any:
+		values := make([]any, len(v))
+		for i, element := range v {
+			values[i] = yamlValue(element)
+		}
+		return values
+	default:
+		return v
+	}
+}
+
+// sortedDocument converts v to an ordered tree in which the keys of every
+// object, whether from a struct or a map, are sorted by their bytes, so
+// uppercase keys such as GPHOME come first. Numbers are kept as
+// json.Number so they print unchanged. Fields that cannot be marshaled are
+// left out and returned as failures.
+func sortedDocument(v any) (any, []string, error) {
+	data, failures, err := partial.JSON(v, "")
+	if err != nil {
+		return nil, nil, fmt.Errorf("sort keys: failed to marshal: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var tree any
+	if err := decoder.Decode(&tree); err != nil {
+		return nil, nil, fmt.Errorf("sort keys: failed to decode: %w", err)
+	}
+	return sortTree(tree), failures, nil
+}
+
+// sortTree replaces the objects of a decoded JSON tree with orderedObjects
+// holding their members in key order.
+func sortTree(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		object := make(orderedObject, 0, len(keys))
+		for _, key := range keys {
+			object = append(object, orderedMember{Key: key, Value: sortTree(v[key])})
+		}
+		return object
+	case []any:
+		for i, element := range v {
+			v[i] = sortTree(element)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// marshalSorted renders v as a yaml or indented json document with sorted
+// keys (see sortedDocument).
+func marshalSorted(v any, format string) ([]byte, []string, error) {
+	document, failures, err := sortedDocument(v)
+	if err != nil {
+		return nil, nil, err
+	}
+	var output []byte
+	if format == "json" {
+		output, err = json.MarshalIndent(document, "", "  ")
+	} else {
+		output, err = yaml.Marshal(document)
+	}
+	return output, failures, err
+}
@@ -0,0 +1,105 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysinfo
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+// sortedTestInfo has struct fields declared out of alphabetical order,
+// nested structs and maps, and a limit beyond int64.
+var sortedTestInfo = SysInfo{
+	OS:           "linux",
+	Architecture: "amd64",
+	Hostname:     "cdw",
+	CPUs:         16,
+	GPHOME:       "/usr/local/cloudberry",
+	MemoryStats:  map[string]string{"MemTotal": "15.6 GiB", "Buffers": "1.0 GiB"},
+	CGroupLimits: &CGroupLimits{Version: "v2", CPULimit: "unlimited"},
+	Warnings:     []string{"b warning", "a warning"},
+}
+
+// TestMarshalOutputSortKeys validates that every object of the yaml and
+// json documents lists its keys in order, and that the values are unchanged.
+func TestMarshalOutputSortKeys(t *testing.T) {
+	expectedTop := []string{"GPHOME", "architecture", "cgroup_limits", "cpus", "hostname", "kernel", "memory_stats", "os", "os_version", "warnings"}
+
+	for _, format := range []string{"json", "yaml"} {
+		t.Run(format, func(t *testing.T) {
+			output, err := marshalOutput(sortedTestInfo, format, true)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var top yaml.MapSlice
+			if err := yaml.Unmarshal(output, &top); err != nil {
+				t.Fatalf("expected a document, got %s: %v", output, err)
+			}
+			var keys []string
+			for _, item := range top {
+				keys = append(keys, item.Key.(string))
+			}
+			if strings.Join(keys, ",") != strings.Join(expectedTop, ",") {
+				t.Errorf("unexpected key order %q", keys)
+			}
+			if strings.Index(string(output), "Buffers") > strings.Index(string(output), "MemTotal") {
+				t.Errorf("expected map keys in order, got %s", output)
+			}
+			if strings.Index(string(output), "cpu_limit") > strings.Index(string(output), "version") {
+				t.Errorf("expected nested struct fields in order, got %s", output)
+			}
+			if strings.Index(string(output), "b warning") > strings.Index(string(output), "a warning") {
+				t.Errorf("expected lists to keep their order, got %s", output)
+			}
+
+			// The document holds the same values as the unsorted one
+			unsorted, err := marshalOutput(sortedTestInfo, format, false)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var sortedValues, unsortedValues map[string]any
+			if format == "json" {
+				_ = json.Unmarshal(output, &sortedValues)
+				_ = json.Unmarshal(unsorted, &unsortedValues)
+			} else {
+				_ = yaml.Unmarshal(output, &sortedValues)
+				_ = yaml.Unmarshal(unsorted, &unsortedValues)
+			}
+			if !reflect.DeepEqual(sortedValues, unsortedValues) {
+				t.Errorf("sorted document differs:\n%s\nunsorted:\n%s", output, unsorted)
+			}
+		})
+	}
+}
+
+// TestYAMLValue validates that numbers are not quoted in yaml documents.
+func TestYAMLValue(t *testing.T) {
+	output, err := yaml.Marshal(sortTree(map[string]any{
+		"count":     json.Number("16"),
+		"unlimited": json.Number("18446744073709551615"),
+		"ratio":     json.Number("0.5"),
+		"list":      []any{json.Number("1")},
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "count: 16\nlist:\n- 1\nratio: 0.5\nunlimited: 18446744073709551615\n"
+	if string(output) != expected {
+		t.Errorf("expected %q, got %q", expected, output)
+	}
+}
@@ -0,0 +1,89 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysinfo
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+)
+
+// timeSyncCommands maps each TimeSync source to the command it was read from.
+var timeSyncCommands = map[string]string{
+	"timedatectl": "timedatectl status",
+	"chronyc":     "chronyc tracking",
+	"ntpq":        "ntpq -p",
+}
+
+// fieldSources describes where each populated field of info was collected
+// from, as "<field> <- <source>" lines in document order. Paths are read
+// from the package variables, so mocked paths are reported as such.
+func fieldSources(info SysInfo, opts options) []string {
+	var sources []string
+	add := func(populated bool, field, source string) {
+		if populated {
+			sources = append(sources, fmt.Sprintf("%s <- %s", field, source))
+		}
+	}
+
+	add(info.OS != "", "os", "runtime.GOOS")
+	add(info.Architecture != "", "architecture", "runtime.GOARCH")
+	add(info.Hostname != "", "hostname", "gethostname(2)")
+	add(info.MachineID != "", "machine_id", machineIDPath)
+	add(info.Virtualization != "", "virtualization", "systemd-detect-virt, falling back to "+dmiProductNamePath+" and "+dmiSysVendorPath)
+	add(info.Kernel != "", "kernel", "uname -r")
+	add(info.KernelCmdline != "", "kernel_cmdline", procCmdline)
+	add(info.KernelParameters != nil, "kernel_parameters", procCmdline)
+	add(info.OSVersion != "", "os_version", osReleasePath+" PRETTY_NAME")
+	add(info.CPUs != 0, "cpus", "runtime.NumCPU")
+	add(info.MemoryStats != nil, "memory_stats", procMeminfo)
+	add(info.MemoryStatsFull != nil, "memory_stats_full", procMeminfo)
+
+	gphomeBin := filepath.Join(info.GPHOME, "bin")
+	add(info.GPHOME != "", "GPHOME", "$GPHOME")
+	add(info.PGConfigConfigure != nil, "pg_config_configure", filepath.Join(gphomeBin, "pg_config")+" --configure")
+	add(info.AssertionsEnabled != nil, "assertions_enabled", filepath.Join(gphomeBin, "pg_config")+" --configure (--enable-cassert)")
+	add(info.DebugEnabled != nil, "debug_enabled", filepath.Join(gphomeBin, "pg_config")+" --configure (--enable-debug)")
+	add(info.PostgresVersion != "", "postgres_version", filepath.Join(gphomeBin, "postgres")+" --version")
+	add(info.GPVersion != "", "gp_version", filepath.Join(gphomeBin, "postgres")+" --gp-version")
+	add(info.VersionConsistent != nil || info.VersionNote != "", "version_consistent", "postgres_version and gp_version")
+	add(info.GPHOMEFilesystem != "", "gphome_filesystem", procMounts)
+	add(info.MountOptions != nil, "mount_options", procMounts+" for $GPHOME and $COORDINATOR_DATA_DIRECTORY/$MASTER_DATA_DIRECTORY")
+
+	add(info.SecurityModules != nil, "security_modules", selinuxEnforcePath+", "+apparmorEnabledPath)
+	add(info.KernelTuning != nil, "kernel_tuning", filepath.Join(procSysVM, "<sysctl>"))
+	add(info.ResourceLimits != nil, "resource_limits", "getrlimit(2)")
+	if info.TimeSync != nil {
+		source, ok := timeSyncCommands[info.TimeSync.Source]
+		if !ok {
+			source = "timedatectl status, chronyc tracking, ntpq -p (none available)"
+		}
+		add(true, "time_sync", source)
+	}
+	add(info.CGroupLimits != nil, "cgroup_limits", cgroupRoot+", "+procMeminfo)
+	add(info.RunningBackends != nil, "running_backends", filepath.Join(procDir, "<pid>", "exe")+", "+filepath.Join(procDir, "<pid>", "cmdline"))
+	if info.Libraries != nil {
+		add(info.Libraries.Glibc != "", "libraries.glibc", "getconf GNU_LIBC_VERSION, falling back to ldd --version")
+		add(info.Libraries.Linked != nil && opts.linkedLibraries, "libraries.linked", "ldd "+filepath.Join(gphomeBin, "postgres"))
+	}
+	add(info.Services != nil, "services", "systemctl is-enabled, systemctl is-active")
+	return sources
+}
+
+// printFieldSources writes the field sources of info to w.
+func printFieldSources(w io.Writer, info SysInfo, opts options) {
+	fmt.Fprintln(w, "Field sources:")
+	for _, source := range fieldSources(info, opts) {
+		fmt.Fprintln(w, "  "+source)
+	}
+}
@@ -0,0 +1,76 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysinfo
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// TestFieldSources validates that only populated fields are attributed and
+// that mocked paths are reported.
+func TestFieldSources(t *testing.T) {
+	originalOSRelease := osReleasePath
+	t.Cleanup(func() { osReleasePath = originalOSRelease })
+	osReleasePath = "/tmp/mock/os-release"
+
+	tests := []struct {
+		name     string
+		info     SysInfo
+		opts     options
+		expected []string
+	}{
+		{
+			name: "system fields",
+			info: SysInfo{Kernel: "Linux 5.14.0", OSVersion: "Rocky Linux 9.3", TimeSync: &TimeSync{Source: "chronyc"}},
+			expected: []string{
+				"kernel <- uname -r",
+				"os_version <- /tmp/mock/os-release PRETTY_NAME",
+				"time_sync <- chronyc tracking",
+			},
+		},
+		{
+			name: "database fields",
+			info: SysInfo{GPHOME: "/opt/cbdb", PostgresVersion: "postgres (Apache Cloudberry) 14.4", Libraries: &Libraries{Linked: map[string]string{"libz.so.1": "/lib64/libz.so.1"}}},
+			opts: options{linkedLibraries: true},
+			expected: []string{
+				"GPHOME <- $GPHOME",
+				"postgres_version <- /opt/cbdb/bin/postgres --version",
+				"libraries.linked <- ldd /opt/cbdb/bin/postgres",
+			},
+		},
+		{
+			name:     "time sync unavailable",
+			info:     SysInfo{TimeSync: &TimeSync{Source: "not available"}},
+			expected: []string{"time_sync <- timedatectl status, chronyc tracking, ntpq -p (none available)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fieldSources(tt.info, tt.opts); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("fieldSources() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestPrintFieldSources validates the verbose field source listing.
+func TestPrintFieldSources(t *testing.T) {
+	var out bytes.Buffer
+	printFieldSources(&out, SysInfo{Kernel: "Linux 5.14.0"}, defaultOptions())
+	if expected := "Field sources:\n  kernel <- uname -r\n"; out.String() != expected {
+		t.Errorf("printFieldSources() = %q, expected %q", out.String(), expected)
+	}
+}
@@ -29,28 +29,43 @@
 package sysinfo
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"runtime"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
-	"sync"
 
+	"github.com/edespino/cbtoolbox/cmd/internal/partial"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v2"
 )
 
 // Package-level variables that control behavior and configuration.
 var (
-	// formatFlag determines the output format (yaml or json)
-	formatFlag string
-
 	// procMeminfo specifies the path to system memory information
-	procMeminfo = "/proc/meminfo"
+	procMeminfo   = "/proc/meminfo"
 	osReleasePath = "/etc/os-release"
+
+	// procMounts specifies the path to the mounted filesystem table
+	procMounts = "/proc/mounts"
+
+	// selinuxEnforcePath and apparmorEnabledPath expose security module state
+	selinuxEnforcePath  = "/sys/fs/selinux/enforce"
+	apparmorEnabledPath = "/sys/module/apparmor/parameters/enabled"
+)
+
+// Unit systems for rendering byte values, selected with --units.
+const (
+	unitsBinary  = "binary"
+	unitsDecimal = "decimal"
+	unitsRaw     = "raw"
 )
 
 // Cmd represents the sysinfo command that gathers and displays
@@ -66,35 +81,186 @@ Requires GPHOME environment variable to be set for database-specific information
 // SysInfo represents the complete system and database environment
 // information collected by the sysinfo command.
 type SysInfo struct {
-	OS                 string            `json:"os" yaml:"os"`
-	Architecture       string            `json:"architecture" yaml:"architecture"`
-	Hostname           string            `json:"hostname" yaml:"hostname"`
-	Kernel             string            `json:"kernel" yaml:"kernel"`
-	OSVersion          string            `json:"os_version" yaml:"os_version"`
-	CPUs               int               `json:"cpus" yaml:"cpus"`
-	MemoryStats        map[string]string `json:"memory_stats" yaml:"memory_stats"`
-	GPHOME             string            `json:"GPHOME,omitempty" yaml:"GPHOME,omitempty"`
-	PGConfigConfigure  []string          `json:"pg_config_configure,omitempty" yaml:"pg_config_configure,omitempty"`
-	PostgresVersion    string            `json:"postgres_version,omitempty" yaml:"postgres_version,omitempty"`
-	GPVersion          string            `json:"gp_version,omitempty" yaml:"gp_version,omitempty"`
+	OS                string            `json:"os" yaml:"os"`
+	Architecture      string            `json:"architecture" yaml:"architecture"`
+	Hostname          string            `json:"hostname" yaml:"hostname"`
+	MachineID         string            `json:"machine_id,omitempty" yaml:"machine_id,omitempty"`
+	Virtualization    string            `json:"virtualization,omitempty" yaml:"virtualization,omitempty"`
+	Kernel            string            `json:"kernel" yaml:"kernel"`
+	KernelCmdline     string            `json:"kernel_cmdline,omitempty" yaml:"kernel_cmdline,omitempty"`
+	KernelParameters  map[string]string `json:"kernel_parameters,omitempty" yaml:"kernel_parameters,omitempty"`
+	KernelNotes       []string          `json:"kernel_notes,omitempty" yaml:"kernel_notes,omitempty"`
+	OSVersion         string            `json:"os_version" yaml:"os_version"`
+	CPUs              int               `json:"cpus" yaml:"cpus"`
+	MemoryStats       map[string]string `json:"memory_stats,omitempty" yaml:"memory_stats,omitempty"`
+	MemoryStatsFull   map[string]string `json:"memory_stats_full,omitempty" yaml:"memory_stats_full,omitempty"`
+	GPHOME            string            `json:"GPHOME,omitempty" yaml:"GPHOME,omitempty"`
+	PGConfigConfigure []string          `json:"pg_config_configure,omitempty" yaml:"pg_config_configure,omitempty"`
+	AssertionsEnabled *bool             `json:"assertions_enabled,omitempty" yaml:"assertions_enabled,omitempty"`
+	DebugEnabled      *bool             `json:"debug_enabled,omitempty" yaml:"debug_enabled,omitempty"`
+	PostgresVersion   string            `json:"postgres_version,omitempty" yaml:"postgres_version,omitempty"`
+	GPVersion         string            `json:"gp_version,omitempty" yaml:"gp_version,omitempty"`
+	VersionConsistent *bool             `json:"version_consistent,omitempty" yaml:"version_consistent,omitempty"`
+	VersionNote       string            `json:"version_note,omitempty" yaml:"version_note,omitempty"`
+	GPHOMEFilesystem  string            `json:"gphome_filesystem,omitempty" yaml:"gphome_filesystem,omitempty"`
+	MountOptions      map[string]string `json:"mount_options,omitempty" yaml:"mount_options,omitempty"`
+	MountWarnings     []string          `json:"mount_warnings,omitempty" yaml:"mount_warnings,omitempty"`
+	SecurityModules   *SecurityModules  `json:"security_modules,omitempty" yaml:"security_modules,omitempty"`
+	KernelTuning      *KernelTuning     `json:"kernel_tuning,omitempty" yaml:"kernel_tuning,omitempty"`
+	ResourceLimits    *ResourceLimits   `json:"resource_limits,omitempty" yaml:"resource_limits,omitempty"`
+	TimeSync          *TimeSync         `json:"time_sync,omitempty" yaml:"time_sync,omitempty"`
+	CGroupLimits      *CGroupLimits     `json:"cgroup_limits,omitempty" yaml:"cgroup_limits,omitempty"`
+	RunningBackends   *RunningBackends  `json:"running_backends,omitempty" yaml:"running_backends,omitempty"`
+	Libraries         *Libraries        `json:"libraries,omitempty" yaml:"libraries,omitempty"`
+	Services          map[string]string `json:"services,omitempty" yaml:"services,omitempty"`
+	Timings           map[string]string `json:"timings,omitempty" yaml:"timings,omitempty"`
+	Warnings          []string          `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+}
+
+// SecurityModules reports the state of Linux security modules that can
+// block Apache Cloudberry from binding ports or reading directories.
+type SecurityModules struct {
+	SELinux  string   `json:"selinux" yaml:"selinux"`
+	AppArmor string   `json:"apparmor" yaml:"apparmor"`
+	Notes    []string `json:"notes,omitempty" yaml:"notes,omitempty"`
+}
+
+// options holds the settings for a single sysinfo invocation.
+// They are read from the command's flags rather than shared package
+// globals, so concurrent invocations do not race on them.
+type options struct {
+	// format determines the output format (yaml or json)
+	format string
+
+	// noSortConfig keeps pg_config --configure options in their original order
+	noSortConfig bool
+
+	// units selects how byte values are rendered (binary, decimal or raw)
+	units string
+
+	// linkedLibraries runs ldd on the GPHOME postgres binary
+	linkedLibraries bool
+
+	// verbose prints the source of each collected field to stderr
+	verbose bool
+
+	// timings reports the duration of each collection step
+	timings bool
+
+	// services lists the systemd units whose state is reported
+	services []string
+
+	// fullMeminfo reports every /proc/meminfo key in MemoryStatsFull
+	fullMeminfo bool
+
+	// versionRetries bounds the retries of transiently failing postgres version commands
+	versionRetries int
+
+	// skipCollectors lists the collectors that are not run (see Collectors)
+	skipCollectors []string
+
+	// captureFixtures is the directory the raw inputs are written to, if set
+	captureFixtures string
+
+	// noRedact keeps the hostname in captured fixtures
+	noRedact bool
+
+	// sortKeys lists the keys of every yaml/json object in alphabetical order
+	sortKeys bool
+}
+
+// defaultOptions returns the options used when no flags are available.
+func defaultOptions() options {
+	return options{format: "yaml", units: unitsBinary, services: defaultServices, versionRetries: defaultVersionRetries}
+}
+
+// optionsFromFlags reads the invocation options from the command's flags.
+// Flags that are not defined on cmd keep their default values.
+func optionsFromFlags(cmd *cobra.Command) options {
+	opts := defaultOptions()
+	if cmd == nil {
+		return opts
+	}
+	if format, err := cmd.Flags().GetString("format"); err == nil {
+		opts.format = format
+	}
+	if noSort, err := cmd.Flags().GetBool("no-sort-config"); err == nil {
+		opts.noSortConfig = noSort
+	}
+	if units, err := cmd.Flags().GetString("units"); err == nil {
+		opts.units = units
+	}
+	if linked, err := cmd.Flags().GetBool("linked-libraries"); err == nil {
+		opts.linkedLibraries = linked
+	}
+	if verbose, err := cmd.Flags().GetBool("verbose"); err == nil {
+		opts.verbose = verbose
+	}
+	if timings, err := cmd.Flags().GetBool("timings"); err == nil {
+		opts.timings = timings
+	}
+	if services, err := cmd.Flags().GetStringSlice("services"); err == nil {
+		opts.services = services
+	}
+	if fullMeminfo, err := cmd.Flags().GetBool("full-meminfo"); err == nil {
+		opts.fullMeminfo = fullMeminfo
+	}
+	if retries, err := cmd.Flags().GetInt("version-retries"); err == nil {
+		opts.versionRetries = retries
+	}
+	if skip, err := cmd.Flags().GetStringSlice("skip-collectors"); err == nil {
+		opts.skipCollectors = skip
+	}
+	if dir, err := cmd.Flags().GetString("capture-fixtures"); err == nil {
+		opts.captureFixtures = dir
+	}
+	if noRedact, err := cmd.Flags().GetBool("no-redact"); err == nil {
+		opts.noRedact = noRedact
+	}
+	if sortKeys, err := cmd.Flags().GetBool("sort-keys"); err == nil {
+		opts.sortKeys = sortKeys
+	}
+	return opts
 }
 
 // init initializes the sysinfo command configuration.
 // It sets up the default output format and command flags.
 func init() {
 	// Default output format is YAML
-	formatFlag = "yaml"
-	Cmd.Flags().StringVar(&formatFlag, "format", "yaml", "Output format: yaml or json")
+	Cmd.Flags().String("format", "yaml", "Output format: yaml, json, or json-flat (single-level JSON with dotted keys)")
+	Cmd.Flags().Bool("no-sort-config", false, "Keep pg_config configure options in their original order")
+	Cmd.Flags().Bool("sort-keys", false, "List the keys of every yaml/json object, fields and maps alike, in alphabetical order for stable diffs")
+	Cmd.Flags().String("units", unitsBinary, "Units for byte values: binary (KiB, MiB, GiB), decimal (kB, MB, GB) or raw (kB as reported by the kernel)")
+	Cmd.Flags().Bool("linked-libraries", false, "Report the shared libraries the GPHOME postgres binary links against (runs ldd)")
+	Cmd.Flags().StringSlice("services", defaultServices, "Comma-separated systemd units whose state is reported")
+	Cmd.Flags().Bool("full-meminfo", false, "Also report every /proc/meminfo key in memory_stats_full")
+	Cmd.Flags().Int("version-retries", defaultVersionRetries, "Retries of postgres --version/--gp-version when they fail to start transiently (e.g. fork fails under load)")
+	Cmd.Flags().StringSlice("skip-collectors", nil, "Comma-separated collectors not to run: "+strings.Join(Collectors, ", "))
+	Cmd.Flags().String("capture-fixtures", "", "Write the raw files and command outputs read during collection to this directory, for reproducing cbtoolbox bugs offline")
+	Cmd.Flags().Bool("no-redact", false, "Keep the hostname in fixtures written by --capture-fixtures")
+	Cmd.Flags().Bool("timings", false, "Report the wall-clock duration of each collection step in a timings block")
+	Cmd.Flags().BoolP("verbose", "v", false, "Print the source (file or command) of each collected field to stderr")
 }
 
+// Formats lists the output formats supported by the sysinfo command.
+var Formats = []string{"yaml", "json", "json-flat"}
+
 // validateFormat checks if the provided format is supported.
-// Returns nil for valid formats (yaml, json) and an error for unsupported formats.
+// Returns nil for valid formats (yaml, json, json-flat) and an error for unsupported formats.
 func validateFormat(format string) error {
-	switch format {
-	case "yaml", "json":
+	if slices.Contains(Formats, format) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s (supported formats: %s)", ErrInvalidFormat, format, strings.Join(Formats, ", "))
+}
+
+// validateUnits checks if the provided unit system is supported.
+func validateUnits(units string) error {
+	switch units {
+	case unitsBinary, unitsDecimal, unitsRaw:
 		return nil
 	default:
-		return fmt.Errorf("invalid format: %s (supported formats: yaml, json)", format)
+		return fmt.Errorf("%w: %s (supported units: binary, decimal, raw)", ErrInvalidUnits, units)
 	}
 }
 
@@ -126,7 +292,7 @@ func getHostname() (string, error) {
 // getKernelVersion returns the Linux kernel version by executing 'uname -r'.
 // Returns an error if the command fails or cannot be executed.
 func getKernelVersion() (string, error) {
-	output, err := exec.Command("uname", "-r").Output()
+	output, err := runCommand("uname", "-r")
 	if err != nil {
 		return "", fmt.Errorf("kernel: failed to retrieve version: %w", err)
 	}
@@ -159,49 +325,336 @@ func getCPUCount() int {
 
 // getReadableMemoryStats returns memory statistics from /proc/meminfo in a human-readable format.
 // The returned map includes MemTotal, MemFree, MemAvailable, Cached, and Buffers,
-// with values converted to appropriate units (KiB, MiB, GiB).
-func getReadableMemoryStats() (map[string]string, error) {
-	output, err := os.ReadFile(procMeminfo)
+// with values rendered in the given unit system (see formatSize).
+func getReadableMemoryStats(units string) (map[string]string, error) {
+	f, err := os.Open(procMeminfo)
+	if err != nil {
+		return nil, fmt.Errorf("meminfo: failed to read file: %w", err)
+	}
+	defer f.Close()
+
+	memoryStats, err := scanMemoryStats(f, units)
 	if err != nil {
 		return nil, fmt.Errorf("meminfo: failed to read file: %w", err)
 	}
+	return memoryStats, nil
+}
+
+// memoryStatKeys is the number of /proc/meminfo keys reported in MemoryStats.
+const memoryStatKeys = 5
 
-	lines := strings.Split(string(output), "\n")
+// scanMemoryStats reads the reported memory statistics from meminfo content
+// line by line, and stops as soon as all of them were found. The keys sit
+// at the top of /proc/meminfo, so the remaining lines are never read.
+func scanMemoryStats(r io.Reader, units string) (map[string]string, error) {
+	memoryStats := make(map[string]string, memoryStatKeys)
+	scanner := bufio.NewScanner(r)
+	for len(memoryStats) < memoryStatKeys && scanner.Scan() {
+		key, rest, found := bytes.Cut(scanner.Bytes(), []byte(":"))
+		if !found {
+			continue
+		}
+		switch string(key) {
+		case "MemTotal", "MemFree", "MemAvailable", "Cached", "Buffers":
+			if fields := bytes.Fields(rest); len(fields) > 0 {
+				memoryStats[string(key)] = formatSize(string(fields[0]), units)
+			}
+		}
+	}
+	return memoryStats, scanner.Err()
+}
+
+// getFullMemoryStats returns every key of /proc/meminfo. Values reported
+// in kB are rendered in the given unit system (see formatSize); counts such
+// as HugePages_Total are returned as they are.
+func getFullMemoryStats(units string) (map[string]string, error) {
+	data, err := readFile(procMeminfo)
+	if err != nil {
+		return nil, fmt.Errorf("meminfo: failed to read file: %w", err)
+	}
 	memoryStats := make(map[string]string)
-	for _, line := range lines {
-		fields := strings.Fields(line)
-		if len(fields) < 2 {
+	for _, line := range strings.Split(string(data), "\n") {
+		key, rest, found := strings.Cut(line, ":")
+		if !found {
 			continue
 		}
-		key := strings.TrimSuffix(fields[0], ":")
-		value := fields[1]
-		if key == "MemTotal" || key == "MemFree" || key == "MemAvailable" || key == "Cached" || key == "Buffers" {
-			converted := humanizeSize(value)
-			memoryStats[key] = converted
+		fields := strings.Fields(rest)
+		switch {
+		case len(fields) == 2 && fields[1] == "kB":
+			memoryStats[key] = formatSize(fields[0], units)
+		case len(fields) > 0:
+			memoryStats[key] = strings.Join(fields, " ")
 		}
 	}
 	return memoryStats, nil
 }
 
-// humanizeSize converts a memory size from kilobytes to a human-readable string.
-// Input is a string representing kilobytes.
-// Output format:
-//   - For values >= 1024*1024 KB: X.X GiB
-//   - For values >= 1024 KB: X.X MiB
-//   - For values < 1024 KB: X KiB
+// humanizeSize converts a memory size from kilobytes to a human-readable string
+// in binary units. It is shorthand for formatSize(kb, unitsBinary).
 func humanizeSize(kb string) string {
+	return formatSize(kb, unitsBinary)
+}
+
+// formatSize renders a size given in kilobytes (1024 bytes, as reported by
+// /proc) in the selected unit system. Every byte value in the sysinfo output
+// goes through this function.
+// Output format:
+//   - binary: X.X GiB, X.X MiB or X KiB (powers of 1024)
+//   - decimal: X.X GB, X.X MB or X kB (powers of 1000)
+//   - raw: X kB, unconverted
+//
+// Input that is not an integer is returned unchanged.
+func formatSize(kb string, units string) string {
 	kbInt, err := strconv.Atoi(kb)
 	if err != nil {
 		return kb
 	}
-	switch {
-	case kbInt >= 1024*1024:
-		return fmt.Sprintf("%.1f GiB", float64(kbInt)/(1024*1024))
-	case kbInt >= 1024:
-		return fmt.Sprintf("%.1f MiB", float64(kbInt)/1024)
+	switch units {
+	case unitsRaw:
+		return fmt.Sprintf("%d kB", kbInt)
+	case unitsDecimal:
+		bytes := float64(kbInt) * 1024
+		switch {
+		case bytes >= 1e9:
+			return fmt.Sprintf("%.1f GB", bytes/1e9)
+		case bytes >= 1e6:
+			return fmt.Sprintf("%.1f MB", bytes/1e6)
+		default:
+			return fmt.Sprintf("%.0f kB", bytes/1e3)
+		}
+	default:
+		switch {
+		case kbInt >= 1024*1024:
+			return fmt.Sprintf("%.1f GiB", float64(kbInt)/(1024*1024))
+		case kbInt >= 1024:
+			return fmt.Sprintf("%.1f MiB", float64(kbInt)/1024)
+		default:
+			return fmt.Sprintf("%d KiB", kbInt)
+		}
+	}
+}
+
+// getSELinuxMode returns the SELinux mode (enforcing, permissive, or disabled).
+// Reads /sys/fs/selinux/enforce, falling back to the getenforce command.
+// Returns "not available" if neither source can be read.
+func getSELinuxMode() string {
+	if content, err := readFile(selinuxEnforcePath); err == nil {
+		switch strings.TrimSpace(string(content)) {
+		case "1":
+			return "enforcing"
+		case "0":
+			return "permissive"
+		}
+	}
+	if output, err := runCommand("getenforce"); err == nil {
+		return strings.ToLower(strings.TrimSpace(string(output)))
+	}
+	return "not available"
+}
+
+// getAppArmorStatus returns whether AppArmor is enabled or disabled.
+// Returns "not available" if the AppArmor module is not loaded.
+func getAppArmorStatus() string {
+	content, err := readFile(apparmorEnabledPath)
+	if err != nil {
+		return "not available"
+	}
+	switch strings.TrimSpace(string(content)) {
+	case "Y":
+		return "enabled"
+	case "N":
+		return "disabled"
 	default:
-		return fmt.Sprintf("%d KiB", kbInt)
+		return strings.TrimSpace(string(content))
+	}
+}
+
+// getSecurityModules collects SELinux and AppArmor state.
+// Enforcing modes are flagged with a note since they often require
+// policy work before Apache Cloudberry runs correctly.
+func getSecurityModules() *SecurityModules {
+	modules := &SecurityModules{
+		SELinux:  getSELinuxMode(),
+		AppArmor: getAppArmorStatus(),
+	}
+	if modules.SELinux == "enforcing" {
+		modules.Notes = append(modules.Notes, "SELinux is enforcing; Cloudberry may need policy changes to bind ports or access data directories")
+	}
+	if modules.AppArmor == "enabled" {
+		modules.Notes = append(modules.Notes, "AppArmor is enabled; verify no profile confines postgres or its data directories")
+	}
+	return modules
+}
+
+// discouragedMountOptions lists mount options that are discouraged for
+// filesystems hosting database data directories, with the reason why.
+var discouragedMountOptions = map[string]string{
+	"nobarrier":      "disables write barriers and risks data loss on power failure",
+	"barrier=0":      "disables write barriers and risks data loss on power failure",
+	"data=writeback": "allows stale data to appear in files after a crash",
+	"discard":        "online discard can cause I/O latency spikes; prefer periodic fstrim",
+}
+
+// mountEntry represents a single line of /proc/mounts.
+type mountEntry struct {
+	Device     string
+	MountPoint string
+	FSType     string
+	Options    string
+}
+
+// unescapeMountField decodes the octal escapes (e.g. \040 for a space)
+// used by the kernel for special characters in /proc/mounts fields.
+func unescapeMountField(field string) string {
+	if !strings.Contains(field, `\`) {
+		return field
+	}
+	var b strings.Builder
+	for i := 0; i < len(field); i++ {
+		if field[i] == '\\' && i+3 < len(field) {
+			if v, err := strconv.ParseUint(field[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(v))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(field[i])
+	}
+	return b.String()
+}
+
+// readMounts parses the mounted filesystem table from procMounts.
+// Returns an error if the file cannot be read.
+func readMounts() ([]mountEntry, error) {
+	content, err := readFile(procMounts)
+	if err != nil {
+		return nil, fmt.Errorf("mounts: failed to read file: %w", err)
+	}
+
+	var entries []mountEntry
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+		entries = append(entries, mountEntry{
+			Device:     unescapeMountField(fields[0]),
+			MountPoint: unescapeMountField(fields[1]),
+			FSType:     fields[2],
+			Options:    fields[3],
+		})
+	}
+	return entries, nil
+}
+
+// findMount returns the mount entry hosting the given path, which is the
+// entry with the longest mount point that is a prefix of the path.
+// When a mount point is mounted over, the last entry wins.
+func findMount(path string, entries []mountEntry) (mountEntry, bool) {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	path = filepath.Clean(path)
+
+	var best mountEntry
+	found := false
+	for _, entry := range entries {
+		mp := entry.MountPoint
+		if path != mp && mp != "/" && !strings.HasPrefix(path, mp+"/") {
+			continue
+		}
+		if !found || len(mp) >= len(best.MountPoint) {
+			best = entry
+			found = true
+		}
 	}
+	return best, found
+}
+
+// getDataDirectories returns the coordinator data directory configured in
+// the environment, if any. Both the current and the legacy variable names
+// are honored.
+func getDataDirectories() []string {
+	var dirs []string
+	for _, name := range []string{"COORDINATOR_DATA_DIRECTORY", "MASTER_DATA_DIRECTORY"} {
+		if dir := os.Getenv(name); dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// networkFilesystems lists filesystem types served over the network.
+// Running binaries from them can cause subtle issues and slow startups.
+var networkFilesystems = map[string]bool{
+	"nfs":        true,
+	"nfs4":       true,
+	"cifs":       true,
+	"smb3":       true,
+	"smbfs":      true,
+	"ceph":       true,
+	"glusterfs":  true,
+	"fuse.sshfs": true,
+}
+
+// getGPHOMEFilesystem returns the filesystem type of the mount hosting
+// GPHOME, along with a warning when it is a network filesystem.
+// Returns an error if /proc/mounts cannot be read or has no matching mount.
+func getGPHOMEFilesystem(gphome string) (string, string, error) {
+	entries, err := readMounts()
+	if err != nil {
+		return "", "", err
+	}
+	entry, ok := findMount(gphome, entries)
+	if !ok {
+		return "", "", fmt.Errorf("mounts: no mount found for %s", gphome)
+	}
+	if networkFilesystems[entry.FSType] {
+		return entry.FSType, fmt.Sprintf("GPHOME %s is on a network filesystem (%s mounted at %s); running binaries from it can cause subtle issues and slow startups", gphome, entry.FSType, entry.MountPoint), nil
+	}
+	return entry.FSType, "", nil
+}
+
+// getMountOptions returns the mount options of the filesystems hosting
+// GPHOME and the data directories, keyed by mount point, along with
+// warnings for any options that are discouraged for database data
+// directories. Only mounts hosting a data directory are warned about.
+// Returns an error if /proc/mounts cannot be read.
+func getMountOptions(gphome string, dataDirs []string) (map[string]string, []string, error) {
+	entries, err := readMounts()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dataMounts := make(map[string]bool)
+	for _, dir := range dataDirs {
+		if entry, ok := findMount(dir, entries); ok {
+			dataMounts[entry.MountPoint] = true
+		}
+	}
+
+	options := make(map[string]string)
+	var warnings []string
+	for _, path := range append([]string{gphome}, dataDirs...) {
+		entry, ok := findMount(path, entries)
+		if !ok {
+			continue
+		}
+		if _, seen := options[entry.MountPoint]; seen {
+			continue
+		}
+		options[entry.MountPoint] = entry.Options
+		if !dataMounts[entry.MountPoint] {
+			continue
+		}
+
+		for _, opt := range strings.Split(entry.Options, ",") {
+			if reason, bad := discouragedMountOptions[opt]; bad {
+				warnings = append(warnings, fmt.Sprintf("%s: %s is discouraged for database data directories (%s)", entry.MountPoint, opt, reason))
+			}
+		}
+	}
+	return options, warnings, nil
 }
 
 // getGPHOME returns and validates the GPHOME environment variable.
@@ -212,32 +665,52 @@ func humanizeSize(kb string) string {
 func getGPHOME() (string, error) {
 	gphome := os.Getenv("GPHOME")
 	if gphome == "" {
-		return "", fmt.Errorf("GPHOME: environment variable not set")
+		return "", &messageError{"GPHOME: environment variable not set", ErrGPHOMENotSet}
 	}
 	if _, err := os.Stat(gphome); os.IsNotExist(err) {
-		return gphome, fmt.Errorf("GPHOME: directory does not exist: %s", gphome)
+		return gphome, &messageError{fmt.Sprintf("GPHOME: directory does not exist: %s", gphome), ErrGPHOMENotFound}
 	}
 	return gphome, nil
 }
 
 // getPGConfigConfigure returns PostgreSQL build configuration options.
 // Executes pg_config --configure in the specified GPHOME/bin directory.
+// Options are sorted alphabetically so output is comparable across hosts,
+// unless sortOptions is false (--no-sort-config).
 // Returns an error if:
 //   - pg_config executable is not found in GPHOME/bin
 //   - pg_config command execution fails
-func getPGConfigConfigure(gphome string) ([]string, error) {
+func getPGConfigConfigure(gphome string, sortOptions bool) ([]string, error) {
 	pgConfigPath := filepath.Join(gphome, "bin", "pg_config")
 	if _, err := os.Stat(pgConfigPath); os.IsNotExist(err) {
 		return nil, fmt.Errorf("pg_config: file not found at %s", pgConfigPath)
 	}
 
-	cmd := exec.Command(pgConfigPath, "--configure")
-	output, err := cmd.Output()
+	output, err := runCommand(pgConfigPath, "--configure")
 	if err != nil {
 		return nil, fmt.Errorf("pg_config: failed to execute: %w", err)
 	}
 	config := strings.ReplaceAll(strings.TrimSpace(string(output)), "'", "")
-	return strings.Fields(config), nil
+	options := strings.Fields(config)
+	if sortOptions {
+		sort.Strings(options)
+	}
+	return options, nil
+}
+
+// buildFlags reports whether the configure options of a PostgreSQL build
+// include --enable-cassert and --enable-debug. Assertion-enabled builds
+// are slower and abort on failed assertions where release builds carry on.
+func buildFlags(options []string) (assertions, debug bool) {
+	for _, option := range options {
+		switch strings.TrimSpace(option) {
+		case "--enable-cassert":
+			assertions = true
+		case "--enable-debug":
+			debug = true
+		}
+	}
+	return assertions, debug
 }
 
 // getPostgresVersion returns the PostgreSQL server version.
@@ -251,8 +724,7 @@ func getPostgresVersion(gphome string) (string, error) {
 		return "", fmt.Errorf("postgres: executable not found at %s", postgresPath)
 	}
 
-	cmd := exec.Command(postgresPath, "--version")
-	output, err := cmd.Output()
+	output, err := runCommand(postgresPath, "--version")
 	if err != nil {
 		return "", fmt.Errorf("postgres: failed to execute version check: %w", err)
 	}
@@ -270,8 +742,7 @@ func getGPVersion(gphome string) (string, error) {
 		return "", fmt.Errorf("postgres: executable not found at %s", postgresPath)
 	}
 
-	cmd := exec.Command(postgresPath, "--gp-version")
-	output, err := cmd.Output()
+	output, err := runCommand(postgresPath, "--gp-version")
 	if err != nil {
 		return "", fmt.Errorf("postgres: failed to execute gp-version check: %w", err)
 	}
@@ -287,8 +758,10 @@ func getGPVersion(gphome string) (string, error) {
 //   - []error: Collection of any errors encountered
 //
 // If GPHOME is not set or invalid, returns appropriate error messages for each
-// component that could not be checked.
-func gatherGPHOMEInfo() (string, []string, string, string, []error) {
+// component that could not be checked. Each command is timed with timer.
+// The postgres version commands are retried up to opts.versionRetries
+// times when they fail to start transiently (see isTransient).
+func gatherGPHOMEInfo(opts options, timer *stepTimer) (string, []string, string, string, []error) {
 	gphome, gphomeErr := getGPHOME()
 	var pgConfig []string
 	var postgresVersion string
@@ -300,21 +773,27 @@ func gatherGPHOMEInfo() (string, []string, string, string, []error) {
 	}
 
 	if gphome != "" {
-		config, err := getPGConfigConfigure(gphome)
+		stop := timer.track("pg_config_configure")
+		config, err := getPGConfigConfigure(gphome, !opts.noSortConfig)
+		stop()
 		if err != nil {
 			errs = append(errs, fmt.Errorf("pg_config error: %w", err))
 		} else {
 			pgConfig = config
 		}
 
-		version, err := getPostgresVersion(gphome)
+		stop = timer.track("postgres_version")
+		version, err := retryTransient(opts.versionRetries, func() (string, error) { return getPostgresVersion(gphome) })
+		stop()
 		if err != nil {
 			errs = append(errs, fmt.Errorf("postgres version error: %w", err))
 		} else {
 			postgresVersion = version
 		}
 
-		gpVer, err := getGPVersion(gphome)
+		stop = timer.track("gp_version")
+		gpVer, err := retryTransient(opts.versionRetries, func() (string, error) { return getGPVersion(gphome) })
+		stop()
 		if err != nil {
 			errs = append(errs, fmt.Errorf("gp version error: %w", err))
 		} else {
@@ -340,9 +819,66 @@ func gatherGPHOMEInfo() (string, []string, string, string, []error) {
 //   - Required system information cannot be collected
 //   - GPHOME is not set (after displaying available system information)
 func RunSysInfo(cmd *cobra.Command, args []string) error {
-	if err := validateFormat(formatFlag); err != nil {
+	return runSysInfo(optionsFromFlags(cmd))
+}
+
+// marshalOutput renders the collected information in the given format.
+// json-flat is a single line, so each run is one event for log ingestion.
+// Fields that cannot be marshaled are left out of the document and
+// reported to stderr, so the rest of the information is still printed.
+// With sortKeys, yaml and json documents list the keys of every object in
+// alphabetical order (see sortedDocument); json-flat keys are always sorted.
+func marshalOutput(info SysInfo, format string, sortKeys bool) ([]byte, error) {
+	var output []byte
+	var failures []string
+	var err error
+	switch {
+	case sortKeys && format != "json-flat":
+		output, failures, err = marshalSorted(info, format)
+	case format == "json":
+		output, failures, err = partial.JSON(info, "  ")
+	case format == "json-flat":
+		var flat map[string]any
+		if flat, failures, err = flatten(info); err == nil {
+			output, err = json.Marshal(flat)
+		}
+	default:
+		output, failures, err = partial.YAML(info)
+	}
+	for _, failure := range failures {
+		fmt.Fprintf(os.Stderr, "Warning: output: left out field %s\n", failure)
+	}
+	return output, err
+}
+
+// runSysInfo implements RunSysInfo with explicit options.
+func runSysInfo(opts options) error {
+	if err := validateFormat(opts.format); err != nil {
 		return err
 	}
+	if err := validateUnits(opts.units); err != nil {
+		return err
+	}
+	if opts.versionRetries < 0 {
+		return fmt.Errorf("--version-retries must not be negative: %d", opts.versionRetries)
+	}
+	if err := validateCollectors(opts.skipCollectors); err != nil {
+		return err
+	}
+	if opts.noRedact && opts.captureFixtures == "" {
+		return fmt.Errorf("--no-redact requires --capture-fixtures")
+	}
+	if opts.captureFixtures != "" {
+		stop, err := captureFixtures(opts.captureFixtures, !opts.noRedact)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := stop(); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+		}()
+	}
 
 	// Check GPHOME first
 	if os.Getenv("GPHOME") == "" {
@@ -352,130 +888,296 @@ func RunSysInfo(cmd *cobra.Command, args []string) error {
 			CPUs:         getCPUCount(),
 		}
 
-		// Get other system info
+		// Get other system info, recording failures as warnings
 		if hostname, err := getHostname(); err == nil {
 			info.Hostname = hostname
+		} else {
+			info.Warnings = append(info.Warnings, err.Error())
 		}
 		if kernel, err := getKernelVersion(); err == nil {
 			info.Kernel = kernel
+		} else {
+			info.Warnings = append(info.Warnings, err.Error())
+		}
+		if opts.collects(collectorCmdline) {
+			if cmdline, params, notes, err := getKernelCmdline(); err == nil {
+				info.KernelCmdline, info.KernelParameters, info.KernelNotes = cmdline, params, notes
+			} else {
+				info.Warnings = append(info.Warnings, err.Error())
+			}
 		}
 		if osVersion, err := getOSVersion(); err == nil {
 			info.OSVersion = osVersion
+		} else {
+			info.Warnings = append(info.Warnings, err.Error())
 		}
-		if memStats, err := getReadableMemoryStats(); err == nil {
-			info.MemoryStats = memStats
+		if opts.collects(collectorMem) {
+			if memStats, err := getReadableMemoryStats(opts.units); err == nil {
+				info.MemoryStats = memStats
+			} else {
+				info.Warnings = append(info.Warnings, err.Error())
+			}
+			if opts.fullMeminfo {
+				if memStats, err := getFullMemoryStats(opts.units); err == nil {
+					info.MemoryStatsFull = memStats
+				} else {
+					info.Warnings = append(info.Warnings, err.Error())
+				}
+			}
+		}
+		if opts.collects(collectorSecurity) {
+			info.SecurityModules = getSecurityModules()
+		}
+		if opts.collects(collectorTuning) {
+			info.KernelTuning = getKernelTuning()
+		}
+		if opts.collects(collectorLimits) {
+			info.ResourceLimits = getResourceLimits(opts.units)
+		}
+		if opts.collects(collectorTime) {
+			info.TimeSync = getTimeSync()
+		}
+		if opts.collects(collectorCGroup) {
+			info.CGroupLimits = getCGroupLimits(opts.units)
+		}
+		if opts.collects(collectorLibraries) {
+			// Linked libraries need GPHOME, so only glibc is reported
+			libs, libErrs := getLibraries("", false)
+			info.Libraries = libs
+			for _, err := range libErrs {
+				info.Warnings = append(info.Warnings, err.Error())
+			}
+		}
+		if opts.collects(collectorServices) {
+			if services, err := getServices(opts.services); err == nil {
+				info.Services = services
+			} else {
+				info.Warnings = append(info.Warnings, err.Error())
+			}
+		}
+		if opts.collects(collectorMachine) {
+			if machineID, err := getMachineID(); err == nil {
+				info.MachineID = machineID
+			} else {
+				info.Warnings = append(info.Warnings, err.Error())
+			}
+			if virtualization, err := getVirtualization(); err == nil {
+				info.Virtualization = virtualization
+			} else {
+				info.Warnings = append(info.Warnings, err.Error())
+			}
 		}
 
-		// Output the available information
-		var output []byte
-		var err error
-		if formatFlag == "json" {
-			output, err = json.MarshalIndent(info, "", "  ")
-		} else {
-			output, err = yaml.Marshal(info)
+		if opts.verbose {
+			printFieldSources(os.Stderr, info, opts)
 		}
+
+		// Output the available information
+		output, err := marshalOutput(info, opts.format, opts.sortKeys)
 		if err != nil {
 			return fmt.Errorf("output: failed to generate: %w", err)
 		}
 
 		fmt.Println(string(output))
-		return fmt.Errorf("GPHOME environment variable is not set")
+		return ErrGPHOMENotSet
 	}
 
-	var wg sync.WaitGroup
-	var mu sync.Mutex
+	timer := newStepTimer(opts.timings)
+	stopTotal := timer.track("total")
 
 	info := SysInfo{}
-	errs := make([]error, 0)
-
-	// Concurrent data collection for system information
-	wg.Add(7)
-	go func() { defer wg.Done(); info.OS = getOS() }()
-	go func() { defer wg.Done(); info.Architecture = getArchitecture() }()
-	go func() {
-		defer wg.Done()
-		if hostname, err := getHostname(); err == nil {
-			info.Hostname = hostname
-		} else {
-			mu.Lock()
-			errs = append(errs, err)
-			mu.Unlock()
-		}
-	}()
-	go func() {
-		defer wg.Done()
-		if kernel, err := getKernelVersion(); err == nil {
-			info.Kernel = kernel
-		} else {
-			mu.Lock()
-			errs = append(errs, err)
-			mu.Unlock()
-		}
-	}()
-	go func() {
-		defer wg.Done()
-		if osVersion, err := getOSVersion(); err == nil {
-			info.OSVersion = osVersion
-		} else {
-			mu.Lock()
-			errs = append(errs, err)
-			mu.Unlock()
-		}
-	}()
-	go func() { defer wg.Done(); info.CPUs = getCPUCount() }()
-	go func() {
-		defer wg.Done()
-		if memStats, err := getReadableMemoryStats(); err == nil {
-			mu.Lock()
+
+	// Concurrent data collection for system information. Each step sets
+	// its own fields of info, so the steps do not need to synchronize.
+	collectors := newCollectorGroup(timer)
+	collectors.run("os", func() error { info.OS = getOS(); return nil })
+	collectors.run("architecture", func() error { info.Architecture = getArchitecture(); return nil })
+	collectors.run("hostname", func() error {
+		hostname, err := getHostname()
+		info.Hostname = hostname
+		return err
+	})
+	collectors.run("kernel", func() error {
+		kernel, err := getKernelVersion()
+		info.Kernel = kernel
+		return err
+	})
+	if opts.collects(collectorCmdline) {
+		collectors.run("kernel_cmdline", func() error {
+			cmdline, params, notes, err := getKernelCmdline()
+			if err != nil {
+				return err
+			}
+			info.KernelCmdline, info.KernelParameters, info.KernelNotes = cmdline, params, notes
+			return nil
+		})
+	}
+	collectors.run("os_version", func() error {
+		osVersion, err := getOSVersion()
+		info.OSVersion = osVersion
+		return err
+	})
+	collectors.run("cpus", func() error { info.CPUs = getCPUCount(); return nil })
+	if opts.collects(collectorSecurity) {
+		collectors.run("security_modules", func() error { info.SecurityModules = getSecurityModules(); return nil })
+	}
+	if opts.collects(collectorTuning) {
+		collectors.run("kernel_tuning", func() error { info.KernelTuning = getKernelTuning(); return nil })
+	}
+	if opts.collects(collectorLimits) {
+		collectors.run("resource_limits", func() error { info.ResourceLimits = getResourceLimits(opts.units); return nil })
+	}
+	if opts.collects(collectorTime) {
+		collectors.run("time_sync", func() error { info.TimeSync = getTimeSync(); return nil })
+	}
+	if opts.collects(collectorCGroup) {
+		collectors.run("cgroup_limits", func() error { info.CGroupLimits = getCGroupLimits(opts.units); return nil })
+	}
+	if opts.collects(collectorMem) {
+		collectors.run("memory_stats", func() error {
+			memStats, err := getReadableMemoryStats(opts.units)
+			if err != nil {
+				memStats = map[string]string{"error": err.Error()}
+			}
 			info.MemoryStats = memStats
-			mu.Unlock()
-		} else {
-			mu.Lock()
-			info.MemoryStats = map[string]string{"error": err.Error()}
-			errs = append(errs, err)
-			mu.Unlock()
-		}
-	}()
+			if !opts.fullMeminfo {
+				return err
+			}
+			memStatsFull, errFull := getFullMemoryStats(opts.units)
+			info.MemoryStatsFull = memStatsFull
+			return errors.Join(err, errFull)
+		})
+	}
 
 	// Collect database-specific information
-	gphome, pgConfig, postgresVersion, gpVersion, gphomeErrs := gatherGPHOMEInfo()
+	gphome, pgConfig, postgresVersion, gpVersion, gphomeErrs := gatherGPHOMEInfo(opts, timer)
 	if gphome != "" {
 		info.GPHOME = gphome
 		info.PGConfigConfigure = pgConfig
+		if pgConfig != nil {
+			assertions, debug := buildFlags(pgConfig)
+			info.AssertionsEnabled, info.DebugEnabled = &assertions, &debug
+		}
 		info.PostgresVersion = postgresVersion
 		info.GPVersion = gpVersion
+		if postgresVersion != "" && gpVersion != "" {
+			info.VersionConsistent, info.VersionNote = checkVersionConsistency(postgresVersion, gpVersion)
+		}
 	}
 
-	wg.Wait()
+	// The mount option, GPHOME filesystem, running backend, library and
+	// service checks are informational and never fail the run
+	var warnings []string
+	if gphome != "" && opts.collects(collectorMounts) {
+		// Report mount options for GPHOME and any configured data directories
+		stop := timer.track("mount_options")
+		mountOpts, mountWarnings, err := getMountOptions(gphome, getDataDirectories())
+		stop()
+		if err == nil {
+			info.MountOptions = mountOpts
+			info.MountWarnings = mountWarnings
+		} else {
+			warnings = append(warnings, err.Error())
+		}
 
-	// Handle and report any errors that occurred during collection
+		stop = timer.track("gphome_filesystem")
+		fsType, warning, err := getGPHOMEFilesystem(gphome)
+		stop()
+		if err == nil {
+			info.GPHOMEFilesystem = fsType
+			if warning != "" {
+				info.MountWarnings = append(info.MountWarnings, warning)
+			}
+		} else {
+			warnings = append(warnings, err.Error())
+		}
+	}
+	if gphome != "" && opts.collects(collectorBackends) {
+		stop := timer.track("running_backends")
+		backends, err := getRunningBackends(gphome)
+		stop()
+		if err == nil {
+			info.RunningBackends = backends
+		} else {
+			warnings = append(warnings, err.Error())
+		}
+	}
+	if opts.collects(collectorLibraries) {
+		stop := timer.track("libraries")
+		libs, libErrs := getLibraries(gphome, opts.linkedLibraries)
+		stop()
+		info.Libraries = libs
+		for _, err := range libErrs {
+			warnings = append(warnings, err.Error())
+		}
+	}
+	if opts.collects(collectorServices) {
+		stop := timer.track("services")
+		services, err := getServices(opts.services)
+		stop()
+		if err == nil {
+			info.Services = services
+		} else {
+			warnings = append(warnings, err.Error())
+		}
+	}
+	if opts.collects(collectorMachine) {
+		stop := timer.track("machine_id")
+		machineID, err := getMachineID()
+		stop()
+		if err == nil {
+			info.MachineID = machineID
+		} else {
+			warnings = append(warnings, err.Error())
+		}
+		stop = timer.track("virtualization")
+		virtualization, err := getVirtualization()
+		stop()
+		if err == nil {
+			info.Virtualization = virtualization
+		} else {
+			warnings = append(warnings, err.Error())
+		}
+	}
+
+	errs := collectors.wait()
+	stopTotal()
+	info.Timings = timer.report()
+
+	// Record collection errors in the document so json/yaml consumers see them
+	for _, err := range append(errs, gphomeErrs...) {
+		info.Warnings = append(info.Warnings, err.Error())
+	}
+	info.Warnings = append(info.Warnings, warnings...)
+
+	// Report any errors that occurred during collection for interactive use.
+	// The summary goes to stderr to keep the document on stdout parseable.
 	if len(errs) > 0 || len(gphomeErrs) > 0 {
-		fmt.Println("\nSummary of errors:")
+		fmt.Fprintln(os.Stderr, "\nSummary of errors:")
 		for _, err := range errs {
-			fmt.Println("-", err)
+			fmt.Fprintln(os.Stderr, "-", err)
 		}
 		for _, err := range gphomeErrs {
-			fmt.Println("-", err)
+			fmt.Fprintln(os.Stderr, "-", err)
 		}
+	}
 
-		// Only fail if we have errors from required components
-		if len(errs) > 0 || len(gphomeErrs) > 0 {
-			return fmt.Errorf("errors occurred during system info collection")
-		}
+	// Field sources go to stderr to keep the document on stdout parseable
+	if opts.verbose {
+		printFieldSources(os.Stderr, info, opts)
 	}
 
 	// Generate output in requested format
-	var output []byte
-	var err error
-	if formatFlag == "json" {
-		output, err = json.MarshalIndent(info, "", "  ")
-	} else {
-		output, err = yaml.Marshal(info)
-	}
+	output, err := marshalOutput(info, opts.format, opts.sortKeys)
 	if err != nil {
 		return fmt.Errorf("output: failed to generate: %w", err)
 	}
 
 	fmt.Println(string(output))
+
+	// Only fail if we have errors from required components
+	if len(errs) > 0 || len(gphomeErrs) > 0 {
+		return ErrCollectionFailed
+	}
 	return nil
 }
@@ -16,9 +16,11 @@
 package sysinfo
 
 import (
+	"errors"
 	"io"
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
 	"strings"
 	"sync"
@@ -42,6 +44,19 @@ func captureOutput(f func()) string {
 	return string(out)
 }
 
+// captureStderr captures anything written to stderr while f runs.
+func captureStderr(f func()) string {
+	r, w, _ := os.Pipe()
+	stdErr := os.Stderr
+	os.Stderr = w
+	defer func() { os.Stderr = stdErr }()
+
+	f()
+	w.Close()
+	out, _ := io.ReadAll(r)
+	return string(out)
+}
+
 // TestGetOS validates that the getOS function returns a valid operating system name.
 // It ensures the returned string is non-empty.
 func TestGetOS(t *testing.T) {
@@ -136,7 +151,7 @@ func TestGetReadableMemoryStats(t *testing.T) {
 	originalProcMeminfo := procMeminfo
 	defer func() { procMeminfo = originalProcMeminfo }()
 
-	memoryStats, err := getReadableMemoryStats()
+	memoryStats, err := getReadableMemoryStats(unitsBinary)
 	if err != nil {
 		t.Errorf("Unexpected error retrieving memory stats: %v", err)
 	}
@@ -153,6 +168,39 @@ func TestGetReadableMemoryStats(t *testing.T) {
 	}
 }
 
+// TestGetFullMemoryStats validates that every meminfo key is reported, with
+// kB values converted and counts left unchanged.
+func TestGetFullMemoryStats(t *testing.T) {
+	originalProcMeminfo := procMeminfo
+	defer func() { procMeminfo = originalProcMeminfo }()
+
+	procMeminfo = filepath.Join(t.TempDir(), "meminfo")
+	content := "MemTotal:       64589932 kB\nMemFree:        63021388 kB\nDirty:               124 kB\nHugePages_Total:       0\nHugepagesize:       2048 kB\n"
+	if err := os.WriteFile(procMeminfo, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write meminfo: %v", err)
+	}
+
+	memoryStats, err := getFullMemoryStats(unitsBinary)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[string]string{
+		"MemTotal":        "61.6 GiB",
+		"MemFree":         "60.1 GiB",
+		"Dirty":           "124 KiB",
+		"HugePages_Total": "0",
+		"Hugepagesize":    "2.0 MiB",
+	}
+	if !reflect.DeepEqual(memoryStats, expected) {
+		t.Errorf("getFullMemoryStats() = %v, want %v", memoryStats, expected)
+	}
+
+	procMeminfo = "/nonexistent/meminfo"
+	if _, err := getFullMemoryStats(unitsBinary); err == nil || !strings.Contains(err.Error(), "meminfo: failed to read file") {
+		t.Errorf("Expected read error for missing meminfo, got %v", err)
+	}
+}
+
 // TestGetReadableMemoryStatsMissingFile validates error handling for missing /proc/meminfo.
 // It simulates a non-existent meminfo file and verifies proper error reporting.
 func TestGetReadableMemoryStatsMissingFile(t *testing.T) {
@@ -161,7 +209,7 @@ func TestGetReadableMemoryStatsMissingFile(t *testing.T) {
 
 	procMeminfo = "/nonexistent/meminfo"
 
-	_, err := getReadableMemoryStats()
+	_, err := getReadableMemoryStats(unitsBinary)
 	if err == nil {
 		t.Errorf("Expected error for missing /proc/meminfo")
 	}
@@ -170,6 +218,137 @@ func TestGetReadableMemoryStatsMissingFile(t *testing.T) {
 	}
 }
 
+// sampleMeminfo is a complete /proc/meminfo of a 64 GiB host.
+const sampleMeminfo = `MemTotal:       64594540 kB
+MemFree:        63026132 kB
+MemAvailable:   63441064 kB
+Buffers:            5224 kB
+Cached:          1005672 kB
+SwapCached:            0 kB
+Active:           374440 kB
+Inactive:         823344 kB
+Active(anon):        968 kB
+Inactive(anon):   198480 kB
+Active(file):     373472 kB
+Inactive(file):   624864 kB
+Unevictable:           0 kB
+Mlocked:               0 kB
+SwapTotal:       8388604 kB
+SwapFree:        8388604 kB
+Dirty:                28 kB
+Writeback:             0 kB
+AnonPages:        186960 kB
+Mapped:           183448 kB
+Shmem:             12560 kB
+KReclaimable:      78536 kB
+Slab:             195584 kB
+SReclaimable:      78536 kB
+SUnreclaim:       117048 kB
+KernelStack:        8752 kB
+PageTables:         6832 kB
+NFS_Unstable:          0 kB
+Bounce:                0 kB
+WritebackTmp:          0 kB
+CommitLimit:    40685872 kB
+Committed_AS:    1012280 kB
+VmallocTotal:   34359738367 kB
+VmallocUsed:       63412 kB
+VmallocChunk:          0 kB
+Percpu:            19968 kB
+HardwareCorrupted:     0 kB
+AnonHugePages:     79872 kB
+ShmemHugePages:        0 kB
+ShmemPmdMapped:        0 kB
+FileHugePages:         0 kB
+FilePmdMapped:         0 kB
+HugePages_Total:       0
+HugePages_Free:        0
+HugePages_Rsvd:        0
+HugePages_Surp:        0
+Hugepagesize:       2048 kB
+Hugetlb:               0 kB
+DirectMap4k:      331648 kB
+DirectMap2M:    11202560 kB
+DirectMap1G:    56623104 kB
+`
+
+// splitMemoryStats is the previous implementation of getReadableMemoryStats,
+// which splits and tokenizes the whole file. It is the reference for the
+// scanner's output and the baseline of BenchmarkGetReadableMemoryStats.
+func splitMemoryStats(path string, units string) (map[string]string, error) {
+	output, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	memoryStats := make(map[string]string)
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		key := strings.TrimSuffix(fields[0], ":")
+		if key == "MemTotal" || key == "MemFree" || key == "MemAvailable" || key == "Cached" || key == "Buffers" {
+			memoryStats[key] = formatSize(fields[1], units)
+		}
+	}
+	return memoryStats, nil
+}
+
+// writeSampleMeminfo points procMeminfo at a copy of sampleMeminfo.
+func writeSampleMeminfo(tb testing.TB) {
+	tb.Helper()
+	originalProcMeminfo := procMeminfo
+	tb.Cleanup(func() { procMeminfo = originalProcMeminfo })
+
+	procMeminfo = filepath.Join(tb.TempDir(), "meminfo")
+	if err := os.WriteFile(procMeminfo, []byte(sampleMeminfo), 0644); err != nil {
+		tb.Fatalf("Failed to write mock meminfo: %v", err)
+	}
+}
+
+// TestGetReadableMemoryStatsMatchesSplit validates that the scanner reports
+// exactly what tokenizing the whole file does, in every unit system.
+func TestGetReadableMemoryStatsMatchesSplit(t *testing.T) {
+	writeSampleMeminfo(t)
+
+	for _, units := range []string{unitsBinary, unitsDecimal, unitsRaw} {
+		scanned, err := getReadableMemoryStats(units)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		split, err := splitMemoryStats(procMeminfo, units)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(scanned, split) {
+			t.Errorf("units %s: scanner returned %v, expected %v", units, scanned, split)
+		}
+	}
+}
+
+// BenchmarkGetReadableMemoryStats compares the scanner, which stops after
+// the reported keys, with splitting and tokenizing the whole file.
+func BenchmarkGetReadableMemoryStats(b *testing.B) {
+	writeSampleMeminfo(b)
+
+	b.Run("scanner", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := getReadableMemoryStats(unitsBinary); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("split", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := splitMemoryStats(procMeminfo, unitsBinary); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
 // TestHumanizeSize validates memory size conversion functionality.
 // Tests conversion of various memory sizes to human-readable format.
 func TestHumanizeSize(t *testing.T) {
@@ -177,10 +356,10 @@ func TestHumanizeSize(t *testing.T) {
 		input    string
 		expected string
 	}{
-		{"1024", "1.0 MiB"},          // Test MiB conversion
-		{"2048576", "2.0 GiB"},       // Test GiB conversion
-		{"512", "512 KiB"},           // Test KiB format
-		{"invalid", "invalid"},        // Test invalid input handling
+		{"1024", "1.0 MiB"},    // Test MiB conversion
+		{"2048576", "2.0 GiB"}, // Test GiB conversion
+		{"512", "512 KiB"},     // Test KiB format
+		{"invalid", "invalid"}, // Test invalid input handling
 	}
 
 	for _, tc := range testCases {
@@ -191,6 +370,42 @@ func TestHumanizeSize(t *testing.T) {
 	}
 }
 
+// TestFormatSize validates rendering of sizes in each unit system.
+func TestFormatSize(t *testing.T) {
+	testCases := []struct {
+		input    string
+		units    string
+		expected string
+	}{
+		{"512", unitsBinary, "512 KiB"},
+		{"1024", unitsBinary, "1.0 MiB"},
+		{"2048576", unitsBinary, "2.0 GiB"},
+		{"512", unitsDecimal, "524 kB"},
+		{"1024", unitsDecimal, "1.0 MB"},
+		{"2048576", unitsDecimal, "2.1 GB"},
+		{"2048576", unitsRaw, "2048576 kB"},
+		{"invalid", unitsDecimal, "invalid"},
+	}
+
+	for _, tc := range testCases {
+		result := formatSize(tc.input, tc.units)
+		if result != tc.expected {
+			t.Errorf("formatSize(%s, %s) = %s; want %s", tc.input, tc.units, result, tc.expected)
+		}
+	}
+}
+
+// TestValidateUnits validates that only supported unit systems are accepted.
+func TestValidateUnits(t *testing.T) {
+	for _, units := range []string{unitsBinary, unitsDecimal, unitsRaw} {
+		if err := validateUnits(units); err != nil {
+			t.Errorf("Expected %s to be valid, got: %v", units, err)
+		}
+	}
+	if err := validateUnits("metric"); !errors.Is(err, ErrInvalidUnits) {
+		t.Errorf("Expected ErrInvalidUnits, got: %v", err)
+	}
+}
 
 // It verifies:
 // - Command fails appropriately
@@ -209,18 +424,31 @@ func TestRunSysInfoWithoutGPHOME(t *testing.T) {
 
 	// Run sysinfo and expect an error
 	err := RunSysInfo(cmd, args)
-	if err == nil || err.Error() != "GPHOME environment variable is not set" {
+	if !errors.Is(err, ErrGPHOMENotSet) || err.Error() != "GPHOME environment variable is not set" {
 		t.Errorf("Expected error for unset GPHOME, got: %v", err)
 	}
 }
 
 // TestGetGPHOMEEmpty validates error handling when GPHOME environment variable is unset.
-// Verifies proper error message and handling of missing environment variable.
+// Verifies the returned error matches ErrGPHOMENotSet.
 func TestGetGPHOMEEmpty(t *testing.T) {
 	os.Unsetenv("GPHOME")
 	_, err := getGPHOME()
-	if err == nil || !strings.Contains(err.Error(), "GPHOME: environment variable not set") {
-		t.Errorf("Expected error for unset GPHOME")
+	if !errors.Is(err, ErrGPHOMENotSet) || err.Error() != "GPHOME: environment variable not set" {
+		t.Errorf("Expected ErrGPHOMENotSet for unset GPHOME, got: %v", err)
+	}
+}
+
+// TestGetGPHOMENonexistent validates error handling when GPHOME points to a missing directory.
+// Verifies the returned error matches ErrGPHOMENotFound.
+func TestGetGPHOMENonexistent(t *testing.T) {
+	originalGPHOME := os.Getenv("GPHOME")
+	defer os.Setenv("GPHOME", originalGPHOME)
+
+	os.Setenv("GPHOME", "/nonexistent/gphome")
+	_, err := getGPHOME()
+	if !errors.Is(err, ErrGPHOMENotFound) || err.Error() != "GPHOME: directory does not exist: /nonexistent/gphome" {
+		t.Errorf("Expected ErrGPHOMENotFound for missing GPHOME directory, got: %v", err)
 	}
 }
 
@@ -228,12 +456,68 @@ func TestGetGPHOMEEmpty(t *testing.T) {
 // Verifies proper error reporting when pg_config is not found in the specified path.
 func TestGetPGConfigConfigure(t *testing.T) {
 	os.Setenv("GPHOME", "/tmp")
-	_, err := getPGConfigConfigure("/tmp")
+	_, err := getPGConfigConfigure("/tmp", true)
 	if err == nil {
 		t.Errorf("Expected error for non-existent pg_config")
 	}
 }
 
+// TestGetPGConfigConfigureOrdering validates that configure options are sorted
+// by default and keep their original order with --no-sort-config.
+func TestGetPGConfigConfigureOrdering(t *testing.T) {
+	tmpDir := t.TempDir()
+	binDir := filepath.Join(tmpDir, "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatalf("Failed to create test bin directory: %v", err)
+	}
+	pgConfigContent := "#!/bin/sh\necho \"'--prefix=/usr/local/cloudberry-db' '--enable-gpcloud' '--disable-external-fts'\"\n"
+	if err := os.WriteFile(filepath.Join(binDir, "pg_config"), []byte(pgConfigContent), 0755); err != nil {
+		t.Fatalf("Failed to create mock pg_config: %v", err)
+	}
+
+	testCases := []struct {
+		noSort   bool
+		expected []string
+	}{
+		{false, []string{"--disable-external-fts", "--enable-gpcloud", "--prefix=/usr/local/cloudberry-db"}},
+		{true, []string{"--prefix=/usr/local/cloudberry-db", "--enable-gpcloud", "--disable-external-fts"}},
+	}
+
+	for _, tc := range testCases {
+		options, err := getPGConfigConfigure(tmpDir, !tc.noSort)
+		if err != nil {
+			t.Fatalf("Unexpected error running pg_config: %v", err)
+		}
+		if strings.Join(options, " ") != strings.Join(tc.expected, " ") {
+			t.Errorf("noSort=%v: got %v, want %v", tc.noSort, options, tc.expected)
+		}
+	}
+}
+
+// TestBuildFlags validates detection of assertion and debug builds from
+// pg_config --configure options.
+func TestBuildFlags(t *testing.T) {
+	testCases := []struct {
+		configure  string
+		assertions bool
+		debug      bool
+	}{
+		{"'--prefix=/usr/local/cloudberry-db' '--enable-cassert' '--enable-debug' '--enable-gpcloud'", true, true},
+		{"'--prefix=/usr/local/cloudberry-db' '--enable-debug' 'CFLAGS=-O0 -g3'", false, true},
+		{"'--prefix=/usr/local/cloudberry-db' '--enable-gpcloud' '--disable-cassert'", false, false},
+		{"'--enable-cassert-checks' '--with-cassert'", false, false},
+		{"", false, false},
+	}
+
+	for _, tc := range testCases {
+		options := strings.Fields(strings.ReplaceAll(tc.configure, "'", ""))
+		assertions, debug := buildFlags(options)
+		if assertions != tc.assertions || debug != tc.debug {
+			t.Errorf("buildFlags(%q) = %v, %v, want %v, %v", tc.configure, assertions, debug, tc.assertions, tc.debug)
+		}
+	}
+}
+
 // TestValidateFormat tests format validation for supported and unsupported formats.
 // Verifies proper handling of valid (yaml, json) and invalid format specifications.
 func TestValidateFormat(t *testing.T) {
@@ -243,6 +527,7 @@ func TestValidateFormat(t *testing.T) {
 	}{
 		{"json", true},
 		{"yaml", true},
+		{"json-flat", true},
 		{"invalid", false},
 	}
 
@@ -251,8 +536,8 @@ func TestValidateFormat(t *testing.T) {
 		if tc.valid && err != nil {
 			t.Errorf("Unexpected error for valid format '%s': %v", tc.format, err)
 		}
-		if !tc.valid && err == nil {
-			t.Errorf("Expected error for invalid format '%s'", tc.format)
+		if !tc.valid && !errors.Is(err, ErrInvalidFormat) {
+			t.Errorf("Expected ErrInvalidFormat for invalid format '%s', got: %v", tc.format, err)
 		}
 	}
 }
@@ -312,9 +597,8 @@ esac`
 
 	// Test both JSON and YAML output formats
 	for _, format := range []string{"json", "yaml"} {
-		formatFlag = format
 		output := captureOutput(func() {
-			err := RunSysInfo(nil, nil)
+			err := runSysInfo(options{format: format, units: unitsBinary})
 			if err != nil {
 				t.Errorf("Unexpected error for format %s: %v", format, err)
 			}
@@ -352,15 +636,12 @@ esac`
 // TestRunSysInfoInvalidFormat validates error handling for invalid output format.
 // Verifies proper error message when an unsupported format is specified.
 func TestRunSysInfoInvalidFormat(t *testing.T) {
-	formatFlag = "invalid"
-	defer func() { formatFlag = "yaml" }()
-
-	err := RunSysInfo(nil, nil)
+	err := runSysInfo(options{format: "invalid", units: unitsBinary})
 	if err == nil {
 		t.Error("Expected error for invalid format")
 	}
-	if !strings.Contains(err.Error(), "invalid format") {
-		t.Errorf("Expected error message to contain 'invalid format', got: %v", err)
+	if !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("Expected ErrInvalidFormat, got: %v", err)
 	}
 }
 
@@ -374,21 +655,25 @@ func TestRunSysInfoConcurrency(t *testing.T) {
 	os.Setenv("GPHOME", tmpDir)
 
 	var wg sync.WaitGroup
-	formatFlag = "json"
 	errChan := make(chan error, 10)
 
+	// Each invocation carries its own format, so mixing formats is safe
+	var errOutput string
 	output := captureOutput(func() {
-		for i := 0; i < 10; i++ {
-			wg.Add(1)
-			go func() {
-				defer wg.Done()
-				if err := RunSysInfo(nil, nil); err != nil {
-					errChan <- err
-				}
-			}()
-		}
-		wg.Wait()
-		close(errChan)
+		errOutput = captureStderr(func() {
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				format := []string{"json", "yaml"}[i%2]
+				go func() {
+					defer wg.Done()
+					if err := runSysInfo(options{format: format, units: unitsBinary}); err != nil {
+						errChan <- err
+					}
+				}()
+			}
+			wg.Wait()
+			close(errChan)
+		})
 	})
 
 	// Verify errors were received as expected
@@ -404,8 +689,16 @@ func TestRunSysInfoConcurrency(t *testing.T) {
 	}
 
 	// Verify error output format
-	if !strings.Contains(output, "Summary of errors:") {
-		t.Error("Expected error summary in output")
+	if !strings.Contains(errOutput, "Summary of errors:") {
+		t.Error("Expected error summary on stderr")
+	}
+
+	// Collection errors are also part of the document
+	if !strings.Contains(output, `"warnings"`) || !strings.Contains(output, "warnings:") {
+		t.Error("Expected warnings in both json and yaml output")
+	}
+	if strings.Contains(output, "Summary of errors:") {
+		t.Error("Expected error summary to be kept out of the document on stdout")
 	}
 }
 
@@ -507,4 +800,184 @@ func TestRunSysInfoWithMockedGPHOME(t *testing.T) {
 	if err != nil {
 		t.Errorf("Expected no error with mocked GPHOME, got: %v", err)
 	}
+
+	// An unreadable /proc/mounts is a warning, not a collection failure
+	originalProcMounts := procMounts
+	defer func() { procMounts = originalProcMounts }()
+	procMounts = filepath.Join(mockGPHOME, "missing-mounts")
+	if err := RunSysInfo(cmd, args); err != nil {
+		t.Errorf("Expected no error without /proc/mounts, got: %v", err)
+	}
+}
+
+// TestGetMountOptions validates mount option collection from a mocked /proc/mounts.
+// Verifies the longest matching mount point is selected and discouraged options are
+// flagged only for mounts hosting a data directory.
+func TestGetMountOptions(t *testing.T) {
+	originalProcMounts := procMounts
+	defer func() { procMounts = originalProcMounts }()
+
+	tmpDir := t.TempDir()
+	procMounts = filepath.Join(tmpDir, "mounts")
+	mounts := `/dev/sda1 / ext4 rw,relatime 0 0
+/dev/sdb1 /data xfs rw,noatime,nobarrier 0 0
+/dev/sdc1 /usr/local/cloud\040berry ext4 rw,noatime,discard 0 0
+`
+	if err := os.WriteFile(procMounts, []byte(mounts), 0644); err != nil {
+		t.Fatalf("Failed to write mock mounts file: %v", err)
+	}
+
+	options, warnings, err := getMountOptions("/usr/local/cloud berry/bin", []string{"/data/coordinator/gpseg-1", "/home/gpadmin"})
+	if err != nil {
+		t.Fatalf("Unexpected error retrieving mount options: %v", err)
+	}
+
+	expected := map[string]string{
+		"/":                      "rw,relatime",
+		"/data":                  "rw,noatime,nobarrier",
+		"/usr/local/cloud berry": "rw,noatime,discard",
+	}
+	for mountPoint, opts := range expected {
+		if options[mountPoint] != opts {
+			t.Errorf("Expected options %q for %s, got %q", opts, mountPoint, options[mountPoint])
+		}
+	}
+	if len(options) != len(expected) {
+		t.Errorf("Expected %d mount points, got %d: %v", len(expected), len(options), options)
+	}
+
+	if len(warnings) != 1 {
+		t.Fatalf("Expected 1 warning, got %d: %v", len(warnings), warnings)
+	}
+	if !strings.Contains(strings.Join(warnings, "\n"), "/data: nobarrier is discouraged") {
+		t.Errorf("Expected warning for nobarrier on /data, got: %v", warnings)
+	}
+
+	// A data directory on the GPHOME mount makes its options matter
+	_, warnings, err = getMountOptions("/usr/local/cloud berry/bin", []string{"/usr/local/cloud berry/data/gpseg0"})
+	if err != nil {
+		t.Fatalf("Unexpected error retrieving mount options: %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "/usr/local/cloud berry: discard is discouraged") {
+		t.Errorf("Expected warning for discard on the shared mount, got: %v", warnings)
+	}
+}
+
+// TestGetGPHOMEFilesystem validates filesystem type detection for GPHOME
+// and the warning for network filesystems.
+func TestGetGPHOMEFilesystem(t *testing.T) {
+	originalProcMounts := procMounts
+	defer func() { procMounts = originalProcMounts }()
+
+	procMounts = filepath.Join(t.TempDir(), "mounts")
+	mounts := `/dev/sda1 / xfs rw,relatime 0 0
+nfs-server:/export/cloudberry /usr/local/cloudberry-nfs nfs4 rw,relatime 0 0
+`
+	if err := os.WriteFile(procMounts, []byte(mounts), 0644); err != nil {
+		t.Fatalf("Failed to write mock mounts file: %v", err)
+	}
+
+	testCases := []struct {
+		gphome  string
+		fsType  string
+		warning bool
+	}{
+		{"/usr/local/cloudberry-db", "xfs", false},
+		{"/usr/local/cloudberry-nfs", "nfs4", true},
+	}
+
+	for _, tc := range testCases {
+		fsType, warning, err := getGPHOMEFilesystem(tc.gphome)
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", tc.gphome, err)
+		}
+		if fsType != tc.fsType {
+			t.Errorf("Expected filesystem %q for %s, got %q", tc.fsType, tc.gphome, fsType)
+		}
+		if (warning != "") != tc.warning {
+			t.Errorf("Expected warning: %v for %s, got %q", tc.warning, tc.gphome, warning)
+		}
+	}
+
+	procMounts = filepath.Join(t.TempDir(), "missing")
+	if _, _, err := getGPHOMEFilesystem("/usr/local/cloudberry-db"); err == nil {
+		t.Error("Expected error for missing mounts file")
+	}
+}
+
+// TestGetMountOptionsMissingFile validates error handling for a missing /proc/mounts.
+func TestGetMountOptionsMissingFile(t *testing.T) {
+	originalProcMounts := procMounts
+	defer func() { procMounts = originalProcMounts }()
+
+	procMounts = "/nonexistent/mounts"
+
+	_, _, err := getMountOptions("/", nil)
+	if err == nil || !strings.Contains(err.Error(), "mounts: failed to read file") {
+		t.Errorf("Expected error for missing mounts file, got: %v", err)
+	}
+}
+
+// TestGetSecurityModules validates SELinux and AppArmor detection from mocked paths.
+// Verifies enforcing modes are flagged with notes and missing modules are reported.
+func TestGetSecurityModules(t *testing.T) {
+	originalSELinux, originalAppArmor := selinuxEnforcePath, apparmorEnabledPath
+	defer func() { selinuxEnforcePath, apparmorEnabledPath = originalSELinux, originalAppArmor }()
+
+	tmpDir := t.TempDir()
+	selinuxEnforcePath = filepath.Join(tmpDir, "enforce")
+	apparmorEnabledPath = filepath.Join(tmpDir, "enabled")
+
+	if err := os.WriteFile(selinuxEnforcePath, []byte("1\n"), 0644); err != nil {
+		t.Fatalf("Failed to write mock enforce file: %v", err)
+	}
+	if err := os.WriteFile(apparmorEnabledPath, []byte("Y\n"), 0644); err != nil {
+		t.Fatalf("Failed to write mock apparmor file: %v", err)
+	}
+
+	modules := getSecurityModules()
+	if modules.SELinux != "enforcing" {
+		t.Errorf("Expected SELinux enforcing, got %q", modules.SELinux)
+	}
+	if modules.AppArmor != "enabled" {
+		t.Errorf("Expected AppArmor enabled, got %q", modules.AppArmor)
+	}
+	if len(modules.Notes) != 2 {
+		t.Errorf("Expected 2 notes, got %v", modules.Notes)
+	}
+
+	if err := os.WriteFile(selinuxEnforcePath, []byte("0\n"), 0644); err != nil {
+		t.Fatalf("Failed to write mock enforce file: %v", err)
+	}
+	apparmorEnabledPath = filepath.Join(tmpDir, "missing")
+
+	modules = getSecurityModules()
+	if modules.SELinux != "permissive" {
+		t.Errorf("Expected SELinux permissive, got %q", modules.SELinux)
+	}
+	if modules.AppArmor != "not available" {
+		t.Errorf("Expected AppArmor not available, got %q", modules.AppArmor)
+	}
+	if len(modules.Notes) != 0 {
+		t.Errorf("Expected no notes, got %v", modules.Notes)
+	}
+}
+
+// TestOptionsFromFlags validates that invocation options are read from the command's flags.
+func TestOptionsFromFlags(t *testing.T) {
+	if opts := optionsFromFlags(nil); opts.format != "yaml" || opts.noSortConfig || opts.units != unitsBinary {
+		t.Errorf("Expected default options for nil command, got %+v", opts)
+	}
+
+	cmd := &cobra.Command{}
+	cmd.Flags().String("format", "yaml", "")
+	cmd.Flags().Bool("no-sort-config", false, "")
+	cmd.Flags().String("units", unitsBinary, "")
+	if err := cmd.Flags().Parse([]string{"--format", "json", "--no-sort-config", "--units", "raw"}); err != nil {
+		t.Fatalf("Failed to parse flags: %v", err)
+	}
+
+	if opts := optionsFromFlags(cmd); opts.format != "json" || !opts.noSortConfig || opts.units != unitsRaw {
+		t.Errorf("Expected options from flags, got %+v", opts)
+	}
 }
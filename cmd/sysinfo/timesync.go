@@ -0,0 +1,143 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysinfo
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// runCommand executes a command and returns its standard output.
+// It abstracts command execution, making command-based collectors
+// mockable during tests.
+var runCommand = func(name string, args ...string) ([]byte, error) {
+	return exec.Command(name, args...).Output()
+}
+
+// TimeSync represents the clock synchronization state of the host.
+// Clock skew across segment hosts causes subtle cluster problems, so an
+// unsynchronized clock is flagged with a warning.
+type TimeSync struct {
+	Source       string `json:"source" yaml:"source"`
+	Synchronized bool   `json:"synchronized" yaml:"synchronized"`
+	Offset       string `json:"offset,omitempty" yaml:"offset,omitempty"`
+	Warning      string `json:"warning,omitempty" yaml:"warning,omitempty"`
+}
+
+// getTimeSync collects clock synchronization status. The synchronized state
+// is read from timedatectl, falling back to chronyc and ntpq, and the current
+// offset from chronyc or ntpq. Source is "not available" when none of the
+// tools reports a state.
+func getTimeSync() *TimeSync {
+	ts := &TimeSync{Source: "not available"}
+
+	if output, err := runCommand("timedatectl", "status"); err == nil {
+		if synced, ok := parseTimedatectl(string(output)); ok {
+			ts.Source = "timedatectl"
+			ts.Synchronized = synced
+		}
+	}
+
+	if output, err := runCommand("chronyc", "tracking"); err == nil {
+		if synced, offset, ok := parseChronyTracking(string(output)); ok {
+			if ts.Source == "not available" {
+				ts.Source = "chronyc"
+				ts.Synchronized = synced
+			}
+			ts.Offset = offset
+		}
+	}
+
+	if ts.Offset == "" {
+		if output, err := runCommand("ntpq", "-p"); err == nil {
+			if synced, offset, ok := parseNtpqPeers(string(output)); ok {
+				if ts.Source == "not available" {
+					ts.Source = "ntpq"
+					ts.Synchronized = synced
+				}
+				ts.Offset = offset
+			}
+		}
+	}
+
+	switch {
+	case ts.Source == "not available":
+		ts.Warning = "clock synchronization status could not be determined (timedatectl, chronyc and ntpq unavailable)"
+	case !ts.Synchronized:
+		ts.Warning = "system clock is not synchronized; clock skew across segment hosts can cause cluster problems"
+	}
+	return ts
+}
+
+// parseTimedatectl extracts the synchronized state from 'timedatectl status'.
+// Both "System clock synchronized" and the older "NTP synchronized" labels
+// are recognized. The bool ok is false if neither label is present.
+func parseTimedatectl(output string) (synced bool, ok bool) {
+	for _, line := range strings.Split(output, "\n") {
+		key, value, found := strings.Cut(strings.TrimSpace(line), ":")
+		if !found {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "System clock synchronized", "NTP synchronized":
+			return strings.TrimSpace(value) == "yes", true
+		}
+	}
+	return false, false
+}
+
+// parseChronyTracking extracts the synchronized state and offset from
+// 'chronyc tracking'. The offset is signed: positive when the system clock
+// is fast of NTP time and negative when it is slow.
+func parseChronyTracking(output string) (synced bool, offset string, ok bool) {
+	for _, line := range strings.Split(output, "\n") {
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "Leap status":
+			ok = true
+			synced = value != "Not synchronised"
+		case "System time":
+			// e.g. "0.000012345 seconds slow of NTP time"
+			fields := strings.Fields(value)
+			if len(fields) >= 3 {
+				sign := "+"
+				if fields[2] == "slow" {
+					sign = "-"
+				}
+				offset = sign + fields[0] + " s"
+			}
+		}
+	}
+	return synced, offset, ok
+}
+
+// parseNtpqPeers extracts the synchronized state and offset from 'ntpq -p'.
+// The clock is synchronized when a peer is selected as system peer (marked
+// with '*'); the offset is that peer's offset in milliseconds.
+func parseNtpqPeers(output string) (synced bool, offset string, ok bool) {
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && fields[0] == "remote" {
+			ok = true
+			continue
+		}
+		if strings.HasPrefix(line, "*") && len(fields) >= 9 {
+			return true, fields[8] + " ms", true
+		}
+	}
+	return false, "", ok
+}
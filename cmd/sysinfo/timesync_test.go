@@ -0,0 +1,132 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysinfo
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const (
+	timedatectlSynced = `               Local time: Sat 2024-11-30 10:00:00 UTC
+           Universal time: Sat 2024-11-30 10:00:00 UTC
+                 RTC time: Sat 2024-11-30 10:00:00
+                Time zone: UTC (UTC, +0000)
+System clock synchronized: yes
+              NTP service: active
+          RTC in local TZ: no
+`
+	timedatectlUnsynced = `System clock synchronized: no
+              NTP service: inactive
+`
+	chronyTracking = `Reference ID    : A9FEA97B (169.254.169.123)
+Stratum         : 4
+System time     : 0.000012345 seconds slow of NTP time
+Last offset     : -0.000002000 seconds
+Leap status     : Normal
+`
+	ntpqPeers = `     remote           refid      st t when poll reach   delay   offset  jitter
+==============================================================================
+*ntp1.example.co .GPS.            1 u   33   64  377    0.512    0.123   0.045
++ntp2.example.co .GPS.            1 u   35   64  377    0.601   -0.210   0.050
+`
+)
+
+// mockCommands replaces runCommand with canned output keyed by the command
+// line. Commands without canned output fail as if not installed.
+func mockCommands(t *testing.T, outputs map[string]string) {
+	t.Helper()
+	original := runCommand
+	t.Cleanup(func() { runCommand = original })
+	runCommand = func(name string, args ...string) ([]byte, error) {
+		if output, ok := outputs[strings.Join(append([]string{name}, args...), " ")]; ok {
+			return []byte(output), nil
+		}
+		return nil, errors.New("executable file not found in $PATH")
+	}
+}
+
+// TestGetTimeSync validates time sync collection from each source and its fallbacks.
+func TestGetTimeSync(t *testing.T) {
+	tests := []struct {
+		name         string
+		outputs      map[string]string
+		source       string
+		synchronized bool
+		offset       string
+		warning      bool
+	}{
+		{
+			name:         "timedatectl with chrony offset",
+			outputs:      map[string]string{"timedatectl status": timedatectlSynced, "chronyc tracking": chronyTracking},
+			source:       "timedatectl",
+			synchronized: true,
+			offset:       "-0.000012345 s",
+		},
+		{
+			name:    "timedatectl unsynchronized",
+			outputs: map[string]string{"timedatectl status": timedatectlUnsynced},
+			source:  "timedatectl",
+			warning: true,
+		},
+		{
+			name:         "chronyc fallback",
+			outputs:      map[string]string{"chronyc tracking": chronyTracking},
+			source:       "chronyc",
+			synchronized: true,
+			offset:       "-0.000012345 s",
+		},
+		{
+			name:         "ntpq fallback",
+			outputs:      map[string]string{"ntpq -p": ntpqPeers},
+			source:       "ntpq",
+			synchronized: true,
+			offset:       "0.123 ms",
+		},
+		{
+			name: "chronyc not synchronised",
+			outputs: map[string]string{"chronyc tracking": strings.Replace(chronyTracking,
+				"Leap status     : Normal", "Leap status     : Not synchronised", 1)},
+			source:  "chronyc",
+			offset:  "-0.000012345 s",
+			warning: true,
+		},
+		{
+			name:    "no tools available",
+			outputs: map[string]string{},
+			source:  "not available",
+			warning: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCommands(t, tt.outputs)
+
+			ts := getTimeSync()
+			if ts.Source != tt.source {
+				t.Errorf("Expected source %q, got %q", tt.source, ts.Source)
+			}
+			if ts.Synchronized != tt.synchronized {
+				t.Errorf("Expected synchronized %v, got %v", tt.synchronized, ts.Synchronized)
+			}
+			if ts.Offset != tt.offset {
+				t.Errorf("Expected offset %q, got %q", tt.offset, ts.Offset)
+			}
+			if (ts.Warning != "") != tt.warning {
+				t.Errorf("Expected warning: %v, got %q", tt.warning, ts.Warning)
+			}
+		})
+	}
+}
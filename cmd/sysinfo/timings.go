@@ -0,0 +1,65 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysinfo
+
+import (
+	"sync"
+	"time"
+)
+
+// stepTimer records the wall-clock duration of collection steps for
+// --timings. It is safe for concurrent use by the collector goroutines.
+// A nil *stepTimer records nothing, so collectors can be timed
+// unconditionally.
+type stepTimer struct {
+	mu        sync.Mutex
+	durations map[string]time.Duration
+}
+
+// newStepTimer returns a timer when enabled is set, or nil otherwise.
+func newStepTimer(enabled bool) *stepTimer {
+	if !enabled {
+		return nil
+	}
+	return &stepTimer{durations: make(map[string]time.Duration)}
+}
+
+// track starts timing step and returns a function that records its
+// duration, for use as 'defer timer.track("step")()'.
+func (t *stepTimer) track(step string) func() {
+	if t == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		elapsed := time.Since(start)
+		t.mu.Lock()
+		t.durations[step] += elapsed
+		t.mu.Unlock()
+	}
+}
+
+// report returns the recorded durations rounded to microseconds, or nil
+// when timing is disabled.
+func (t *stepTimer) report() map[string]string {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	report := make(map[string]string, len(t.durations))
+	for step, duration := range t.durations {
+		report[step] = duration.Round(time.Microsecond).String()
+	}
+	return report
+}
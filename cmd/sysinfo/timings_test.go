@@ -0,0 +1,56 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysinfo
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestStepTimer validates timing steps across goroutines and that a
+// disabled timer records nothing.
+func TestStepTimer(t *testing.T) {
+	if report := newStepTimer(false).report(); report != nil {
+		t.Errorf("disabled timer reported %v", report)
+	}
+	// Tracking with a disabled timer is a no-op
+	newStepTimer(false).track("os")()
+
+	timer := newStepTimer(true)
+	var wg sync.WaitGroup
+	for _, step := range []string{"os", "kernel", "kernel", "memory_stats"} {
+		wg.Add(1)
+		go func(step string) {
+			defer wg.Done()
+			defer timer.track(step)()
+			time.Sleep(time.Millisecond)
+		}(step)
+	}
+	wg.Wait()
+
+	report := timer.report()
+	if len(report) != 3 {
+		t.Fatalf("expected 3 steps, got %v", report)
+	}
+	for step, value := range report {
+		duration, err := time.ParseDuration(value)
+		if err != nil || duration < time.Millisecond {
+			t.Errorf("step %s: unexpected duration %q (%v)", step, value, err)
+		}
+	}
+	// Repeated steps accumulate
+	if kernel, _ := time.ParseDuration(report["kernel"]); kernel < 2*time.Millisecond {
+		t.Errorf("expected the kernel step to accumulate two runs, got %s", report["kernel"])
+	}
+}
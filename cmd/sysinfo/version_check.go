@@ -0,0 +1,51 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysinfo
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// versionRegex splits the output of postgres --version and --gp-version,
+// e.g. "postgres (Apache Cloudberry) 1.6.0 build commit:5b5e432", into
+// the program, the product, the version and the optional build identifier.
+var versionRegex = regexp.MustCompile(`^(\S+) \(([^)]+)\) (\S+)(?: build (.+))?$`)
+
+// checkVersionConsistency cross-checks the PostgreSQL and Cloudberry
+// version strings of a GPHOME. Both come from the same postgres binary, so
+// their program and product names must match, and so must their build
+// identifiers when both carry one. A mismatch points to a partially
+// upgraded or otherwise broken installation.
+//
+// Returns nil and a note when a version string is not recognized, and
+// false with a note naming the mismatch when the strings disagree.
+func checkVersionConsistency(postgresVersion, gpVersion string) (*bool, string) {
+	pg := versionRegex.FindStringSubmatch(postgresVersion)
+	gp := versionRegex.FindStringSubmatch(gpVersion)
+	if pg == nil || gp == nil {
+		return nil, fmt.Sprintf("cannot cross-check postgres_version %q and gp_version %q: unrecognized format", postgresVersion, gpVersion)
+	}
+
+	consistent := false
+	switch {
+	case pg[1] != gp[1]:
+		return &consistent, fmt.Sprintf("postgres_version is from program %q but gp_version from %q", pg[1], gp[1])
+	case pg[2] != gp[2]:
+		return &consistent, fmt.Sprintf("postgres_version is from %s but gp_version from %s; the installation may be partially upgraded", pg[2], gp[2])
+	case pg[4] != "" && gp[4] != "" && pg[4] != gp[4]:
+		return &consistent, fmt.Sprintf("postgres_version is from build %s but gp_version from build %s; the installation may be partially upgraded", pg[4], gp[4])
+	}
+	consistent = true
+	return &consistent, ""
+}
@@ -0,0 +1,94 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysinfo
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestCheckVersionConsistency validates the cross-check of the postgres and
+// Cloudberry version strings.
+func TestCheckVersionConsistency(t *testing.T) {
+	tests := []struct {
+		name            string
+		postgresVersion string
+		gpVersion       string
+		consistent      *bool
+		note            string
+	}{
+		{
+			name:            "same product",
+			postgresVersion: "postgres (Cloudberry Database) 14.4",
+			gpVersion:       "postgres (Cloudberry Database) 1.6.0 build 1",
+			consistent:      boolPtr(true),
+		},
+		{
+			name:            "same build",
+			postgresVersion: "postgres (Apache Cloudberry) 14.4 build commit:5b5e432",
+			gpVersion:       "postgres (Apache Cloudberry) 2.0.0 build commit:5b5e432",
+			consistent:      boolPtr(true),
+		},
+		{
+			name:            "different products",
+			postgresVersion: "postgres (Greenplum Database) 12.12",
+			gpVersion:       "postgres (Apache Cloudberry) 2.0.0 build commit:5b5e432",
+			consistent:      boolPtr(false),
+			note:            "from Greenplum Database but gp_version from Apache Cloudberry",
+		},
+		{
+			name:            "different builds",
+			postgresVersion: "postgres (Apache Cloudberry) 14.4 build commit:5b5e432",
+			gpVersion:       "postgres (Apache Cloudberry) 2.0.0 build commit:9f0c1d2",
+			consistent:      boolPtr(false),
+			note:            "from build commit:5b5e432 but gp_version from build commit:9f0c1d2",
+		},
+		{
+			name:            "different programs",
+			postgresVersion: "postgres (Apache Cloudberry) 14.4",
+			gpVersion:       "postmaster (Apache Cloudberry) 2.0.0",
+			consistent:      boolPtr(false),
+			note:            `program "postgres" but gp_version from "postmaster"`,
+		},
+		{
+			name:            "unrecognized",
+			postgresVersion: "postgres mock",
+			gpVersion:       "postgres (Apache Cloudberry) 2.0.0",
+			note:            "unrecognized format",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			consistent, note := checkVersionConsistency(tt.postgresVersion, tt.gpVersion)
+			if (consistent == nil) != (tt.consistent == nil) || (consistent != nil && *consistent != *tt.consistent) {
+				t.Errorf("Expected consistent %v, got %v", fmtBoolPtr(tt.consistent), fmtBoolPtr(consistent))
+			}
+			if (tt.note == "") != (note == "") || !strings.Contains(note, tt.note) {
+				t.Errorf("Expected note containing %q, got %q", tt.note, note)
+			}
+		})
+	}
+}
+
+func boolPtr(b bool) *bool { return &b }
+
+func fmtBoolPtr(b *bool) string {
+	if b == nil {
+		return "nil"
+	}
+	if *b {
+		return "true"
+	}
+	return "false"
+}
@@ -4,6 +4,7 @@ go 1.22.6
 
 require (
 	github.com/spf13/cobra v1.8.1
+	golang.org/x/sync v0.11.0
 	gopkg.in/yaml.v2 v2.4.0
 )
 
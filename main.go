@@ -35,6 +35,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -45,13 +46,25 @@ import (
 // In production, it points to os.Exit.
 var exitFunc = os.Exit
 
+// exitCode returns the exit code for a failed command: the code carried
+// by the first cmd.ExitError in the chain, or 1 when no specific (non-zero)
+// code is set. The exit status of a failed child process, such as gdb, is
+// not cbtoolbox's exit code.
+func exitCode(err error) int {
+	var exitErr *cmd.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+		return exitErr.ExitCode()
+	}
+	return 1
+}
+
 // run executes the root command and handles error propagation.
 // It returns an error if command execution fails, after ensuring
 // proper exit code is set through exitFunc.
 func run() error {
 	err := cmd.Execute()
 	if err != nil {
-		exitFunc(1)
+		exitFunc(exitCode(err))
 		return err
 	}
 	return nil
@@ -13,9 +13,14 @@
 package main
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"os/exec"
 	"strings"
 	"testing"
+
+	"github.com/edespino/cbtoolbox/cmd"
 )
 
 // TestRun validates the main command execution path of cbtoolbox.
@@ -101,3 +106,37 @@ func TestRun(t *testing.T) {
 		})
 	}
 }
+
+// TestExitCode validates that exit codes carried by errors reach exitFunc,
+// and that errors without a specific code exit with 1.
+func TestExitCode(t *testing.T) {
+	base := errors.New("collection failed")
+	childErr := exec.Command("sh", "-c", "exit 5").Run()
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "plain error", err: base, want: 1},
+		{name: "exit error", err: cmd.WithExitCode(base, 3), want: 3},
+		{name: "wrapped exit error", err: fmt.Errorf("sysinfo: %w", cmd.WithExitCode(base, 4)), want: 4},
+		{name: "zero code", err: cmd.WithExitCode(base, 0), want: 1},
+		{name: "child process exit status", err: fmt.Errorf("gdb failed: %w", childErr), want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCode(tt.err); got != tt.want {
+				t.Errorf("exitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	if cmd.WithExitCode(nil, 3) != nil {
+		t.Error("Expected WithExitCode(nil) to return nil")
+	}
+	if err := cmd.WithExitCode(base, 3); !errors.Is(err, base) || err.Error() != base.Error() {
+		t.Errorf("Expected exit error to wrap %v, got %v", base, err)
+	}
+}